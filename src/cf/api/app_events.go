@@ -14,8 +14,25 @@ import (
 
 type AppEventsRepository interface {
 	ListEvents(appGuid string, cb func(models.EventFields) bool) net.ApiResponse
+	ListEventsWithFilter(appGuid string, filter EventFilter, cb func(models.EventFields) bool) net.ApiResponse
 }
 
+// EventFilter selects events by their time and type, newest first when
+// Descending is set. Zero values do not filter anything.
+type EventFilter struct {
+	Since      time.Time
+	Until      time.Time
+	Type       string
+	Descending bool
+	PageSize   int
+}
+
+// the first API version with the /v2/events endpoint, older ones only list
+// the crashes of an app
+const EVENTS_ENDPOINT_MIN_API_VERSION = "2.1.0"
+
+const APP_CRASH_EVENT_TYPE = "app.crash"
+
 type CloudControllerAppEventsRepository struct {
 	config  configuration.Reader
 	gateway net.Gateway
@@ -28,33 +45,90 @@ func NewCloudControllerAppEventsRepository(config configuration.Reader, gateway
 }
 
 func (repo CloudControllerAppEventsRepository) ListEvents(appGuid string, cb func(models.EventFields) bool) net.ApiResponse {
+	return repo.ListEventsWithFilter(appGuid, EventFilter{}, cb)
+}
+
+func (repo CloudControllerAppEventsRepository) ListEventsWithFilter(appGuid string, filter EventFilter, cb func(models.EventFields) bool) net.ApiResponse {
+	if !repo.config.IsMinApiVersion(EVENTS_ENDPOINT_MIN_API_VERSION) {
+		return repo.listCrashEvents(appGuid, filter, cb)
+	}
+
+	queries := []string{"actee:" + appGuid}
+	if !filter.Since.IsZero() {
+		queries = append(queries, "timestamp>="+filter.Since.UTC().Format(EVENT_FILTER_TIMESTAMP_FORMAT))
+	}
+	if !filter.Until.IsZero() {
+		queries = append(queries, "timestamp<="+filter.Until.UTC().Format(EVENT_FILTER_TIMESTAMP_FORMAT))
+	}
+	if filter.Type != "" {
+		queries = append(queries, "type:"+filter.Type)
+	}
+
+	path := fmt.Sprintf("/v2/events?q=%s", url.QueryEscape(strings.Join(queries, ";")))
+	if filter.Descending {
+		path += "&order-direction=desc"
+	}
+	if filter.PageSize > 0 {
+		path += fmt.Sprintf("&results-per-page=%d", filter.PageSize)
+	}
+
 	apiResponse := repo.gateway.ListPaginatedResources(
 		repo.config.ApiEndpoint(),
 		repo.config.AccessToken(),
-		fmt.Sprintf("/v2/events?q=%s", url.QueryEscape(fmt.Sprintf("actee:%s", appGuid))),
+		path,
 		EventResourceNewV2{},
 		func(resource interface{}) bool {
 			return cb(resource.(EventResourceNewV2).ToFields())
 		})
 
-	// FIXME: needs semantic versioning
+	// the API version in the config can be missing or stale, so an old cloud
+	// controller is still recognized by the missing endpoint
 	if apiResponse.IsNotFound() {
-		apiResponse = repo.gateway.ListPaginatedResources(
-			repo.config.ApiEndpoint(),
-			repo.config.AccessToken(),
-			fmt.Sprintf("/v2/apps/%s/events", appGuid),
-			EventResourceOldV2{},
-			func(resource interface{}) bool {
-				return cb(resource.(EventResourceOldV2).ToFields())
-			})
+		apiResponse = repo.listCrashEvents(appGuid, filter, cb)
 	}
 
 	return apiResponse
 }
 
+// listCrashEvents filters the crashes of the app on the client, as the old
+// endpoint cannot filter them.
+func (repo CloudControllerAppEventsRepository) listCrashEvents(appGuid string, filter EventFilter, cb func(models.EventFields) bool) net.ApiResponse {
+	if filter.Type != "" && filter.Type != APP_CRASH_EVENT_TYPE {
+		return net.NewSuccessfulApiResponse()
+	}
+
+	events := []models.EventFields{}
+	apiResponse := repo.gateway.ListPaginatedResources(
+		repo.config.ApiEndpoint(),
+		repo.config.AccessToken(),
+		fmt.Sprintf("/v2/apps/%s/events", appGuid),
+		EventResourceOldV2{},
+		func(resource interface{}) bool {
+			event := resource.(EventResourceOldV2).ToFields()
+			if !filter.Since.IsZero() && event.Timestamp.Before(filter.Since) {
+				return true
+			}
+			if !filter.Until.IsZero() && event.Timestamp.After(filter.Until) {
+				return true
+			}
+			if filter.Descending {
+				events = append([]models.EventFields{event}, events...)
+				return true
+			}
+			return cb(event)
+		})
+
+	for _, event := range events {
+		if !cb(event) {
+			break
+		}
+	}
+	return apiResponse
+}
+
 const APP_EVENT_TIMESTAMP_FORMAT = "2006-01-02T15:04:05-07:00"
+const EVENT_FILTER_TIMESTAMP_FORMAT = "2006-01-02T15:04:05Z"
 
-// FIXME: needs semantic versioning
 type EventResourceOldV2 struct {
 	Resource
 	Entity struct {
@@ -66,11 +140,13 @@ type EventResourceOldV2 struct {
 }
 
 func (resource EventResourceOldV2) ToFields() models.EventFields {
+	index := resource.Entity.InstanceIndex
 	return models.EventFields{
-		Guid:        resource.Metadata.Guid,
-		Name:        "app crashed",
-		Timestamp:   resource.Entity.Timestamp,
-		Description: fmt.Sprintf("instance: %d, reason: %s, exit_status: %s", resource.Entity.InstanceIndex, resource.Entity.ExitDescription, strconv.Itoa(resource.Entity.ExitStatus)),
+		Guid:          resource.Metadata.Guid,
+		Name:          "app crashed",
+		Timestamp:     resource.Entity.Timestamp,
+		Description:   fmt.Sprintf("instance: %d, reason: %s, exit_status: %s", resource.Entity.InstanceIndex, resource.Entity.ExitDescription, strconv.Itoa(resource.Entity.ExitStatus)),
+		InstanceIndex: &index,
 	}
 }
 
@@ -103,12 +179,18 @@ func (resource EventResourceNewV2) ToFields() models.EventFields {
 		metadata = generic.NewMap(metadata.Get("request"))
 	}
 
-	return models.EventFields{
+	fields := models.EventFields{
 		Guid:        resource.Metadata.Guid,
 		Name:        resource.Entity.Type,
 		Timestamp:   resource.Entity.Timestamp,
 		Description: formatDescription(metadata, KNOWN_METADATA_KEYS),
 	}
+
+	if index, ok := metadata.Get("index").(float64); ok {
+		instanceIndex := int(index)
+		fields.InstanceIndex = &instanceIndex
+	}
+	return fields
 }
 
 func formatDescription(metadata generic.Map, keys []string) string {
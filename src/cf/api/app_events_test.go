@@ -74,14 +74,6 @@ var oldV2NotFoundRequest = testnet.TestRequest{
 	},
 }
 
-var newV2NotFoundRequest = testnet.TestRequest{
-	Method: "GET",
-	Path:   "/v2/events?q=actee%3Amy-app-guid",
-	Response: testnet.TestResponse{
-		Status: http.StatusNotFound,
-	},
-}
-
 type eventTestDependencies struct {
 	server  *httptest.Server
 	handler *testnet.TestHandler
@@ -90,11 +82,16 @@ type eventTestDependencies struct {
 }
 
 func setupEventTest(requests []testnet.TestRequest) (deps eventTestDependencies) {
+	return setupEventTestWithApiVersion(requests, "")
+}
+
+func setupEventTestWithApiVersion(requests []testnet.TestRequest, apiVersion string) (deps eventTestDependencies) {
 	deps.server, deps.handler = testnet.NewTLSServer(requests)
 
 	configRepo := testconfig.NewRepository()
 	configRepo.SetApiEndpoint(deps.server.URL)
 	configRepo.SetAccessToken("BEARER my_access_token")
+	configRepo.SetApiVersion(apiVersion)
 
 	deps.config = configRepo
 	deps.gateway = net.NewCloudControllerGateway()
@@ -107,26 +104,28 @@ func teardownEventTest(deps eventTestDependencies) {
 }
 
 var _ = Describe("App Events Repo", func() {
-	It("TestListOldV2EventsWhenNewV2ApiNotFound", func() {
-		deps := setupEventTest([]testnet.TestRequest{
-			newV2NotFoundRequest,
+	It("TestListOldV2EventsWhenApiIsOlderThanEventsEndpoint", func() {
+		deps := setupEventTestWithApiVersion([]testnet.TestRequest{
 			firstPageOldV2EventsRequest,
 			secondPageOldV2EventsRequest,
-		})
+		}, "2.0.0")
 		defer teardownEventTest(deps)
 
 		repo := NewCloudControllerAppEventsRepository(deps.config, deps.gateway)
 
+		firstIndex, secondIndex := 1, 2
 		expectedEvents := []models.EventFields{
 			models.EventFields{
-				Name:        "app crashed",
-				Description: "instance: 1, reason: app instance exited, exit_status: 1",
-				Timestamp:   testtime.MustParse(APP_EVENT_TIMESTAMP_FORMAT, "2013-10-07T16:51:07+00:00"),
+				Name:          "app crashed",
+				Description:   "instance: 1, reason: app instance exited, exit_status: 1",
+				Timestamp:     testtime.MustParse(APP_EVENT_TIMESTAMP_FORMAT, "2013-10-07T16:51:07+00:00"),
+				InstanceIndex: &firstIndex,
 			},
 			models.EventFields{
-				Name:        "app crashed",
-				Description: "instance: 2, reason: app instance was stopped, exit_status: 2",
-				Timestamp:   testtime.MustParse(APP_EVENT_TIMESTAMP_FORMAT, "2013-10-07T17:51:07+00:00"),
+				Name:          "app crashed",
+				Description:   "instance: 2, reason: app instance was stopped, exit_status: 2",
+				Timestamp:     testtime.MustParse(APP_EVENT_TIMESTAMP_FORMAT, "2013-10-07T17:51:07+00:00"),
+				InstanceIndex: &secondIndex,
 			},
 		}
 
@@ -223,11 +222,10 @@ var _ = Describe("App Events Repo", func() {
 	})
 
 	It("TestListOldV2EventsApiError", func() {
-		deps := setupEventTest([]testnet.TestRequest{
-			newV2NotFoundRequest,
+		deps := setupEventTestWithApiVersion([]testnet.TestRequest{
 			firstPageOldV2EventsRequest,
 			oldV2NotFoundRequest,
-		})
+		}, "2.0.0")
 		defer teardownEventTest(deps)
 
 		repo := NewCloudControllerAppEventsRepository(deps.config, deps.gateway)
@@ -241,11 +239,13 @@ var _ = Describe("App Events Repo", func() {
 		firstExpectedTime, err := time.Parse(APP_EVENT_TIMESTAMP_FORMAT, "2013-10-07T16:51:07+00:00")
 		Expect(err).NotTo(HaveOccurred())
 
+		instanceIndex := 1
 		expectedEvents := []models.EventFields{
 			models.EventFields{
-				Name:        "app crashed",
-				Description: "instance: 1, reason: app instance exited, exit_status: 1",
-				Timestamp:   firstExpectedTime,
+				Name:          "app crashed",
+				Description:   "instance: 1, reason: app instance exited, exit_status: 1",
+				Timestamp:     firstExpectedTime,
+				InstanceIndex: &instanceIndex,
 			},
 		}
 
@@ -254,6 +254,103 @@ var _ = Describe("App Events Repo", func() {
 		Expect(deps.handler.AllRequestsCalled()).To(BeTrue())
 	})
 
+	It("TestListEventsFallsBackToOldV2EventsWhenEventsEndpointIsNotFound", func() {
+		deps := setupEventTest([]testnet.TestRequest{
+			testnet.TestRequest{
+				Method:   "GET",
+				Path:     "/v2/events?q=actee%3Amy-app-guid",
+				Response: testnet.TestResponse{Status: http.StatusNotFound},
+			},
+			firstPageOldV2EventsRequest,
+			secondPageOldV2EventsRequest,
+		})
+		defer teardownEventTest(deps)
+
+		repo := NewCloudControllerAppEventsRepository(deps.config, deps.gateway)
+
+		list := []models.EventFields{}
+		apiResponse := repo.ListEvents("my-app-guid", func(e models.EventFields) bool {
+			list = append(list, e)
+			return true
+		})
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(len(list)).To(Equal(2))
+		Expect(list[0].Name).To(Equal("app crashed"))
+		Expect(deps.handler.AllRequestsCalled()).To(BeTrue())
+	})
+
+	It("TestListEventsWithFilterSendsQueryFilters", func() {
+		deps := setupEventTest([]testnet.TestRequest{
+			testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/events?q=actee%3Amy-app-guid%3Btimestamp%3E%3D2014-01-21T00%3A00%3A00Z%3Btimestamp%3C%3D2014-01-22T12%3A00%3A00Z%3Btype%3Aapp.crash&order-direction=desc&results-per-page=5",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body:   `{"total_results": 0, "total_pages": 1, "next_url": "", "resources": []}`,
+				},
+			},
+		})
+		defer teardownEventTest(deps)
+
+		repo := NewCloudControllerAppEventsRepository(deps.config, deps.gateway)
+
+		filter := EventFilter{
+			Since:      testtime.MustParse(APP_EVENT_TIMESTAMP_FORMAT, "2014-01-21T01:00:00+01:00"),
+			Until:      testtime.MustParse(APP_EVENT_TIMESTAMP_FORMAT, "2014-01-22T12:00:00+00:00"),
+			Type:       "app.crash",
+			Descending: true,
+			PageSize:   5,
+		}
+		apiResponse := repo.ListEventsWithFilter("my-app-guid", filter, func(e models.EventFields) bool {
+			return true
+		})
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(deps.handler.AllRequestsCalled()).To(BeTrue())
+	})
+
+	It("TestListEventsWithFilterFiltersOldV2EventsOnTheClient", func() {
+		deps := setupEventTestWithApiVersion([]testnet.TestRequest{
+			firstPageOldV2EventsRequest,
+			secondPageOldV2EventsRequest,
+		}, "2.0.0")
+		defer teardownEventTest(deps)
+
+		repo := NewCloudControllerAppEventsRepository(deps.config, deps.gateway)
+
+		filter := EventFilter{
+			Since:      testtime.MustParse(APP_EVENT_TIMESTAMP_FORMAT, "2013-10-07T17:00:00+00:00"),
+			Type:       "app.crash",
+			Descending: true,
+		}
+		list := []models.EventFields{}
+		apiResponse := repo.ListEventsWithFilter("my-app-guid", filter, func(e models.EventFields) bool {
+			list = append(list, e)
+			return true
+		})
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(len(list)).To(Equal(1))
+		Expect(list[0].Description).To(ContainSubstring("instance: 2"))
+	})
+
+	It("TestListEventsWithFilterSkipsOldV2EventsOfOtherTypes", func() {
+		deps := setupEventTestWithApiVersion([]testnet.TestRequest{}, "2.0.0")
+		defer teardownEventTest(deps)
+
+		repo := NewCloudControllerAppEventsRepository(deps.config, deps.gateway)
+
+		called := false
+		apiResponse := repo.ListEventsWithFilter("my-app-guid", EventFilter{Type: "audit.app.update"}, func(e models.EventFields) bool {
+			called = true
+			return true
+		})
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(called).To(BeFalse())
+	})
+
 	It("TestUnmarshalNewCrashEvent", func() {
 		resource := new(EventResourceNewV2)
 		err := json.Unmarshal([]byte(`
@@ -282,6 +379,7 @@ var _ = Describe("App Events Repo", func() {
 		Expect(eventFields.Name).To(Equal("app.crash"))
 		Expect(eventFields.Timestamp).To(Equal(testtime.MustParse(APP_EVENT_TIMESTAMP_FORMAT, "2013-10-07T16:51:07+00:00")))
 		Expect(eventFields.Description).To(Equal(`index: 3, reason: CRASHED, exit_description: unknown, exit_status: -1`))
+		Expect(*eventFields.InstanceIndex).To(Equal(3))
 	})
 
 	It("TestUnmarshalUpdateAppEvent", func() {
@@ -4,10 +4,16 @@ import (
 	"cf/configuration"
 	"cf/net"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 )
 
 type AppFilesRepository interface {
 	ListFiles(appGuid, path string) (files string, apiResponse net.ApiResponse)
+	ListInstanceFiles(appGuid string, instance int, path string) (files string, apiResponse net.ApiResponse)
+	DownloadFile(appGuid string, instance int, path string, destination io.Writer) (apiResponse net.ApiResponse)
+	ReadFileFrom(appGuid string, instance int, path string, offset int64) (content []byte, fileSize int64, apiResponse net.ApiResponse)
 }
 
 type CloudControllerAppFilesRepository struct {
@@ -22,8 +28,11 @@ func NewCloudControllerAppFilesRepository(config configuration.Reader, gateway n
 }
 
 func (repo CloudControllerAppFilesRepository) ListFiles(appGuid, path string) (files string, apiResponse net.ApiResponse) {
-	url := fmt.Sprintf("%s/v2/apps/%s/instances/0/files/%s", repo.config.ApiEndpoint(), appGuid, path)
-	request, apiResponse := repo.gateway.NewRequest("GET", url, repo.config.AccessToken(), nil)
+	return repo.ListInstanceFiles(appGuid, 0, path)
+}
+
+func (repo CloudControllerAppFilesRepository) ListInstanceFiles(appGuid string, instance int, path string) (files string, apiResponse net.ApiResponse) {
+	request, apiResponse := repo.newFilesRequest(appGuid, instance, path)
 	if apiResponse.IsNotSuccessful() {
 		return
 	}
@@ -31,3 +40,87 @@ func (repo CloudControllerAppFilesRepository) ListFiles(appGuid, path string) (f
 	files, _, apiResponse = repo.gateway.PerformRequestForTextResponse(request)
 	return
 }
+
+func (repo CloudControllerAppFilesRepository) DownloadFile(appGuid string, instance int, path string, destination io.Writer) (apiResponse net.ApiResponse) {
+	request, apiResponse := repo.newFilesRequest(appGuid, instance, path)
+	if apiResponse.IsNotSuccessful() {
+		return
+	}
+
+	rawResponse, apiResponse := repo.gateway.PerformRequestForStreamingResponse(request)
+	if apiResponse.IsNotSuccessful() {
+		return
+	}
+	defer rawResponse.Body.Close()
+
+	_, err := io.Copy(destination, rawResponse.Body)
+	if err != nil {
+		apiResponse = net.NewApiResponseWithError("Error downloading file", err)
+	}
+	return
+}
+
+// ReadFileFrom reads a file from offset to its end with a range request, or
+// its last -offset bytes when offset is negative. It also returns the size
+// of the whole file, which is smaller than offset when the file has been
+// truncated since.
+func (repo CloudControllerAppFilesRepository) ReadFileFrom(appGuid string, instance int, path string, offset int64) (content []byte, fileSize int64, apiResponse net.ApiResponse) {
+	request, apiResponse := repo.newFilesRequest(appGuid, instance, path)
+	if apiResponse.IsNotSuccessful() {
+		return
+	}
+
+	// asking for the byte before offset as well makes an unchanged file answer
+	// with that byte, so only a file shorter than offset is not satisfiable
+	switch {
+	case offset > 0:
+		request.HttpReq.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset-1))
+	case offset < 0:
+		request.HttpReq.Header.Set("Range", fmt.Sprintf("bytes=%d", offset))
+	}
+
+	rawResponse, apiResponse := repo.gateway.PerformRequestForResponse(request)
+	if apiResponse.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		apiResponse = net.NewSuccessfulApiResponse()
+		return
+	}
+	if apiResponse.IsNotSuccessful() {
+		return
+	}
+	defer rawResponse.Body.Close()
+
+	body, err := ioutil.ReadAll(rawResponse.Body)
+	if err != nil {
+		apiResponse = net.NewApiResponseWithError("Error reading file", err)
+		return
+	}
+
+	// servers that do not support ranges answer with the whole file
+	var bodyStart int64
+	fileSize = int64(len(body))
+	if rawResponse.StatusCode == http.StatusPartialContent {
+		var bodyEnd int64
+		_, err = fmt.Sscanf(rawResponse.Header.Get("Content-Range"), "bytes %d-%d/%d", &bodyStart, &bodyEnd, &fileSize)
+		if err != nil {
+			apiResponse = net.NewApiResponseWithError("Invalid Content-Range in response", err)
+			return
+		}
+	}
+
+	start := offset
+	if offset < 0 {
+		start = fileSize + offset
+	}
+	if start < bodyStart {
+		start = bodyStart
+	}
+	if start-bodyStart < int64(len(body)) {
+		content = body[start-bodyStart:]
+	}
+	return
+}
+
+func (repo CloudControllerAppFilesRepository) newFilesRequest(appGuid string, instance int, path string) (request *net.Request, apiResponse net.ApiResponse) {
+	url := fmt.Sprintf("%s/v2/apps/%s/instances/%d/files/%s", repo.config.ApiEndpoint(), appGuid, instance, path)
+	return repo.gateway.NewRequest("GET", url, repo.config.AccessToken(), nil)
+}
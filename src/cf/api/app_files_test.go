@@ -1,6 +1,7 @@
 package api_test
 
 import (
+	"bytes"
 	. "cf/api"
 	"cf/net"
 	"fmt"
@@ -62,4 +63,116 @@ var _ = Describe("AppFilesRepository", func() {
 		Expect(err.IsNotSuccessful()).To(BeFalse())
 		Expect(list).To(Equal(expectedResponse))
 	})
+
+	It("downloads a file of an instance as bytes", func() {
+		content := []byte{0x1f, 0x8b, 0x00, 0xff, '\n', 0x00}
+
+		downloadEndpoint := func(writer http.ResponseWriter, request *http.Request) {
+			if request.URL.Path != "/some/heap.dump" {
+				writer.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			writer.WriteHeader(http.StatusOK)
+			writer.Write(content)
+		}
+
+		downloadServer := httptest.NewTLSServer(http.HandlerFunc(downloadEndpoint))
+		defer downloadServer.Close()
+
+		req := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+			Method: "GET",
+			Path:   "/v2/apps/my-app-guid/instances/2/files/some/heap.dump",
+			Response: testnet.TestResponse{
+				Status: http.StatusTemporaryRedirect,
+				Header: http.Header{
+					"Location": {fmt.Sprintf("%s/some/heap.dump", downloadServer.URL)},
+				},
+			},
+		})
+
+		redirectServer, handler := testnet.NewTLSServer([]testnet.TestRequest{req})
+		defer redirectServer.Close()
+
+		configRepo := testconfig.NewRepositoryWithDefaults()
+		configRepo.SetApiEndpoint(redirectServer.URL)
+
+		repo := NewCloudControllerAppFilesRepository(configRepo, net.NewCloudControllerGateway())
+
+		buffer := new(bytes.Buffer)
+		apiResponse := repo.DownloadFile("my-app-guid", 2, "some/heap.dump", buffer)
+
+		Expect(handler.AllRequestsCalled()).To(BeTrue())
+		Expect(apiResponse.IsNotSuccessful()).To(BeFalse())
+		Expect(buffer.Bytes()).To(Equal(content))
+	})
+
+	Describe("reading a file from an offset", func() {
+		callReadFileFrom := func(offset int64, response testnet.TestResponse, expectedRange string) (content []byte, fileSize int64, apiResponse net.ApiResponse) {
+			req := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/apps/my-app-guid/instances/1/files/logs/app.log",
+				Matcher: func(request *http.Request) {
+					Expect(request.Header.Get("Range")).To(Equal(expectedRange))
+				},
+				Response: response,
+			})
+
+			ts, handler := testnet.NewTLSServer([]testnet.TestRequest{req})
+			defer ts.Close()
+
+			configRepo := testconfig.NewRepositoryWithDefaults()
+			configRepo.SetApiEndpoint(ts.URL)
+			repo := NewCloudControllerAppFilesRepository(configRepo, net.NewCloudControllerGateway())
+
+			content, fileSize, apiResponse = repo.ReadFileFrom("my-app-guid", 1, "logs/app.log", offset)
+			Expect(handler.AllRequestsCalled()).To(BeTrue())
+			return
+		}
+
+		It("returns the bytes after the offset and the size of the file", func() {
+			content, fileSize, apiResponse := callReadFileFrom(100, testnet.TestResponse{
+				Status: http.StatusPartialContent,
+				Header: http.Header{"Content-Range": {"bytes 99-105/106"}},
+				Body:   "xhello",
+			}, "bytes=99-")
+
+			Expect(apiResponse.IsNotSuccessful()).To(BeFalse())
+			Expect(string(content)).To(Equal("hello\n"))
+			Expect(fileSize).To(Equal(int64(106)))
+		})
+
+		It("returns the last bytes of the file for a negative offset", func() {
+			content, fileSize, apiResponse := callReadFileFrom(-4, testnet.TestResponse{
+				Status: http.StatusPartialContent,
+				Header: http.Header{"Content-Range": {"bytes 16-19/20"}},
+				Body:   "end",
+			}, "bytes=-4")
+
+			Expect(apiResponse.IsNotSuccessful()).To(BeFalse())
+			Expect(string(content)).To(Equal("end\n"))
+			Expect(fileSize).To(Equal(int64(20)))
+		})
+
+		It("cuts the whole file when the server ignores the range", func() {
+			content, fileSize, apiResponse := callReadFileFrom(4, testnet.TestResponse{
+				Status: http.StatusOK,
+				Body:   "skipped",
+			}, "bytes=3-")
+
+			Expect(apiResponse.IsNotSuccessful()).To(BeFalse())
+			Expect(string(content)).To(Equal("ped\n"))
+			Expect(fileSize).To(Equal(int64(8)))
+		})
+
+		It("returns an empty file when the file is shorter than the offset", func() {
+			content, fileSize, apiResponse := callReadFileFrom(100, testnet.TestResponse{
+				Status: http.StatusRequestedRangeNotSatisfiable,
+			}, "bytes=99-")
+
+			Expect(apiResponse.IsNotSuccessful()).To(BeFalse())
+			Expect(content).To(BeEmpty())
+			Expect(fileSize).To(Equal(int64(0)))
+		})
+	})
 })
@@ -33,6 +33,7 @@ type InstanceStatsApiResponse struct {
 
 type AppInstancesRepository interface {
 	GetInstances(appGuid string) (instances []models.AppInstanceFields, apiResponse net.ApiResponse)
+	DeleteInstance(appGuid string, index int) (apiResponse net.ApiResponse)
 }
 
 type CloudControllerAppInstancesRepository struct {
@@ -76,6 +77,13 @@ func (repo CloudControllerAppInstancesRepository) GetInstances(appGuid string) (
 	return repo.updateInstancesWithStats(appGuid, instances)
 }
 
+// DeleteInstance stops one instance of an app, the cloud controller starts
+// a new one in its place
+func (repo CloudControllerAppInstancesRepository) DeleteInstance(appGuid string, index int) (apiResponse net.ApiResponse) {
+	path := fmt.Sprintf("%s/v2/apps/%s/instances/%d", repo.config.ApiEndpoint(), appGuid, index)
+	return repo.gateway.DeleteResource(path, repo.config.AccessToken())
+}
+
 func (repo CloudControllerAppInstancesRepository) updateInstancesWithStats(guid string, instances []models.AppInstanceFields) (updatedInst []models.AppInstanceFields, apiResponse net.ApiResponse) {
 	path := fmt.Sprintf("%s/v2/apps/%s/stats", repo.config.ApiEndpoint(), guid)
 	statsResponse := StatsApiResponse{}
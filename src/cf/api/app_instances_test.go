@@ -40,6 +40,21 @@ var _ = Describe("AppInstancesRepo", func() {
 		Expect(instance0.MemUsage).To(Equal(uint64(19218432)))
 		Expect(instance0.CpuUsage).To(Equal(3.659571249238058e-05))
 	})
+
+	It("deletes a single instance of an app", func() {
+		ts, handler, repo := createAppInstancesRepo([]testnet.TestRequest{
+			testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method:   "DELETE",
+				Path:     "/v2/apps/my-cool-app-guid/instances/2",
+				Response: testnet.TestResponse{Status: http.StatusNoContent},
+			}),
+		})
+		defer ts.Close()
+
+		apiResponse := repo.DeleteInstance("my-cool-app-guid", 2)
+		Expect(handler.AllRequestsCalled()).To(BeTrue())
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+	})
 })
 
 var appStatsRequest = testapi.NewCloudControllerTestRequest(testnet.TestRequest{
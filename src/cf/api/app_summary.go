@@ -29,7 +29,9 @@ type ApplicationFromSummary struct {
 	DiskQuota        uint64 `json:"disk_quota"`
 	Urls             []string
 	State            string
-	SpaceGuid        string `json:"space_guid"`
+	SpaceGuid        string   `json:"space_guid"`
+	ServiceNames     []string `json:"service_names"`
+	Services         []ServiceInstanceSummary
 }
 
 func (resource ApplicationFromSummary) ToFields() (app models.ApplicationFields) {
@@ -54,6 +56,12 @@ func (resource ApplicationFromSummary) ToModel() (app models.AppSummary) {
 	}
 	app.RouteSummaries = routes
 
+	// the space summary lists the names, the summary of a single app the instances
+	app.ServiceNames = resource.ServiceNames
+	for _, service := range resource.Services {
+		app.ServiceNames = append(app.ServiceNames, service.Name)
+	}
+
 	return
 }
 
@@ -51,9 +51,56 @@ var _ = Describe("AppSummaryRepository", func() {
 		Expect(app2.InstanceCount).To(Equal(3))
 		Expect(app2.RunningInstances).To(Equal(1))
 		Expect(app2.Memory).To(Equal(uint64(512)))
+		Expect(app2.ServiceNames).To(Equal([]string{"my-service-instance"}))
+	})
+
+	It("gets the summary of a single app", func() {
+		getAppSummaryRequest := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+			Method:   "GET",
+			Path:     "/v2/apps/app-1-guid/summary",
+			Response: testnet.TestResponse{Status: http.StatusOK, Body: getAppSummaryResponseBody},
+		})
+
+		ts, handler, repo := createAppSummaryRepo([]testnet.TestRequest{getAppSummaryRequest})
+		defer ts.Close()
+
+		app, apiResponse := repo.GetSummary("app-1-guid")
+		Expect(handler.AllRequestsCalled()).To(BeTrue())
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+
+		Expect(app.Name).To(Equal("app1"))
+		Expect(app.DiskQuota).To(Equal(uint64(1024)))
+		Expect(len(app.RouteSummaries)).To(Equal(1))
+		Expect(app.RouteSummaries[0].URL()).To(Equal("app1.cfapps.io"))
+		Expect(app.ServiceNames).To(Equal([]string{"my-db", "my-queue"}))
 	})
 })
 
+var getAppSummaryResponseBody = `
+{
+  "guid":"app-1-guid",
+  "name":"app1",
+  "routes":[
+    {
+      "guid":"route-1-guid",
+      "host":"app1",
+      "domain":{
+        "guid":"domain-1-guid",
+        "name":"cfapps.io"
+      }
+    }
+  ],
+  "running_instances":1,
+  "services":[
+    {"guid":"db-guid","name":"my-db","bound_app_count":1},
+    {"guid":"queue-guid","name":"my-queue","bound_app_count":2}
+  ],
+  "memory":128,
+  "disk_quota":1024,
+  "instances":1,
+  "state":"STARTED"
+}`
+
 var getAppSummariesResponseBody = `
 {
   "apps":[
@@ -7,6 +7,7 @@ import (
 	"cf/configuration"
 	"cf/models"
 	"cf/net"
+	"cf/trace"
 	"encoding/json"
 	"errors"
 	"fileutils"
@@ -26,36 +27,74 @@ type AppFileResource struct {
 }
 
 type ApplicationBitsRepository interface {
-	UploadApp(appGuid, dir string, cb func(path string, zipSize, fileCount uint64)) (apiResponse net.ApiResponse)
+	UploadApp(appGuid, dir string, forceUpload bool, cb func(path string, zipSize, fileCount uint64), progress UploadProgress) (uploaded bool, apiResponse net.ApiResponse)
+	PlanUpload(appGuid, dir string, forceUpload bool) (plan UploadPlan, apiResponse net.ApiResponse)
+}
+
+// UploadPlan describes what UploadApp would send, based on resource matching
+// against the files the cloud controller already has.
+type UploadPlan struct {
+	Unchanged       bool
+	TotalFileCount  uint64
+	UploadFileCount uint64
+	UploadBytes     uint64
+}
+
+// UploadProgress is told how much of the upload request has been sent, and
+// when the cloud controller starts processing the uploaded bits.
+type UploadProgress interface {
+	Sent(bytesSent, totalBytes int64)
+	Processing()
 }
 
 type CloudControllerApplicationBitsRepository struct {
 	config  configuration.Reader
 	gateway net.Gateway
 	zipper  cf.Zipper
+	cache   cf.AppBitsCache
 }
 
-func NewCloudControllerApplicationBitsRepository(config configuration.Reader, gateway net.Gateway, zipper cf.Zipper) (repo CloudControllerApplicationBitsRepository) {
+func NewCloudControllerApplicationBitsRepository(config configuration.Reader, gateway net.Gateway, zipper cf.Zipper, cache cf.AppBitsCache) (repo CloudControllerApplicationBitsRepository) {
 	repo.config = config
 	repo.gateway = gateway
 	repo.zipper = zipper
+	repo.cache = cache
 	return
 }
 
-func (repo CloudControllerApplicationBitsRepository) UploadApp(appGuid string, appDir string, cb func(path string, zipSize, fileCount uint64)) (apiResponse net.ApiResponse) {
+// UploadApp skips the upload when the files in appDir match the fingerprint
+// cached for appGuid by the last successful upload, unless forceUpload is set.
+func (repo CloudControllerApplicationBitsRepository) UploadApp(appGuid string, appDir string, forceUpload bool, cb func(path string, zipSize, fileCount uint64), progress UploadProgress) (uploaded bool, apiResponse net.ApiResponse) {
 	fileutils.TempDir("apps", func(uploadDir string, err error) {
 		if err != nil {
 			apiResponse = net.NewApiResponseWithMessage(err.Error())
 			return
 		}
 
-		var presentResourcesJson []byte
+		var (
+			presentResourcesJson []byte
+			fingerprint          string
+			unchanged            bool
+		)
 		repo.sourceDir(appDir, func(sourceDir string, sourceErr error) {
 			if sourceErr != nil {
 				err = sourceErr
 				return
 			}
-			presentResourcesJson, err = repo.copyUploadableFiles(sourceDir, uploadDir)
+
+			var allAppFiles []models.AppFileFields
+			allAppFiles, err = cf.AppFilesInDir(sourceDir)
+			if err != nil {
+				return
+			}
+
+			fingerprint = cf.AppFingerprint(allAppFiles)
+			if !forceUpload && fingerprint == repo.cache.Fingerprint(appGuid) {
+				unchanged = true
+				return
+			}
+
+			presentResourcesJson, err = repo.copyUploadableFiles(allAppFiles, sourceDir, uploadDir)
 		})
 
 		if err != nil {
@@ -63,6 +102,10 @@ func (repo CloudControllerApplicationBitsRepository) UploadApp(appGuid string, a
 			return
 		}
 
+		if unchanged {
+			return
+		}
+
 		fileutils.TempFile("uploads", func(zipFile *os.File, err error) {
 			if err != nil {
 				apiResponse = net.NewApiResponseWithMessage("%s", err.Error())
@@ -82,16 +125,55 @@ func (repo CloudControllerApplicationBitsRepository) UploadApp(appGuid string, a
 			}
 			cb(appDir, uint64(stat.Size()), cf.CountFiles(uploadDir))
 
-			apiResponse = repo.uploadBits(appGuid, zipFile, presentResourcesJson)
+			apiResponse = repo.uploadBits(appGuid, zipFile, presentResourcesJson, progress)
 			if apiResponse.IsNotSuccessful() {
 				return
 			}
+			uploaded = true
+
+			err = repo.cache.SetFingerprint(appGuid, fingerprint)
+			if err != nil {
+				trace.Logger.Printf("Error caching app bits fingerprint: %s\n", err)
+			}
 		})
 	})
 	return
 }
 
-func (repo CloudControllerApplicationBitsRepository) uploadBits(appGuid string, zipFile *os.File, presentResourcesJson []byte) (apiResponse net.ApiResponse) {
+func (repo CloudControllerApplicationBitsRepository) PlanUpload(appGuid string, appDir string, forceUpload bool) (plan UploadPlan, apiResponse net.ApiResponse) {
+	repo.sourceDir(appDir, func(sourceDir string, err error) {
+		if err != nil {
+			apiResponse = net.NewApiResponseWithMessage("%s", err)
+			return
+		}
+
+		allAppFiles, err := cf.AppFilesInDir(sourceDir)
+		if err != nil {
+			apiResponse = net.NewApiResponseWithMessage("%s", err)
+			return
+		}
+		plan.TotalFileCount = uint64(len(allAppFiles))
+
+		if !forceUpload && cf.AppFingerprint(allAppFiles) == repo.cache.Fingerprint(appGuid) {
+			plan.Unchanged = true
+			return
+		}
+
+		var appFilesToUpload []models.AppFileFields
+		appFilesToUpload, _, apiResponse = repo.getFilesToUpload(allAppFiles)
+		if apiResponse.IsNotSuccessful() {
+			return
+		}
+
+		plan.UploadFileCount = uint64(len(appFilesToUpload))
+		for _, file := range appFilesToUpload {
+			plan.UploadBytes += uint64(file.Size)
+		}
+	})
+	return
+}
+
+func (repo CloudControllerApplicationBitsRepository) uploadBits(appGuid string, zipFile *os.File, presentResourcesJson []byte, progress UploadProgress) (apiResponse net.ApiResponse) {
 	url := fmt.Sprintf("%s/v2/apps/%s/bits", repo.config.ApiEndpoint(), appGuid)
 	fileutils.TempFile("requests", func(requestFile *os.File, err error) {
 		if err != nil {
@@ -105,8 +187,21 @@ func (repo CloudControllerApplicationBitsRepository) uploadBits(appGuid string,
 			return
 		}
 
+		requestStats, err := requestFile.Stat()
+		if err != nil {
+			apiResponse = net.NewApiResponseWithError("Error reading tmp file", err)
+			return
+		}
+
+		body := net.NewProgressReader(requestFile, requestStats.Size(), func(bytesSent, totalBytes int64) {
+			progress.Sent(bytesSent, totalBytes)
+			if bytesSent == totalBytes {
+				progress.Processing()
+			}
+		})
+
 		var request *net.Request
-		request, apiResponse = repo.gateway.NewRequest("PUT", url, repo.config.AccessToken(), requestFile)
+		request, apiResponse = repo.gateway.NewRequest("PUT", url, repo.config.AccessToken(), body)
 		if apiResponse.IsNotSuccessful() {
 			return
 		}
@@ -144,13 +239,8 @@ func (repo CloudControllerApplicationBitsRepository) sourceDir(appDir string, cb
 	})
 }
 
-func (repo CloudControllerApplicationBitsRepository) copyUploadableFiles(appDir string, uploadDir string) (presentResourcesJson []byte, err error) {
+func (repo CloudControllerApplicationBitsRepository) copyUploadableFiles(allAppFiles []models.AppFileFields, appDir string, uploadDir string) (presentResourcesJson []byte, err error) {
 	// Find which files need to be uploaded
-	allAppFiles, err := cf.AppFilesInDir(appDir)
-	if err != nil {
-		return
-	}
-
 	appFilesToUpload, presentResourcesJson, apiResponse := repo.getFilesToUpload(allAppFiles)
 	if apiResponse.IsNotSuccessful() {
 		err = errors.New(apiResponse.Message)
@@ -196,6 +196,34 @@ func createProgressEndpoint(status string) (req testnet.TestRequest) {
 	return
 }
 
+type fakeAppBitsCache struct {
+	fingerprints map[string]string
+}
+
+func (cache *fakeAppBitsCache) Fingerprint(appGuid string) string {
+	return cache.fingerprints[appGuid]
+}
+
+func (cache *fakeAppBitsCache) SetFingerprint(appGuid, fingerprint string) (err error) {
+	cache.fingerprints[appGuid] = fingerprint
+	return
+}
+
+type fakeUploadProgress struct {
+	bytesSent           int64
+	totalBytes          int64
+	processingWasCalled bool
+}
+
+func (progress *fakeUploadProgress) Sent(bytesSent, totalBytes int64) {
+	progress.bytesSent = bytesSent
+	progress.totalBytes = totalBytes
+}
+
+func (progress *fakeUploadProgress) Processing() {
+	progress.processingWasCalled = true
+}
+
 func testUploadApp(dir string, requests []testnet.TestRequest) (app models.Application, apiResponse net.ApiResponse) {
 	ts, handler := testnet.NewTLSServer(requests)
 	defer ts.Close()
@@ -205,23 +233,29 @@ func testUploadApp(dir string, requests []testnet.TestRequest) (app models.Appli
 	gateway := net.NewCloudControllerGateway()
 	gateway.PollingThrottle = time.Duration(0)
 	zipper := cf.ApplicationZipper{}
-	repo := NewCloudControllerApplicationBitsRepository(configRepo, gateway, zipper)
+	cache := &fakeAppBitsCache{fingerprints: map[string]string{}}
+	repo := NewCloudControllerApplicationBitsRepository(configRepo, gateway, zipper, cache)
 
 	var (
 		reportedPath                          string
 		reportedFileCount, reportedUploadSize uint64
 	)
-	apiResponse = repo.UploadApp("my-cool-app-guid", dir, func(path string, uploadSize, fileCount uint64) {
+	progress := &fakeUploadProgress{}
+	_, apiResponse = repo.UploadApp("my-cool-app-guid", dir, false, func(path string, uploadSize, fileCount uint64) {
 		reportedPath = path
 		reportedUploadSize = uploadSize
 		reportedFileCount = fileCount
-	})
+	}, progress)
 
 	Expect(reportedPath).To(Equal(dir))
 	Expect(reportedFileCount).To(Equal(uint64(len(expectedApplicationContent))))
 	Expect(reportedUploadSize).To(Equal(uint64(759)))
 	Expect(handler.AllRequestsCalled()).To(BeTrue())
 
+	Expect(progress.totalBytes).To(BeNumerically(">", 0))
+	Expect(progress.bytesSent).To(Equal(progress.totalBytes))
+	Expect(progress.processingWasCalled).To(BeTrue())
+
 	return
 }
 
@@ -231,9 +265,10 @@ var _ = Describe("Testing with ginkgo", func() {
 		gateway := net.NewCloudControllerGateway()
 		zipper := &cf.ApplicationZipper{}
 
-		repo := NewCloudControllerApplicationBitsRepository(config, gateway, zipper)
+		cache := &fakeAppBitsCache{fingerprints: map[string]string{}}
+		repo := NewCloudControllerApplicationBitsRepository(config, gateway, zipper, cache)
 
-		apiResponse := repo.UploadApp("app-guid", "/foo/bar", func(path string, uploadSize, fileCount uint64) {})
+		_, apiResponse := repo.UploadApp("app-guid", "/foo/bar", false, func(path string, uploadSize, fileCount uint64) {}, &fakeUploadProgress{})
 		Expect(apiResponse.IsNotSuccessful()).To(BeTrue())
 		Expect(apiResponse.Message).To(ContainSubstring(filepath.Join("foo", "bar")))
 	})
@@ -283,3 +318,127 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(apiResponse.IsSuccessful()).To(BeFalse())
 	})
 })
+
+var _ = Describe("uploading app bits that have not changed", func() {
+	var (
+		dir         string
+		fingerprint string
+		cache       *fakeAppBitsCache
+	)
+
+	BeforeEach(func() {
+		wd, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir = filepath.Join(wd, "../../fixtures/example-app")
+
+		appFiles, err := cf.AppFilesInDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		fingerprint = cf.AppFingerprint(appFiles)
+
+		cache = &fakeAppBitsCache{fingerprints: map[string]string{}}
+	})
+
+	upload := func(forceUpload bool, requests []testnet.TestRequest) (uploaded, callbackCalled bool, apiResponse net.ApiResponse) {
+		ts, handler := testnet.NewTLSServer(requests)
+		defer ts.Close()
+
+		configRepo := testconfig.NewRepositoryWithDefaults()
+		configRepo.SetApiEndpoint(ts.URL)
+		gateway := net.NewCloudControllerGateway()
+		gateway.PollingThrottle = time.Duration(0)
+		repo := NewCloudControllerApplicationBitsRepository(configRepo, gateway, cf.ApplicationZipper{}, cache)
+
+		uploaded, apiResponse = repo.UploadApp("my-cool-app-guid", dir, forceUpload, func(path string, uploadSize, fileCount uint64) {
+			callbackCalled = true
+		}, &fakeUploadProgress{})
+		Expect(handler.AllRequestsCalled()).To(BeTrue())
+		return
+	}
+
+	It("remembers the fingerprint of the uploaded bits", func() {
+		uploaded, _, apiResponse := upload(false, defaultRequests)
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(uploaded).To(BeTrue())
+		Expect(cache.Fingerprint("my-cool-app-guid")).To(Equal(fingerprint))
+	})
+
+	It("skips the upload when the fingerprint matches the last upload", func() {
+		cache.fingerprints["my-cool-app-guid"] = fingerprint
+
+		uploaded, callbackCalled, apiResponse := upload(false, []testnet.TestRequest{})
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(uploaded).To(BeFalse())
+		Expect(callbackCalled).To(BeFalse())
+	})
+
+	It("uploads when the fingerprint was recorded for a different app", func() {
+		cache.fingerprints["some-other-app-guid"] = fingerprint
+
+		uploaded, _, apiResponse := upload(false, defaultRequests)
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(uploaded).To(BeTrue())
+	})
+
+	It("uploads unchanged bits when forced to", func() {
+		cache.fingerprints["my-cool-app-guid"] = fingerprint
+
+		uploaded, callbackCalled, apiResponse := upload(true, defaultRequests)
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(uploaded).To(BeTrue())
+		Expect(callbackCalled).To(BeTrue())
+	})
+})
+
+var _ = Describe("planning an app bits upload", func() {
+	var (
+		dir   string
+		cache *fakeAppBitsCache
+	)
+
+	BeforeEach(func() {
+		wd, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir = filepath.Join(wd, "../../fixtures/example-app")
+
+		cache = &fakeAppBitsCache{fingerprints: map[string]string{}}
+	})
+
+	planUpload := func(requests []testnet.TestRequest) (plan UploadPlan, apiResponse net.ApiResponse) {
+		ts, handler := testnet.NewTLSServer(requests)
+		defer ts.Close()
+
+		configRepo := testconfig.NewRepositoryWithDefaults()
+		configRepo.SetApiEndpoint(ts.URL)
+		gateway := net.NewCloudControllerGateway()
+		repo := NewCloudControllerApplicationBitsRepository(configRepo, gateway, cf.ApplicationZipper{}, cache)
+
+		plan, apiResponse = repo.PlanUpload("my-cool-app-guid", dir, false)
+		Expect(handler.AllRequestsCalled()).To(BeTrue())
+		return
+	}
+
+	It("counts the files the cloud controller does not have yet", func() {
+		plan, apiResponse := planUpload([]testnet.TestRequest{matchResourceRequest})
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(plan.Unchanged).To(BeFalse())
+		Expect(plan.TotalFileCount).To(Equal(uint64(5)))
+		Expect(plan.UploadFileCount).To(Equal(uint64(3)))
+		Expect(plan.UploadBytes).To(Equal(uint64(59 + 229 + 111)))
+	})
+
+	It("does not match resources when the bits are unchanged", func() {
+		appFiles, err := cf.AppFilesInDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		cache.fingerprints["my-cool-app-guid"] = cf.AppFingerprint(appFiles)
+
+		plan, apiResponse := planUpload([]testnet.TestRequest{})
+
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		Expect(plan.Unchanged).To(BeTrue())
+	})
+})
@@ -68,7 +68,9 @@ func NewApplicationEntityFromAppParams(app models.AppParams) ApplicationEntity {
 		state := strings.ToUpper(*app.State)
 		entity.State = &state
 	}
-	if app.EnvironmentVars != nil && len(*app.EnvironmentVars) > 0 {
+	// an empty map removes every variable, only a nil one leaves them as
+	// they are
+	if app.EnvironmentVars != nil && *app.EnvironmentVars != nil {
 		entity.EnvironmentJson = app.EnvironmentVars
 	}
 	return entity
@@ -81,6 +83,12 @@ func (resource ApplicationResource) ToFields() (app models.ApplicationFields) {
 	if entity.Name != nil {
 		app.Name = *entity.Name
 	}
+	if entity.Command != nil {
+		app.Command = *entity.Command
+	}
+	if entity.Buildpack != nil {
+		app.BuildpackUrl = *entity.Buildpack
+	}
 	if entity.Memory != nil {
 		app.Memory = uint64(*entity.Memory)
 	}
@@ -123,8 +131,10 @@ type PaginatedApplicationResources struct {
 type ApplicationRepository interface {
 	Create(params models.AppParams) (createdApp models.Application, apiResponse net.ApiResponse)
 	Read(name string) (app models.Application, apiResponse net.ApiResponse)
+	ReadFromSpace(name, spaceGuid string) (app models.Application, apiResponse net.ApiResponse)
 	Update(appGuid string, params models.AppParams) (updatedApp models.Application, apiResponse net.ApiResponse)
 	Delete(appGuid string) (apiResponse net.ApiResponse)
+	ReadEnv(appGuid string) (env models.Environment, apiResponse net.ApiResponse)
 }
 
 type CloudControllerApplicationRepository struct {
@@ -157,7 +167,11 @@ func (repo CloudControllerApplicationRepository) Create(params models.AppParams)
 }
 
 func (repo CloudControllerApplicationRepository) Read(name string) (app models.Application, apiResponse net.ApiResponse) {
-	path := fmt.Sprintf("%s/v2/spaces/%s/apps?q=%s&inline-relations-depth=1", repo.config.ApiEndpoint(), repo.config.SpaceFields().Guid, url.QueryEscape("name:"+name))
+	return repo.ReadFromSpace(name, repo.config.SpaceFields().Guid)
+}
+
+func (repo CloudControllerApplicationRepository) ReadFromSpace(name, spaceGuid string) (app models.Application, apiResponse net.ApiResponse) {
+	path := fmt.Sprintf("%s/v2/spaces/%s/apps?q=%s&inline-relations-depth=1", repo.config.ApiEndpoint(), spaceGuid, url.QueryEscape("name:"+name))
 	appResources := new(PaginatedApplicationResources)
 	apiResponse = repo.gateway.GetResource(path, repo.config.AccessToken(), appResources)
 	if apiResponse.IsNotSuccessful() {
@@ -192,6 +206,36 @@ func (repo CloudControllerApplicationRepository) Update(appGuid string, params m
 	return
 }
 
+type ApplicationEnvResource struct {
+	System      map[string]interface{} `json:"system_env_json"`
+	Application map[string]interface{} `json:"application_env_json"`
+	User        map[string]interface{} `json:"environment_json"`
+	Running     map[string]interface{} `json:"running_env_json"`
+	Staging     map[string]interface{} `json:"staging_env_json"`
+}
+
+func (resource ApplicationEnvResource) ToModel() models.Environment {
+	return models.Environment{
+		System:      resource.System,
+		Application: resource.Application,
+		User:        resource.User,
+		Running:     resource.Running,
+		Staging:     resource.Staging,
+	}
+}
+
+func (repo CloudControllerApplicationRepository) ReadEnv(appGuid string) (env models.Environment, apiResponse net.ApiResponse) {
+	path := fmt.Sprintf("%s/v2/apps/%s/env", repo.config.ApiEndpoint(), appGuid)
+	resource := new(ApplicationEnvResource)
+	apiResponse = repo.gateway.GetResource(path, repo.config.AccessToken(), resource)
+	if apiResponse.IsNotSuccessful() {
+		return
+	}
+
+	env = resource.ToModel()
+	return
+}
+
 func (repo CloudControllerApplicationRepository) formatAppJSON(input models.AppParams) (data string, err error) {
 	appResource := NewApplicationEntityFromAppParams(input)
 	bytes, err := json.Marshal(appResource)
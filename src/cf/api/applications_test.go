@@ -29,6 +29,8 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(app.Routes[0].Host).To(Equal("app1"))
 		Expect(app.Routes[0].Domain.Name).To(Equal("cfapps.io"))
 		Expect(app.Stack.Name).To(Equal("awesome-stacks-ahoy"))
+		Expect(app.Command).To(Equal("bundle exec rackup"))
+		Expect(app.BuildpackUrl).To(Equal("ruby_buildpack"))
 	})
 
 	It("TestFindByNameWhenAppIsNotFound", func() {
@@ -45,6 +47,19 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(apiResponse.IsNotFound()).To(BeTrue())
 	})
 
+	It("finds an app by name in another space", func() {
+		request := testapi.NewCloudControllerTestRequest(findAppRequest)
+		request.Path = "/v2/spaces/other-space-guid/apps?q=name%3AMy+App&inline-relations-depth=1"
+
+		ts, handler, repo := createAppRepo([]testnet.TestRequest{request})
+		defer ts.Close()
+
+		app, apiResponse := repo.ReadFromSpace("My App", "other-space-guid")
+		Expect(handler.AllRequestsCalled()).To(BeTrue())
+		Expect(apiResponse.IsNotSuccessful()).To(BeFalse())
+		Expect(app.Guid).To(Equal("app1-guid"))
+	})
+
 	It("TestSetEnv", func() {
 		request := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
 			Method:   "PUT",
@@ -144,6 +159,23 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(apiResponse.IsNotSuccessful()).To(BeFalse())
 	})
 
+	It("TestUpdateApplicationRemovesAllEnvironmentVariables", func() {
+		request := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+			Method:   "PUT",
+			Path:     "/v2/apps/my-app-guid?inline-relations-depth=1",
+			Matcher:  testnet.RequestBodyMatcher(`{"environment_json":{}}`),
+			Response: testnet.TestResponse{Status: http.StatusOK, Body: updateApplicationResponse},
+		})
+
+		ts, handler, repo := createAppRepo([]testnet.TestRequest{request})
+		defer ts.Close()
+
+		envVars := map[string]string{}
+		_, apiResponse := repo.Update("my-app-guid", models.AppParams{EnvironmentVars: &envVars})
+		Expect(handler.AllRequestsCalled()).To(BeTrue())
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+	})
+
 	It("TestDeleteApplication", func() {
 		deleteApplicationRequest := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
 			Method:   "DELETE",
@@ -158,6 +190,34 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(handler.AllRequestsCalled()).To(BeTrue())
 		Expect(apiResponse.IsNotSuccessful()).To(BeFalse())
 	})
+
+	It("reads the environment of an app", func() {
+		envRequest := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+			Method: "GET",
+			Path:   "/v2/apps/my-cool-app-guid/env",
+			Response: testnet.TestResponse{Status: http.StatusOK, Body: `
+{
+  "staging_env_json": {"STAGING": "true"},
+  "running_env_json": {"RUNNING": "true"},
+  "environment_json": {"my-key": "my-value", "count": 3},
+  "system_env_json": {"VCAP_SERVICES": {"p-mysql": [{"name": "db", "credentials": {"password": "secret"}}]}},
+  "application_env_json": {"VCAP_APPLICATION": {"name": "my-cool-app"}}
+}`},
+		})
+
+		ts, handler, repo := createAppRepo([]testnet.TestRequest{envRequest})
+		defer ts.Close()
+
+		env, apiResponse := repo.ReadEnv("my-cool-app-guid")
+		Expect(handler.AllRequestsCalled()).To(BeTrue())
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+
+		Expect(env.User).To(Equal(map[string]interface{}{"my-key": "my-value", "count": float64(3)}))
+		Expect(env.Running).To(Equal(map[string]interface{}{"RUNNING": "true"}))
+		Expect(env.Staging).To(Equal(map[string]interface{}{"STAGING": "true"}))
+		Expect(env.Application).To(HaveKey("VCAP_APPLICATION"))
+		Expect(env.System).To(HaveKey("VCAP_SERVICES"))
+	})
 })
 
 var singleAppResponse = testnet.TestResponse{
@@ -178,6 +238,8 @@ var singleAppResponse = testnet.TestResponse{
         "memory": 128,
         "instances": 1,
         "state": "STOPPED",
+        "command": "bundle exec rackup",
+        "buildpack": "ruby_buildpack",
         "stack": {
 			"metadata": {
 				  "guid": "app1-route-guid"
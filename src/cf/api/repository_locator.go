@@ -21,6 +21,7 @@ type RepositoryLocator struct {
 	appFilesRepo                    CloudControllerAppFilesRepository
 	domainRepo                      CloudControllerDomainRepository
 	routeRepo                       CloudControllerRouteRepository
+	routeHealthRepo                 HttpRouteHealthRepository
 	stackRepo                       CloudControllerStackRepository
 	serviceRepo                     CloudControllerServiceRepository
 	serviceBindingRepo              CloudControllerServiceBindingRepository
@@ -45,7 +46,7 @@ func NewRepositoryLocator(config configuration.ReadWriter, gatewaysByName map[st
 	cloudControllerGateway.SetTokenRefresher(loc.authRepo)
 	uaaGateway.SetTokenRefresher(loc.authRepo)
 
-	loc.appBitsRepo = NewCloudControllerApplicationBitsRepository(config, cloudControllerGateway, cf.ApplicationZipper{})
+	loc.appBitsRepo = NewCloudControllerApplicationBitsRepository(config, cloudControllerGateway, cf.ApplicationZipper{}, cf.NewDiskAppBitsCache(configuration.DefaultAppBitsCacheFilePath()))
 	loc.appEventsRepo = NewCloudControllerAppEventsRepository(config, cloudControllerGateway)
 	loc.appFilesRepo = NewCloudControllerAppFilesRepository(config, cloudControllerGateway)
 	loc.appRepo = NewCloudControllerApplicationRepository(config, cloudControllerGateway)
@@ -60,6 +61,7 @@ func NewRepositoryLocator(config configuration.ReadWriter, gatewaysByName map[st
 	loc.passwordRepo = NewCloudControllerPasswordRepository(config, uaaGateway, loc.endpointRepo)
 	loc.quotaRepo = NewCloudControllerQuotaRepository(config, cloudControllerGateway)
 	loc.routeRepo = NewCloudControllerRouteRepository(config, cloudControllerGateway, loc.domainRepo)
+	loc.routeHealthRepo = NewHttpRouteHealthRepository()
 	loc.stackRepo = NewCloudControllerStackRepository(config, cloudControllerGateway)
 	loc.serviceRepo = NewCloudControllerServiceRepository(config, cloudControllerGateway)
 	loc.serviceBindingRepo = NewCloudControllerServiceBindingRepository(config, cloudControllerGateway)
@@ -130,6 +132,10 @@ func (locator RepositoryLocator) GetRouteRepository() RouteRepository {
 	return locator.routeRepo
 }
 
+func (locator RepositoryLocator) GetRouteHealthRepository() RouteHealthRepository {
+	return locator.routeHealthRepo
+}
+
 func (locator RepositoryLocator) GetStackRepository() StackRepository {
 	return locator.stackRepo
 }
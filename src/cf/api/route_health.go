@@ -0,0 +1,47 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+type RouteHealthRepository interface {
+	Probe(url string, timeout time.Duration) (statusCode int, latency time.Duration, err error)
+}
+
+type HttpRouteHealthRepository struct{}
+
+func NewHttpRouteHealthRepository() (repo HttpRouteHealthRepository) {
+	return
+}
+
+// Probe requests url the way a browser would, going through the router to
+// the app, and reports the status code and how long the response took.
+//
+// Like the requests to the cloud controller (see net.newHttpClient), the
+// probe does not verify certificates, so that apps on domains with self
+// signed certificates can be checked. It sends no credentials and only reads
+// the status code, so a forged certificate cannot get anything out of it.
+// Routes are requested over plain http, which the router serves for every
+// route; an app that redirects to https is followed there.
+func (repo HttpRouteHealthRepository) Probe(url string, timeout time.Duration) (statusCode int, latency time.Duration, err error) {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			Proxy:           http.ProxyFromEnvironment,
+		},
+	}
+
+	startTime := time.Now()
+	response, err := client.Get(url)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	latency = time.Since(startTime)
+	statusCode = response.StatusCode
+	return
+}
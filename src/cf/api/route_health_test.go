@@ -0,0 +1,33 @@
+package api_test
+
+import (
+	. "cf/api"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("RouteHealthRepository", func() {
+	It("reports the status of the route", func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			Expect(request.URL.Path).To(Equal("/health"))
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		statusCode, latency, err := NewHttpRouteHealthRepository().Probe(ts.URL+"/health", time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(latency).To(BeNumerically(">", 0))
+	})
+
+	It("fails when the route cannot be reached", func() {
+		ts := httptest.NewServer(http.NotFoundHandler())
+		ts.Close()
+
+		_, _, err := NewHttpRouteHealthRepository().Probe(ts.URL+"/health", time.Second)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -16,6 +16,7 @@ type ServiceRepository interface {
 	GetAllServiceOfferings() (offerings models.ServiceOfferings, apiResponse net.ApiResponse)
 	GetServiceOfferingsForSpace(spaceGuid string) (offerings models.ServiceOfferings, apiResponse net.ApiResponse)
 	FindInstanceByName(name string) (instance models.ServiceInstance, apiResponse net.ApiResponse)
+	FindInstanceByNameInSpace(name, spaceGuid string) (instance models.ServiceInstance, apiResponse net.ApiResponse)
 	CreateServiceInstance(name, planGuid string) (identicalAlreadyExists bool, apiResponse net.ApiResponse)
 	RenameService(instance models.ServiceInstance, newName string) (apiResponse net.ApiResponse)
 	DeleteService(instance models.ServiceInstance) (apiResponse net.ApiResponse)
@@ -62,7 +63,11 @@ func (repo CloudControllerServiceRepository) getServiceOfferings(path string) (o
 }
 
 func (repo CloudControllerServiceRepository) FindInstanceByName(name string) (instance models.ServiceInstance, apiResponse net.ApiResponse) {
-	path := fmt.Sprintf("%s/v2/spaces/%s/service_instances?return_user_provided_service_instances=true&q=%s&inline-relations-depth=2", repo.config.ApiEndpoint(), repo.config.SpaceFields().Guid, url.QueryEscape("name:"+name))
+	return repo.FindInstanceByNameInSpace(name, repo.config.SpaceFields().Guid)
+}
+
+func (repo CloudControllerServiceRepository) FindInstanceByNameInSpace(name, spaceGuid string) (instance models.ServiceInstance, apiResponse net.ApiResponse) {
+	path := fmt.Sprintf("%s/v2/spaces/%s/service_instances?return_user_provided_service_instances=true&q=%s&inline-relations-depth=2", repo.config.ApiEndpoint(), spaceGuid, url.QueryEscape("name:"+name))
 
 	resources := new(PaginatedServiceInstanceResources)
 	apiResponse = repo.gateway.GetResource(path, repo.config.AccessToken(), resources)
@@ -155,6 +155,21 @@ var _ = Describe("Services Repo", func() {
 		Expect(apiResponse.IsNotFound()).To(BeTrue())
 	})
 
+	It("finds an instance by name in another space", func() {
+		req := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+			Method:   "GET",
+			Path:     "/v2/spaces/other-space-guid/service_instances?return_user_provided_service_instances=true&q=name%3Amy-service",
+			Response: testnet.TestResponse{Status: http.StatusOK, Body: `{ "resources": [] }`},
+		})
+
+		ts, handler, repo := createServiceRepo([]testnet.TestRequest{req})
+		defer ts.Close()
+
+		_, apiResponse := repo.FindInstanceByNameInSpace("my-service", "other-space-guid")
+		Expect(handler.AllRequestsCalled()).To(BeTrue())
+		Expect(apiResponse.IsNotFound()).To(BeTrue())
+	})
+
 	It("TestDeleteServiceWithoutServiceBindings", func() {
 		req := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
 			Method:   "DELETE",
@@ -46,7 +46,12 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 		{
 			Name:        "app",
 			Description: "Display health and status for app",
-			Usage:       fmt.Sprintf("%s app APP", cf.Name()),
+			Usage:       fmt.Sprintf("%s app APP [--watch] [--interval SECONDS] [--sort cpu|memory]", cf.Name()),
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "watch", Usage: "Keep refreshing the instances of the app, redrawing them in place on a terminal"},
+				NewIntFlagWithValue("interval", "Seconds between refreshes when watching", 5),
+				NewStringFlag("sort", "Sort the instances by cpu or memory usage when watching"),
+			},
 			Action: func(c *cli.Context) {
 				cmdRunner.RunCmdByName("app", c)
 			},
@@ -72,6 +77,24 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 				cmdRunner.RunCmdByName("auth", c)
 			},
 		},
+		{
+			Name:        "autoscale",
+			Description: "Keep scaling an app between a minimum and a maximum number of instances by cpu usage",
+			Usage: fmt.Sprintf("%s autoscale APP --max MAX [--min MIN] [--cpu-high PERCENT] [--cpu-low PERCENT]\n", cf.Name()) +
+				"   [--cooldown SECONDS] [--interval SECONDS] [--dry-run]",
+			Flags: []cli.Flag{
+				NewIntFlagWithValue("min", "Minimum number of instances", 1),
+				NewIntFlag("max", "Maximum number of instances"),
+				NewIntFlagWithValue("cpu-high", "Add an instance when the average cpu of the instances is above this percentage", 80),
+				NewIntFlagWithValue("cpu-low", "Remove an instance when the average cpu of the instances is below this percentage", 20),
+				NewIntFlagWithValue("cooldown", "Seconds to wait after scaling before scaling again", 300),
+				NewIntFlagWithValue("interval", "Seconds between samples of the instances", 30),
+				cli.BoolFlag{Name: "dry-run", Usage: "Log the decisions without scaling the app"},
+			},
+			Action: func(c *cli.Context) {
+				cmdRunner.RunCmdByName("autoscale", c)
+			},
+		},
 		{
 			Name:        "bind-service",
 			ShortName:   "bs",
@@ -89,6 +112,35 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 				cmdRunner.RunCmdByName("buildpacks", c)
 			},
 		},
+		{
+			Name:        "copy-app-config",
+			Description: "Copy env variables, service bindings, scale and routes from one app to another",
+			Usage: fmt.Sprintf("%s copy-app-config SOURCE_APP TARGET_APP [-s TARGET_SPACE] [--env] [--services] [--scale] [--routes]", cf.Name()) +
+				"\n\nTIP:\n" +
+				"   Without any of --env, --services, --scale or --routes, all of them are copied.",
+			Flags: []cli.Flag{
+				NewStringFlag("s", "Space of the target app, defaults to the targeted space"),
+				cli.BoolFlag{Name: "env", Usage: "Copy the env variables"},
+				cli.BoolFlag{Name: "services", Usage: "Bind the target app to the services of the source app"},
+				cli.BoolFlag{Name: "scale", Usage: "Copy the instance count and memory limit"},
+				cli.BoolFlag{Name: "routes", Usage: "Bind the target app to the routes of the source app"},
+			},
+			Action: func(c *cli.Context) {
+				cmdRunner.RunCmdByName("copy-app-config", c)
+			},
+		},
+		{
+			Name:        "create-app-manifest",
+			Description: "Create an app manifest for an app that has been pushed successfully",
+			Usage:       fmt.Sprintf("%s create-app-manifest APP [-p MANIFEST_PATH] [-f]", cf.Name()),
+			Flags: []cli.Flag{
+				NewStringFlag("p", "Path to write the manifest to, defaults to ./APP_manifest.yml"),
+				cli.BoolFlag{Name: "f", Usage: "Force overwriting an existing manifest without confirmation"},
+			},
+			Action: func(c *cli.Context) {
+				cmdRunner.RunCmdByName("create-app-manifest", c)
+			},
+		},
 		{
 			Name:        "create-buildpack",
 			Description: "Create a buildpack",
@@ -347,11 +399,31 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 				cmdRunner.RunCmdByName("domains", c)
 			},
 		},
+		{
+			Name:        "download",
+			Description: "Download a file or a directory tree from an app instance",
+			Usage: fmt.Sprintf("%s download APP REMOTE_PATH [LOCAL_DIR] [-i INSTANCE] [--include GLOB] [--exclude GLOB] [--concurrency N]", cf.Name()) +
+				"\n\nTIP:\n" +
+				"   A directory is downloaded with all of its files into a directory of the same name in LOCAL_DIR, which defaults to the current directory.",
+			Flags: []cli.Flag{
+				NewIntFlag("i", "Index of the instance to download from, defaults to 0"),
+				NewStringSliceFlag("include", "Only download files matching the glob, flag can be specified multiple times"),
+				NewStringSliceFlag("exclude", "Skip files matching the glob, flag can be specified multiple times"),
+				NewIntFlagWithValue("concurrency", "Number of files to download at once", 4),
+			},
+			Action: func(c *cli.Context) {
+				cmdRunner.RunCmdByName("download", c)
+			},
+		},
 		{
 			Name:        "env",
 			ShortName:   "e",
 			Description: "Show all env variables for an app",
-			Usage:       fmt.Sprintf("%s env APP", cf.Name()),
+			Usage:       fmt.Sprintf("%s env APP [--show-secrets] [--export shell|dotenv --show-secrets]", cf.Name()),
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "show-secrets", Usage: "Show the credentials of bound services instead of hiding them"},
+				NewStringFlag("export", "Print the variables of the running app to run it locally, as 'shell' exports or in 'dotenv' format, needs --show-secrets"),
+			},
 			Action: func(c *cli.Context) {
 				cmdRunner.RunCmdByName("env", c)
 			},
@@ -359,7 +431,18 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 		{
 			Name:        "events",
 			Description: "Show recent app events",
-			Usage:       fmt.Sprintf("%s events APP", cf.Name()),
+			Usage: fmt.Sprintf("%s events APP [--since TIME] [--until TIME] [--type TYPE] [--instance INDEX] [--limit COUNT] [--follow]\n\n", cf.Name()) +
+				"TIP:\n" +
+				"   TIME is a time like 2014-01-21T15:04:05Z, or a duration like 30m for that long ago\n" +
+				"   TYPE is one of crash, create, update, delete, start and stop, or an event type like app.crash",
+			Flags: []cli.Flag{
+				NewStringFlag("since", "Only show events at or after TIME"),
+				NewStringFlag("until", "Only show events at or before TIME"),
+				NewStringFlag("type", "Only show events of TYPE"),
+				NewIntFlag("instance", "Only show events about the instance with INDEX"),
+				NewIntFlag("limit", "Only show the COUNT most recent events"),
+				cli.BoolFlag{Name: "follow", Usage: "Keep showing new events as they happen, until interrupted"},
+			},
 			Action: func(c *cli.Context) {
 				cmdRunner.RunCmdByName("events", c)
 			},
@@ -368,7 +451,12 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 			Name:        "files",
 			ShortName:   "f",
 			Description: "Print out a list of files in a directory or the contents of a specific file",
-			Usage:       fmt.Sprintf("%s files APP [PATH]", cf.Name()),
+			Usage:       fmt.Sprintf("%s files APP [PATH] [-i INSTANCE] [--tail [--interval SECONDS]]", cf.Name()),
+			Flags: []cli.Flag{
+				NewIntFlag("i", "Index of the instance to get the files of, defaults to 0"),
+				cli.BoolFlag{Name: "tail", Usage: "Follow the file at PATH and print lines as they are appended, until interrupted"},
+				NewIntFlagWithValue("interval", "Seconds between reads of the file with --tail", 1),
+			},
 			Action: func(c *cli.Context) {
 				cmdRunner.RunCmdByName("files", c)
 			},
@@ -495,24 +583,44 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 			Usage: "Push a single app (with or without a manifest):\n" +
 				fmt.Sprintf("   %s push APP [-b BUILDPACK_NAME] [-c COMMAND] [-d DOMAIN] [-f MANIFEST_PATH]\n", cf.Name()) +
 				"   [-i NUM_INSTANCES] [-m MEMORY] [-n HOST] [-p PATH] [-s STACK] [-t TIMEOUT]\n" +
-				"   [--no-hostname] [--no-manifest] [--no-route] [--no-start]" +
+				"   [--parallel N] [--strategy blue-green] [--dry-run] [--force-upload] [--no-hostname] [--no-manifest] [--no-route] [--no-start]\n" +
+				"   [--vars-file VARS_FILE_PATH] [--var NAME=VALUE] [--print-manifest] [--prune-routes]\n" +
+				"   [--random-route] [--avoid-taken-routes] [--wait-for all|N|PERCENT%]\n" +
+				"   [--health-check-path PATH] [--expect-status STATUS] [--health-check-timeout SECONDS]" +
 				"\n\n   Push multiple apps with a manifest:\n" +
-				fmt.Sprintf("   %s push [-f MANIFEST_PATH]\n", cf.Name()),
+				fmt.Sprintf("   %s push [-f MANIFEST_PATH]\n", cf.Name()) +
+				"\n   Merge several manifests, each overlaying the ones before it:\n" +
+				fmt.Sprintf("   %s push -f MANIFEST_PATH -f OVERLAY_PATH\n", cf.Name()) +
+				"   Apps are merged by name, and a key set to '(( prune ))' or a list item '(( prune NAME ))' is removed\n",
 			Flags: []cli.Flag{
 				NewStringFlag("b", "Custom buildpack by name (e.g. my-buildpack) or GIT URL (e.g. https://github.com/heroku/heroku-buildpack-play.git)"),
 				NewStringFlag("c", "Startup command, set to null to reset to default start command"),
 				NewStringFlag("d", "Domain (e.g. example.com)"),
-				NewStringFlag("f", "Path to manifest"),
+				NewStringSliceFlag("f", "Path to manifest, flag can be specified multiple times to merge manifests in order"),
 				NewStringFlag("i", "Number of instances"),
 				NewStringFlag("m", "Memory limit (e.g. 256M, 1024M, 1G)"),
 				NewStringFlag("n", "Hostname (e.g. my-subdomain)"),
 				NewStringFlag("p", "Path of app directory or zip file"),
+				NewStringFlag("parallel", "Number of apps from the manifest to push at once, following their depends_on order"),
 				NewStringFlag("s", "Stack to use"),
 				NewStringFlag("t", "Start timeout in seconds"),
+				NewStringFlag("health-check-path", "Path to request on every route of the app once it runs, failing the command unless the app answers with the expected status"),
+				NewIntFlagWithValue("expect-status", "HTTP status the health check path has to answer with", 200),
+				NewIntFlagWithValue("health-check-timeout", "Seconds to keep retrying the health check path", 60),
+				NewStringFlag("wait-for", "Instances that must run before the app counts as started: 'all', a number or a percentage (e.g. 50%), defaults to 1"),
+				NewStringSliceFlag("vars-file", "Path to a YAML file of values for ((variables)) in the manifest, flag can be specified multiple times"),
+				NewStringSliceFlag("var", "Value for a ((variable)) in the manifest as NAME=VALUE, flag can be specified multiple times"),
+				NewStringFlag("strategy", "Use 'blue-green' to start the new version next to the running app and only then switch the routes over"),
+				cli.BoolFlag{Name: "dry-run", Usage: "Show what would be created, updated, bound and uploaded without changing anything"},
+				cli.BoolFlag{Name: "force-upload", Usage: "Upload app bits even if they are unchanged since the last push from this machine, which is all the local cache of pushed bits knows about"},
 				cli.BoolFlag{Name: "no-hostname", Usage: "Map the root domain to this app"},
 				cli.BoolFlag{Name: "no-manifest", Usage: "Ignore manifest file"},
 				cli.BoolFlag{Name: "no-route", Usage: "Do not map a route to this app"},
 				cli.BoolFlag{Name: "no-start", Usage: "Do not start an app after pushing"},
+				cli.BoolFlag{Name: "print-manifest", Usage: "Print the manifest with its ((variables)) filled in, without pushing"},
+				cli.BoolFlag{Name: "prune-routes", Usage: "Unbind the routes of the app that are not among the routes, hosts and domains of the manifest"},
+				cli.BoolFlag{Name: "random-route", Usage: "Create a route with a random host for this app"},
+				cli.BoolFlag{Name: "avoid-taken-routes", Usage: "Add random words to hosts whose routes are taken by another space"},
 			},
 			Action: func(c *cli.Context) {
 				cmdRunner.RunCmdByName("push", c)
@@ -570,11 +678,23 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 			Name:        "restart",
 			ShortName:   "rs",
 			Description: "Restart an app",
-			Usage:       fmt.Sprintf("%s restart APP", cf.Name()),
+			Usage:       fmt.Sprintf("%s restart APP [--rolling [--batch N]]", cf.Name()),
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "rolling", Usage: "Restart the instances a batch at a time, waiting for each batch to run again"},
+				NewIntFlagWithValue("batch", "Number of instances to restart at a time with --rolling", 1),
+			},
 			Action: func(c *cli.Context) {
 				cmdRunner.RunCmdByName("restart", c)
 			},
 		},
+		{
+			Name:        "restart-app-instance",
+			Description: "Restart a single instance of an app",
+			Usage:       fmt.Sprintf("%s restart-app-instance APP INDEX", cf.Name()),
+			Action: func(c *cli.Context) {
+				cmdRunner.RunCmdByName("restart-app-instance", c)
+			},
+		},
 		{
 			Name:        "routes",
 			ShortName:   "r",
@@ -652,7 +772,13 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 			Name:        "set-env",
 			ShortName:   "se",
 			Description: "Set an env variable for an app",
-			Usage:       fmt.Sprintf("%s set-env APP NAME VALUE", cf.Name()),
+			Usage: fmt.Sprintf("%s set-env APP NAME VALUE [--json]\n", cf.Name()) +
+				fmt.Sprintf("   %s set-env APP --from-file PATH [--dry-run]", cf.Name()),
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "json", Usage: "Parse VALUE as JSON, storing numbers and booleans as text and objects and lists as JSON"},
+				NewStringFlag("from-file", "Set every variable of a dotenv file in a single update"),
+				cli.BoolFlag{Name: "dry-run", Usage: "Show the added and changed variables without applying them"},
+			},
 			Action: func(c *cli.Context) {
 				cmdRunner.RunCmdByName("set-env", c)
 			},
@@ -735,7 +861,14 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 			Name:        "start",
 			ShortName:   "st",
 			Description: "Start an app",
-			Usage:       fmt.Sprintf("%s start APP", cf.Name()),
+			Usage: fmt.Sprintf("%s start APP [--wait-for all|N|PERCENT%%]\n", cf.Name()) +
+				"   [--health-check-path PATH] [--expect-status STATUS] [--health-check-timeout SECONDS]",
+			Flags: []cli.Flag{
+				NewStringFlag("health-check-path", "Path to request on every route of the app once it runs, failing the command unless the app answers with the expected status"),
+				NewIntFlagWithValue("expect-status", "HTTP status the health check path has to answer with", 200),
+				NewIntFlagWithValue("health-check-timeout", "Seconds to keep retrying the health check path", 60),
+				NewStringFlag("wait-for", "Instances that must run before the app counts as started: 'all', a number or a percentage (e.g. 50%), defaults to 1"),
+			},
 			Action: func(c *cli.Context) {
 				cmdRunner.RunCmdByName("start", c)
 			},
@@ -785,7 +918,13 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 		{
 			Name:        "unset-env",
 			Description: "Remove an env variable",
-			Usage:       fmt.Sprintf("%s unset-env APP NAME", cf.Name()),
+			Usage: fmt.Sprintf("%s unset-env APP NAME\n", cf.Name()) +
+				fmt.Sprintf("   %s unset-env APP [--all | --keys NAME1,NAME2] [--dry-run]", cf.Name()),
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "all", Usage: "Remove every env variable of the app"},
+				NewStringFlag("keys", "Comma separated names of the env variables to remove in a single update"),
+				cli.BoolFlag{Name: "dry-run", Usage: "Show the removed variables without applying the change"},
+			},
 			Action: func(c *cli.Context) {
 				cmdRunner.RunCmdByName("unset-env", c)
 			},
@@ -862,6 +1001,20 @@ func NewApp(cmdRunner commands.Runner) (app *cli.App, err error) {
 				cmdRunner.RunCmdByName("update-user-provided-service", c)
 			},
 		},
+		{
+			Name:        "validate-manifest",
+			Description: "Check a manifest for errors and unknown keys without pushing",
+			Usage:       fmt.Sprintf("%s validate-manifest [-f MANIFEST_PATH [-f OVERLAY_PATH]] [--vars-file VARS_FILE_PATH] [--var NAME=VALUE] [--strict]", cf.Name()),
+			Flags: []cli.Flag{
+				NewStringSliceFlag("f", "Path to manifest, flag can be specified multiple times to merge manifests in order"),
+				NewStringSliceFlag("vars-file", "Path to a YAML file of values for ((variables)) in the manifest, flag can be specified multiple times"),
+				NewStringSliceFlag("var", "Value for a ((variable)) in the manifest as NAME=VALUE, flag can be specified multiple times"),
+				cli.BoolFlag{Name: "strict", Usage: "Fail when the manifest has unknown keys"},
+			},
+			Action: func(c *cli.Context) {
+				cmdRunner.RunCmdByName("validate-manifest", c)
+			},
+		},
 	}
 	return
 }
@@ -17,18 +17,19 @@ import (
 )
 
 var expectedCommandNames = []string{
-	"api", "app", "apps", "auth", "bind-service", "buildpacks", "create-buildpack",
+	"api", "app", "apps", "auth", "autoscale", "bind-service", "buildpacks", "copy-app-config", "create-app-manifest", "create-buildpack",
 	"create-domain", "create-org", "create-route", "create-service", "create-service-auth-token",
 	"create-service-broker", "create-space", "create-user", "create-user-provided-service", "curl",
 	"delete", "delete-buildpack", "delete-domain", "delete-shared-domain", "delete-org", "delete-route",
 	"delete-service", "delete-service-auth-token", "delete-service-broker", "delete-space", "delete-user",
-	"domains", "env", "events", "files", "login", "logout", "logs", "marketplace", "map-route", "org",
+	"domains", "download", "env", "events", "files", "login", "logout", "logs", "marketplace", "map-route", "org",
 	"org-users", "orgs", "passwd", "purge-service-offering", "push", "quotas", "rename", "rename-org",
-	"rename-service", "rename-service-broker", "rename-space", "restart", "routes", "scale",
+	"rename-service", "rename-service-broker", "rename-space", "restart", "restart-app-instance", "routes", "scale",
 	"service", "service-auth-tokens", "service-brokers", "services", "set-env", "set-org-role", "set-quota",
 	"set-space-role", "create-shared-domain", "space", "space-users", "spaces", "stacks", "start", "stop",
 	"target", "unbind-service", "unmap-route", "unset-env", "unset-org-role", "unset-space-role",
 	"update-buildpack", "update-service-broker", "update-service-auth-token", "update-user-provided-service",
+	"validate-manifest",
 }
 
 var _ = Describe("App", func() {
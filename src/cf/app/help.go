@@ -120,20 +120,27 @@ func newAppPresenter(app *cli.App) (presenter appPresenter) {
 				}, {
 					newCmdPresenter(app, maxNameLen, "push"),
 					newCmdPresenter(app, maxNameLen, "scale"),
+					newCmdPresenter(app, maxNameLen, "autoscale"),
 					newCmdPresenter(app, maxNameLen, "delete"),
 					newCmdPresenter(app, maxNameLen, "rename"),
+				}, {
+					newCmdPresenter(app, maxNameLen, "validate-manifest"),
+					newCmdPresenter(app, maxNameLen, "create-app-manifest"),
 				}, {
 					newCmdPresenter(app, maxNameLen, "start"),
 					newCmdPresenter(app, maxNameLen, "stop"),
 					newCmdPresenter(app, maxNameLen, "restart"),
+					newCmdPresenter(app, maxNameLen, "restart-app-instance"),
 				}, {
 					newCmdPresenter(app, maxNameLen, "events"),
 					newCmdPresenter(app, maxNameLen, "files"),
+					newCmdPresenter(app, maxNameLen, "download"),
 					newCmdPresenter(app, maxNameLen, "logs"),
 				}, {
 					newCmdPresenter(app, maxNameLen, "env"),
 					newCmdPresenter(app, maxNameLen, "set-env"),
 					newCmdPresenter(app, maxNameLen, "unset-env"),
+					newCmdPresenter(app, maxNameLen, "copy-app-config"),
 				}, {
 					newCmdPresenter(app, maxNameLen, "stacks"),
 				},
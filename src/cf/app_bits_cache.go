@@ -0,0 +1,97 @@
+package cf
+
+import (
+	"cf/models"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+type AppBitsCache interface {
+	Fingerprint(appGuid string) string
+	SetFingerprint(appGuid, fingerprint string) (err error)
+}
+
+type DiskAppBitsCache struct {
+	filePath string
+	mutex    *sync.Mutex
+}
+
+func NewDiskAppBitsCache(filePath string) DiskAppBitsCache {
+	return DiskAppBitsCache{filePath: filePath, mutex: new(sync.Mutex)}
+}
+
+func (cache DiskAppBitsCache) Fingerprint(appGuid string) string {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	fingerprints, _ := cache.read()
+	return fingerprints[appGuid]
+}
+
+func (cache DiskAppBitsCache) SetFingerprint(appGuid, fingerprint string) (err error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	// an unreadable cache is rebuilt rather than blocking the push
+	fingerprints, _ := cache.read()
+	fingerprints[appGuid] = fingerprint
+	return cache.write(fingerprints)
+}
+
+func (cache DiskAppBitsCache) read() (fingerprints map[string]string, err error) {
+	fingerprints = map[string]string{}
+
+	jsonBytes, err := ioutil.ReadFile(cache.filePath)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(jsonBytes, &fingerprints)
+	if err != nil || fingerprints == nil {
+		fingerprints = map[string]string{}
+	}
+	return
+}
+
+func (cache DiskAppBitsCache) write(fingerprints map[string]string) (err error) {
+	err = os.MkdirAll(filepath.Dir(cache.filePath), 0700)
+	if err != nil {
+		return
+	}
+
+	jsonBytes, err := json.Marshal(fingerprints)
+	if err != nil {
+		return
+	}
+
+	return ioutil.WriteFile(cache.filePath, jsonBytes, 0600)
+}
+
+// AppFingerprint identifies the content of an app package. It only depends on
+// the relative paths, sizes, modes and SHA1s of the files, so it is stable
+// across walk orders.
+func AppFingerprint(appFiles []models.AppFileFields) string {
+	sortedFiles := make([]models.AppFileFields, len(appFiles))
+	copy(sortedFiles, appFiles)
+	sort.Sort(appFilesByPath(sortedFiles))
+
+	h := sha1.New()
+	for _, file := range sortedFiles {
+		fmt.Fprintf(h, "%s\x00%s\x00%d\x00%o\n", filepath.ToSlash(file.Path), file.Sha1, file.Size, uint32(file.Mode))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+type appFilesByPath []models.AppFileFields
+
+func (files appFilesByPath) Len() int      { return len(files) }
+func (files appFilesByPath) Swap(i, j int) { files[i], files[j] = files[j], files[i] }
+func (files appFilesByPath) Less(i, j int) bool {
+	return filepath.ToSlash(files[i].Path) < filepath.ToSlash(files[j].Path)
+}
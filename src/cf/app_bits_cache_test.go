@@ -0,0 +1,82 @@
+package cf_test
+
+import (
+	. "cf"
+	"cf/models"
+	"fileutils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("AppBitsCache", func() {
+	It("persists fingerprints per app guid", func() {
+		fileutils.TempDir("app_bits_cache_test", func(dir string, err error) {
+			Expect(err).NotTo(HaveOccurred())
+			cachePath := filepath.Join(dir, ".cf", "app_bits_cache.json")
+
+			cache := NewDiskAppBitsCache(cachePath)
+			Expect(cache.Fingerprint("app-guid")).To(Equal(""))
+
+			err = cache.SetFingerprint("app-guid", "some-fingerprint")
+			Expect(err).NotTo(HaveOccurred())
+			err = cache.SetFingerprint("other-app-guid", "other-fingerprint")
+			Expect(err).NotTo(HaveOccurred())
+
+			cache = NewDiskAppBitsCache(cachePath)
+			Expect(cache.Fingerprint("app-guid")).To(Equal("some-fingerprint"))
+			Expect(cache.Fingerprint("other-app-guid")).To(Equal("other-fingerprint"))
+		})
+	})
+})
+
+var _ = Describe("AppFingerprint", func() {
+	files := []models.AppFileFields{
+		{Path: "app.rb", Sha1: "2474735f5163ba7612ef641f438f4b5bee00127b", Size: 51},
+		{Path: "config.ru", Sha1: "f097424ce1fa66c6cb9f5e8a18c317376ec12e05", Size: 70},
+	}
+
+	It("does not depend on the order of the files", func() {
+		reversed := []models.AppFileFields{files[1], files[0]}
+		Expect(AppFingerprint(reversed)).To(Equal(AppFingerprint(files)))
+	})
+
+	It("changes when the content of a file changes", func() {
+		changed := []models.AppFileFields{files[0], files[1]}
+		changed[1].Sha1 = "d9c3a51de5c89c11331d3b90b972789f1a14699a"
+		Expect(AppFingerprint(changed)).NotTo(Equal(AppFingerprint(files)))
+	})
+
+	It("changes when the mode of a file changes", func() {
+		changed := []models.AppFileFields{files[0], files[1]}
+		changed[0].Mode = 0755
+		Expect(AppFingerprint(changed)).NotTo(Equal(AppFingerprint(files)))
+	})
+
+	It("changes when a file of an app directory is made executable", func() {
+		fileutils.TempDir("app_fingerprint_test", func(dir string, err error) {
+			Expect(err).NotTo(HaveOccurred())
+			scriptPath := filepath.Join(dir, "start.sh")
+			err = ioutil.WriteFile(scriptPath, []byte("#!/bin/sh\n"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			appFiles, err := AppFilesInDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			fingerprint := AppFingerprint(appFiles)
+
+			err = os.Chmod(scriptPath, 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			appFiles, err = AppFilesInDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(AppFingerprint(appFiles)).NotTo(Equal(fingerprint))
+		})
+	})
+
+	It("changes when a file is added", func() {
+		added := append([]models.AppFileFields{{Path: "Gemfile", Sha1: "d9c3a51de5c89c11331d3b90b972789f1a14699a", Size: 59}}, files...)
+		Expect(AppFingerprint(added)).NotTo(Equal(AppFingerprint(files)))
+	})
+})
@@ -49,6 +49,7 @@ func AppFilesInDir(dir string) (appFiles []models.AppFileFields, err error) {
 			Path: fileName,
 			Sha1: sha1,
 			Size: size,
+			Mode: fileInfo.Mode(),
 		})
 
 		return
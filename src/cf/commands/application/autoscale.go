@@ -0,0 +1,235 @@
+package application
+
+import (
+	"cf/api"
+	"cf/configuration"
+	"cf/models"
+	"cf/requirements"
+	"cf/terminal"
+	"errors"
+	"fmt"
+	"github.com/codegangsta/cli"
+	"time"
+)
+
+// Clock lets tests drive polling commands without waiting for real time to pass.
+type Clock interface {
+	Now() time.Time
+	Sleep(duration time.Duration)
+}
+
+type systemClock struct{}
+
+func (clock systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (clock systemClock) Sleep(duration time.Duration) {
+	time.Sleep(duration)
+}
+
+type autoscalePolicy struct {
+	min      int
+	max      int
+	cpuHigh  float64
+	cpuLow   float64
+	cooldown time.Duration
+	interval time.Duration
+	dryRun   bool
+}
+
+type Autoscale struct {
+	ui               terminal.UI
+	config           configuration.Reader
+	appRepo          api.ApplicationRepository
+	appInstancesRepo api.AppInstancesRepository
+	appReq           requirements.ApplicationRequirement
+	policy           autoscalePolicy
+
+	Clock  Clock
+	Rounds int
+}
+
+func NewAutoscale(ui terminal.UI, config configuration.Reader, appRepo api.ApplicationRepository, appInstancesRepo api.AppInstancesRepository) (cmd *Autoscale) {
+	cmd = new(Autoscale)
+	cmd.ui = ui
+	cmd.config = config
+	cmd.appRepo = appRepo
+	cmd.appInstancesRepo = appInstancesRepo
+	cmd.Clock = systemClock{}
+	return
+}
+
+func (cmd *Autoscale) GetRequirements(reqFactory requirements.Factory, c *cli.Context) (reqs []requirements.Requirement, err error) {
+	if len(c.Args()) != 1 || c.Int("max") < 1 {
+		err = errors.New("Incorrect Usage")
+		cmd.ui.FailWithUsage(c, "autoscale")
+		return
+	}
+
+	cmd.policy = autoscalePolicy{
+		min:      c.Int("min"),
+		max:      c.Int("max"),
+		cpuHigh:  float64(c.Int("cpu-high")),
+		cpuLow:   float64(c.Int("cpu-low")),
+		cooldown: time.Duration(c.Int("cooldown")) * time.Second,
+		interval: time.Duration(c.Int("interval")) * time.Second,
+		dryRun:   c.Bool("dry-run"),
+	}
+
+	err = cmd.policy.validate()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+
+	cmd.appReq = reqFactory.NewApplicationRequirement(c.Args()[0])
+	reqs = []requirements.Requirement{
+		reqFactory.NewLoginRequirement(),
+		reqFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+	return
+}
+
+func (policy autoscalePolicy) validate() error {
+	switch {
+	case policy.min < 1 || policy.max < policy.min:
+		return errors.New(fmt.Sprintf("Invalid instance range: %d to %d\nExpected --min to be at least 1 and no more than --max", policy.min, policy.max))
+	case policy.cpuLow < 0 || policy.cpuHigh > 100 || policy.cpuLow >= policy.cpuHigh:
+		return errors.New(fmt.Sprintf("Invalid cpu thresholds: %.0f%% to %.0f%%\nExpected --cpu-low to be below --cpu-high, both between 0 and 100", policy.cpuLow, policy.cpuHigh))
+	case policy.interval <= 0 || policy.cooldown < 0:
+		return errors.New("Invalid timing\nExpected --interval to be at least a second and --cooldown not to be negative")
+	}
+	return nil
+}
+
+func (cmd *Autoscale) Run(c *cli.Context) {
+	app := cmd.appReq.GetApplication()
+
+	cmd.ui.Say("Autoscaling app %s in org %s / space %s as %s between %d and %d instances, cpu %.0f%% to %.0f%%...",
+		terminal.EntityNameColor(app.Name),
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+		cmd.policy.min,
+		cmd.policy.max,
+		cmd.policy.cpuLow,
+		cmd.policy.cpuHigh,
+	)
+	if cmd.policy.dryRun {
+		cmd.ui.Say("Dry run, the app will not be scaled")
+	}
+	cmd.ui.Say("")
+
+	var lastScaled time.Time
+
+	for round := 0; cmd.Rounds == 0 || round < cmd.Rounds; round++ {
+		if round > 0 {
+			cmd.Clock.Sleep(cmd.policy.interval)
+		}
+
+		// the app can be scaled by others between two rounds, so the decision
+		// starts from the instance count it has now
+		currentApp, apiResponse := cmd.appRepo.Read(app.Name)
+		if apiResponse.IsNotSuccessful() {
+			cmd.log("skipping: could not read the app: %s", apiResponse.Message)
+			continue
+		}
+		instanceCount := currentApp.InstanceCount
+
+		instances, apiResponse := cmd.appInstancesRepo.GetInstances(app.Guid)
+		if apiResponse.IsNotSuccessful() {
+			cmd.log("skipping: could not sample instances: %s", apiResponse.Message)
+			continue
+		}
+
+		newCount, reason := cmd.policy.decide(instanceCount, instances, cmd.Clock.Now().Sub(lastScaled))
+		if newCount == instanceCount {
+			cmd.log("holding at %d instances: %s", instanceCount, reason)
+			continue
+		}
+
+		direction := "up"
+		if newCount < instanceCount {
+			direction = "down"
+		}
+
+		if cmd.policy.dryRun {
+			cmd.log("would scale %s from %d to %d instances: %s", direction, instanceCount, newCount, reason)
+			lastScaled = cmd.Clock.Now()
+			continue
+		}
+
+		cmd.log("scaling %s from %d to %d instances: %s", direction, instanceCount, newCount, reason)
+		_, apiResponse = cmd.appRepo.Update(app.Guid, models.AppParams{InstanceCount: &newCount})
+		if apiResponse.IsNotSuccessful() {
+			cmd.log("scaling failed: %s", apiResponse.Message)
+			continue
+		}
+
+		lastScaled = cmd.Clock.Now()
+	}
+}
+
+// decide picks the instance count for the cpu usage of the running
+// instances. Only leaving the band between the low and the high threshold
+// changes the count, one instance at a time and not before the cooldown
+// since the last change has passed, so that the app does not flap.
+func (policy autoscalePolicy) decide(instanceCount int, instances []models.AppInstanceFields, sinceLastScale time.Duration) (newCount int, reason string) {
+	newCount = instanceCount
+
+	switch {
+	case instanceCount < policy.min:
+		return policy.min, fmt.Sprintf("below the minimum of %d", policy.min)
+	case instanceCount > policy.max:
+		return policy.max, fmt.Sprintf("above the maximum of %d", policy.max)
+	}
+
+	cpu, running := averageCpu(instances)
+	if running == 0 {
+		reason = "no running instances to sample"
+		return
+	}
+
+	switch {
+	case cpu > policy.cpuHigh && instanceCount < policy.max:
+		newCount = instanceCount + 1
+		reason = fmt.Sprintf("average cpu %.1f%% is above %.0f%%", cpu, policy.cpuHigh)
+	case cpu < policy.cpuLow && instanceCount > policy.min:
+		newCount = instanceCount - 1
+		reason = fmt.Sprintf("average cpu %.1f%% is below %.0f%%", cpu, policy.cpuLow)
+	case cpu > policy.cpuHigh:
+		reason = fmt.Sprintf("average cpu %.1f%% is above %.0f%% but the maximum of %d is reached", cpu, policy.cpuHigh, policy.max)
+		return
+	case cpu < policy.cpuLow:
+		reason = fmt.Sprintf("average cpu %.1f%% is below %.0f%% but the minimum of %d is reached", cpu, policy.cpuLow, policy.min)
+		return
+	default:
+		reason = fmt.Sprintf("average cpu %.1f%% is between %.0f%% and %.0f%%", cpu, policy.cpuLow, policy.cpuHigh)
+		return
+	}
+
+	if sinceLastScale < policy.cooldown {
+		reason = fmt.Sprintf("%s but cooling down for another %s", reason, policy.cooldown-sinceLastScale)
+		newCount = instanceCount
+	}
+	return
+}
+
+func averageCpu(instances []models.AppInstanceFields) (cpu float64, running int) {
+	for _, instance := range instances {
+		if instance.State == models.InstanceRunning {
+			cpu += instance.CpuUsage * 100
+			running++
+		}
+	}
+	if running > 0 {
+		cpu = cpu / float64(running)
+	}
+	return
+}
+
+func (cmd *Autoscale) log(message string, args ...interface{}) {
+	cmd.ui.Say("[%s] %s", cmd.Clock.Now().Format("15:04:05"), fmt.Sprintf(message, args...))
+}
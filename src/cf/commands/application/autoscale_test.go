@@ -0,0 +1,166 @@
+package application_test
+
+import (
+	. "cf/commands/application"
+	"cf/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	testapi "testhelpers/api"
+	testassert "testhelpers/assert"
+	testcmd "testhelpers/commands"
+	testconfig "testhelpers/configuration"
+	testreq "testhelpers/requirements"
+	testterm "testhelpers/terminal"
+	testtime "testhelpers/time"
+	"time"
+)
+
+func instancesWithCpu(cpus ...float64) (instances []models.AppInstanceFields) {
+	for _, cpu := range cpus {
+		instances = append(instances, models.AppInstanceFields{State: models.InstanceRunning, CpuUsage: cpu})
+	}
+	return
+}
+
+func appWithInstanceCount(count int) (app models.Application) {
+	app.Name = "my-app"
+	app.Guid = "my-app-guid"
+	app.InstanceCount = count
+	return
+}
+
+var _ = Describe("autoscale command", func() {
+	var (
+		reqFactory       *testreq.FakeReqFactory
+		appRepo          *testapi.FakeApplicationRepository
+		appInstancesRepo *testapi.FakeAppInstancesRepo
+		clock            *testtime.FakeClock
+	)
+
+	BeforeEach(func() {
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		app.InstanceCount = 2
+		reqFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: app}
+		appRepo = &testapi.FakeApplicationRepository{ReadApp: app}
+		appInstancesRepo = &testapi.FakeAppInstancesRepo{}
+		clock = &testtime.FakeClock{CurrentTime: time.Date(2014, time.March, 1, 13, 0, 0, 0, time.UTC)}
+	})
+
+	callAutoscale := func(args []string, rounds int) (ui *testterm.FakeUI) {
+		ui = new(testterm.FakeUI)
+		cmd := NewAutoscale(ui, testconfig.NewRepositoryWithDefaults(), appRepo, appInstancesRepo)
+		cmd.Clock = clock
+		cmd.Rounds = rounds
+		testcmd.RunCommand(cmd, testcmd.NewContext("autoscale", args), reqFactory)
+		return
+	}
+
+	It("fails with usage without an app and a maximum", func() {
+		ui := callAutoscale([]string{"my-app"}, 1)
+		Expect(ui.FailedWithUsage).To(BeTrue())
+
+		ui = callAutoscale([]string{"--max", "4"}, 1)
+		Expect(ui.FailedWithUsage).To(BeTrue())
+	})
+
+	It("fails when the cpu thresholds overlap", func() {
+		ui := callAutoscale([]string{"--max", "4", "--cpu-low", "80", "--cpu-high", "50", "my-app"}, 1)
+
+		Expect(testcmd.CommandDidPassRequirements).To(BeFalse())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid cpu thresholds"},
+		})
+	})
+
+	It("scales up and down one instance at a time, waiting for the cooldown in between", func() {
+		appRepo.ReadAppResponses = []models.Application{
+			appWithInstanceCount(2),
+			appWithInstanceCount(3),
+			appWithInstanceCount(3),
+			appWithInstanceCount(3),
+		}
+		appInstancesRepo.GetInstancesResponses = [][]models.AppInstanceFields{
+			instancesWithCpu(0.9, 0.95),
+			instancesWithCpu(0.9, 0.95, 0.85),
+			instancesWithCpu(0.5, 0.5, 0.5),
+			instancesWithCpu(0.1, 0.1, 0.1),
+		}
+
+		ui := callAutoscale([]string{"--max", "4", "--cooldown", "90", "--interval", "60", "my-app"}, 4)
+
+		Expect(clock.Sleeps).To(Equal([]time.Duration{time.Minute, time.Minute, time.Minute}))
+		Expect(appRepo.UpdatedAppGuids).To(Equal([]string{"my-app-guid", "my-app-guid"}))
+		Expect(*appRepo.UpdatedParams[0].InstanceCount).To(Equal(3))
+		Expect(*appRepo.UpdatedParams[1].InstanceCount).To(Equal(2))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Autoscaling app", "my-app", "between 1 and 4 instances"},
+			{"[13:00:00] scaling up from 2 to 3 instances: average cpu 92.5% is above 80%"},
+			{"[13:01:00] holding at 3 instances", "above 80% but cooling down for another 30s"},
+			{"[13:02:00] holding at 3 instances: average cpu 50.0% is between 20% and 80%"},
+			{"[13:03:00] scaling down from 3 to 2 instances: average cpu 10.0% is below 20%"},
+		})
+	})
+
+	It("starts each round from the instance count the app has at that time", func() {
+		appRepo.ReadAppResponses = []models.Application{
+			appWithInstanceCount(2),
+			appWithInstanceCount(4),
+		}
+		appInstancesRepo.GetInstancesResponses = [][]models.AppInstanceFields{
+			instancesWithCpu(0.5, 0.5),
+			instancesWithCpu(0.1, 0.1, 0.1, 0.1),
+		}
+
+		ui := callAutoscale([]string{"--max", "4", "--cooldown", "0", "my-app"}, 2)
+
+		Expect(appRepo.ReadName).To(Equal("my-app"))
+		Expect(appRepo.UpdatedAppGuids).To(Equal([]string{"my-app-guid"}))
+		Expect(*appRepo.UpdatedParams[0].InstanceCount).To(Equal(3))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"holding at 2 instances"},
+			{"scaling down from 4 to 3 instances"},
+		})
+	})
+
+	It("skips the round when the app cannot be read", func() {
+		appRepo.ReadErr = true
+
+		ui := callAutoscale([]string{"--max", "4", "my-app"}, 1)
+
+		Expect(appRepo.UpdatedAppGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"skipping: could not read the app"},
+		})
+	})
+
+	It("only logs the decisions in a dry run", func() {
+		appInstancesRepo.GetInstancesResponses = [][]models.AppInstanceFields{
+			instancesWithCpu(0.9, 0.95),
+		}
+
+		ui := callAutoscale([]string{"--max", "4", "--dry-run", "my-app"}, 1)
+
+		Expect(appRepo.UpdatedAppGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Dry run"},
+			{"would scale up from 2 to 3 instances"},
+		})
+	})
+
+	It("does not scale past the maximum", func() {
+		appInstancesRepo.GetInstancesResponses = [][]models.AppInstanceFields{
+			instancesWithCpu(0.9, 0.95),
+		}
+
+		ui := callAutoscale([]string{"--max", "2", "my-app"}, 1)
+
+		Expect(appRepo.UpdatedAppGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"holding at 2 instances", "the maximum of 2 is reached"},
+		})
+	})
+})
@@ -0,0 +1,287 @@
+package application
+
+import (
+	"cf"
+	"cf/api"
+	"cf/commands/service"
+	"cf/configuration"
+	"cf/formatters"
+	"cf/models"
+	"cf/requirements"
+	"cf/terminal"
+	"errors"
+	"fmt"
+	"github.com/codegangsta/cli"
+)
+
+type CopyAppConfig struct {
+	ui             terminal.UI
+	config         configuration.Reader
+	appRepo        api.ApplicationRepository
+	appSummaryRepo api.AppSummaryRepository
+	spaceRepo      api.SpaceRepository
+	serviceRepo    api.ServiceRepository
+	bindingRepo    api.ServiceBindingRepository
+	routeRepo      api.RouteRepository
+}
+
+const (
+	configItemCopied   = "copied"
+	configItemUpToDate = "up to date"
+	configItemSkipped  = "skipped"
+	configItemFailed   = "failed"
+)
+
+type configItemResult struct {
+	item    string
+	result  string
+	details string
+}
+
+func NewCopyAppConfig(ui terminal.UI, config configuration.Reader, appRepo api.ApplicationRepository, appSummaryRepo api.AppSummaryRepository,
+	spaceRepo api.SpaceRepository, serviceRepo api.ServiceRepository, bindingRepo api.ServiceBindingRepository, routeRepo api.RouteRepository) (cmd *CopyAppConfig) {
+
+	cmd = new(CopyAppConfig)
+	cmd.ui = ui
+	cmd.config = config
+	cmd.appRepo = appRepo
+	cmd.appSummaryRepo = appSummaryRepo
+	cmd.spaceRepo = spaceRepo
+	cmd.serviceRepo = serviceRepo
+	cmd.bindingRepo = bindingRepo
+	cmd.routeRepo = routeRepo
+	return
+}
+
+func (cmd *CopyAppConfig) GetRequirements(reqFactory requirements.Factory, c *cli.Context) (reqs []requirements.Requirement, err error) {
+	if len(c.Args()) != 2 {
+		err = errors.New("Incorrect Usage")
+		cmd.ui.FailWithUsage(c, "copy-app-config")
+		return
+	}
+
+	reqs = []requirements.Requirement{
+		reqFactory.NewLoginRequirement(),
+		reqFactory.NewTargetedSpaceRequirement(),
+	}
+	return
+}
+
+func (cmd *CopyAppConfig) Run(c *cli.Context) {
+	sourceName := c.Args()[0]
+	targetName := c.Args()[1]
+
+	copyAll := !c.Bool("env") && !c.Bool("services") && !c.Bool("scale") && !c.Bool("routes")
+
+	targetSpace := cmd.config.SpaceFields()
+	if c.String("s") != "" {
+		space, apiResponse := cmd.spaceRepo.FindByName(c.String("s"))
+		if apiResponse.IsNotSuccessful() {
+			cmd.ui.Failed(apiResponse.Message)
+			return
+		}
+		targetSpace = space.SpaceFields
+	}
+
+	cmd.ui.Say("Copying config of app %s to app %s in org %s / space %s as %s...",
+		terminal.EntityNameColor(sourceName),
+		terminal.EntityNameColor(targetName),
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(targetSpace.Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+	)
+
+	source, apiResponse := cmd.appRepo.Read(sourceName)
+	if apiResponse.IsNotSuccessful() {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	target, apiResponse := cmd.appRepo.ReadFromSpace(targetName, targetSpace.Guid)
+	if apiResponse.IsNotSuccessful() {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	if source.Guid == target.Guid {
+		cmd.ui.Failed("Source and target are the same app")
+		return
+	}
+
+	summary, apiResponse := cmd.appSummaryRepo.GetSummary(source.Guid)
+	if apiResponse.IsNotSuccessful() {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	results := []configItemResult{}
+	if copyAll || c.Bool("env") {
+		results = append(results, cmd.copyEnv(source, target))
+	}
+	if copyAll || c.Bool("services") {
+		results = append(results, cmd.copyServices(summary, target, targetSpace)...)
+	}
+	if copyAll || c.Bool("scale") {
+		results = append(results, cmd.copyScale(source, target))
+	}
+	if copyAll || c.Bool("routes") {
+		results = append(results, cmd.copyRoutes(summary, target, targetSpace)...)
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	cmd.showResults(results, target)
+}
+
+func (cmd *CopyAppConfig) copyEnv(source, target models.Application) (result configItemResult) {
+	result.item = "env"
+
+	env := map[string]string{}
+	for key, value := range target.EnvironmentVars {
+		env[key] = value
+	}
+	for key, value := range source.EnvironmentVars {
+		env[key] = value
+	}
+
+	changes := diffEnv(target.EnvironmentVars, env)
+	if changes.isEmpty() {
+		result.result = configItemUpToDate
+		result.details = fmt.Sprintf("%d variables", len(source.EnvironmentVars))
+		return
+	}
+
+	_, apiResponse := cmd.appRepo.Update(target.Guid, models.AppParams{EnvironmentVars: &env})
+	if apiResponse.IsNotSuccessful() {
+		result.result = configItemFailed
+		result.details = apiResponse.Message
+		return
+	}
+
+	result.result = configItemCopied
+	result.details = fmt.Sprintf("%d added, %d changed", len(changes.added), len(changes.changed))
+	return
+}
+
+func (cmd *CopyAppConfig) copyServices(summary models.AppSummary, target models.Application, targetSpace models.SpaceFields) (results []configItemResult) {
+	for _, serviceName := range summary.ServiceNames {
+		result := configItemResult{item: "service " + serviceName}
+
+		instance, apiResponse := cmd.serviceRepo.FindInstanceByNameInSpace(serviceName, targetSpace.Guid)
+		switch {
+		case apiResponse.IsNotFound():
+			result.result = configItemFailed
+			result.details = fmt.Sprintf("not found in space %s", targetSpace.Name)
+		case apiResponse.IsNotSuccessful():
+			result.result = configItemFailed
+			result.details = apiResponse.Message
+		default:
+			result.result, result.details = cmd.bindService(instance, target)
+		}
+
+		results = append(results, result)
+	}
+	return
+}
+
+func (cmd *CopyAppConfig) bindService(instance models.ServiceInstance, target models.Application) (result, details string) {
+	apiResponse := cmd.bindingRepo.Create(instance.Guid, target.Guid)
+	switch {
+	case apiResponse.ErrorCode == service.AppAlreadyBoundErrorCode:
+		return configItemUpToDate, "already bound"
+	case apiResponse.IsNotSuccessful():
+		return configItemFailed, apiResponse.Message
+	}
+	return configItemCopied, "bound"
+}
+
+func (cmd *CopyAppConfig) copyScale(source, target models.Application) (result configItemResult) {
+	result.item = "scale"
+	result.details = fmt.Sprintf("%d x %s", source.InstanceCount, formatters.ByteSize(source.Memory*formatters.MEGABYTE))
+
+	if source.InstanceCount == target.InstanceCount && source.Memory == target.Memory {
+		result.result = configItemUpToDate
+		return
+	}
+
+	params := models.AppParams{InstanceCount: &source.InstanceCount, Memory: &source.Memory}
+	_, apiResponse := cmd.appRepo.Update(target.Guid, params)
+	if apiResponse.IsNotSuccessful() {
+		result.result = configItemFailed
+		result.details = apiResponse.Message
+		return
+	}
+
+	result.result = configItemCopied
+	return
+}
+
+func (cmd *CopyAppConfig) copyRoutes(summary models.AppSummary, target models.Application, targetSpace models.SpaceFields) (results []configItemResult) {
+	for _, route := range summary.RouteSummaries {
+		result := configItemResult{item: "route " + route.URL()}
+
+		switch {
+		case isRouteBoundToApp(route, target):
+			result.result = configItemUpToDate
+			result.details = "already bound"
+		case cmd.config.SpaceFields().Guid != targetSpace.Guid:
+			// a route belongs to a single space, so it cannot follow the app
+			result.result = configItemSkipped
+			result.details = fmt.Sprintf("route is not in space %s", targetSpace.Name)
+		default:
+			result.result, result.details = cmd.bindRoute(route, target)
+		}
+
+		results = append(results, result)
+	}
+	return
+}
+
+func (cmd *CopyAppConfig) bindRoute(route models.RouteSummary, target models.Application) (result, details string) {
+	apiResponse := cmd.routeRepo.Bind(route.Guid, target.Guid)
+	if apiResponse.IsNotSuccessful() {
+		return configItemFailed, apiResponse.Message
+	}
+	return configItemCopied, "bound"
+}
+
+func isRouteBoundToApp(route models.RouteSummary, app models.Application) bool {
+	for _, boundRoute := range app.Routes {
+		if boundRoute.Guid == route.Guid {
+			return true
+		}
+	}
+	return false
+}
+
+func (cmd *CopyAppConfig) showResults(results []configItemResult, target models.Application) {
+	table := cmd.ui.Table([]string{"item", "result", "details"})
+	rows := [][]string{}
+
+	failedCount := 0
+	for _, result := range results {
+		var coloredResult string
+		switch result.result {
+		case configItemCopied, configItemUpToDate:
+			coloredResult = terminal.SuccessColor(result.result)
+		case configItemSkipped:
+			coloredResult = terminal.WarningColor(result.result)
+		default:
+			coloredResult = terminal.FailureColor(result.result)
+			failedCount++
+		}
+		rows = append(rows, []string{result.item, coloredResult, result.details})
+	}
+
+	table.Print(rows)
+
+	if failedCount > 0 {
+		cmd.ui.Say("")
+		cmd.ui.Failed("%d of %d items were not copied", failedCount, len(results))
+		return
+	}
+
+	cmd.ui.Say("")
+	cmd.ui.Say("TIP: Use '%s restart %s' to ensure your changes take effect", cf.Name(), target.Name)
+}
@@ -0,0 +1,183 @@
+package application_test
+
+import (
+	. "cf/commands/application"
+	"cf/commands/service"
+	"cf/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	testapi "testhelpers/api"
+	testassert "testhelpers/assert"
+	testcmd "testhelpers/commands"
+	testconfig "testhelpers/configuration"
+	testreq "testhelpers/requirements"
+	testterm "testhelpers/terminal"
+)
+
+var _ = Describe("copy-app-config command", func() {
+	var (
+		reqFactory     *testreq.FakeReqFactory
+		appRepo        *testapi.FakeApplicationRepository
+		appSummaryRepo *testapi.FakeAppSummaryRepo
+		spaceRepo      *testapi.FakeSpaceRepository
+		serviceRepo    *testapi.FakeServiceRepo
+		bindingRepo    *testapi.FakeServiceBindingRepo
+		routeRepo      *testapi.FakeRouteRepository
+	)
+
+	BeforeEach(func() {
+		source := models.Application{}
+		source.Name = "my-app"
+		source.Guid = "my-app-guid"
+		source.InstanceCount = 3
+		source.Memory = 512
+		source.EnvironmentVars = map[string]string{"A": "1", "B": "2"}
+
+		boundRoute := models.RouteSummary{}
+		boundRoute.Guid = "bound-route-guid"
+
+		target := models.Application{}
+		target.Name = "my-copy"
+		target.Guid = "my-copy-guid"
+		target.InstanceCount = 1
+		target.Memory = 256
+		target.EnvironmentVars = map[string]string{"B": "old", "C": "3"}
+		target.Routes = []models.RouteSummary{boundRoute}
+
+		appRepo = &testapi.FakeApplicationRepository{
+			ReadAppsByName: map[string]models.Application{"my-app": source, "my-copy": target},
+		}
+
+		route := models.RouteSummary{}
+		route.Guid = "route-guid"
+		route.Host = "my-app"
+		route.Domain.Name = "example.com"
+		boundRoute.Host = "my-copy"
+		boundRoute.Domain.Name = "example.com"
+
+		summary := models.AppSummary{}
+		summary.Guid = "my-app-guid"
+		summary.ServiceNames = []string{"my-db"}
+		summary.RouteSummaries = []models.RouteSummary{route, boundRoute}
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{GetSummarySummary: summary}
+
+		otherSpace := models.Space{}
+		otherSpace.Name = "other-space"
+		otherSpace.Guid = "other-space-guid"
+		spaceRepo = &testapi.FakeSpaceRepository{Spaces: []models.Space{otherSpace}}
+
+		instance := models.ServiceInstance{}
+		instance.Name = "my-db"
+		instance.Guid = "my-db-guid"
+		serviceRepo = &testapi.FakeServiceRepo{FindInstanceByNameServiceInstance: instance}
+
+		bindingRepo = &testapi.FakeServiceBindingRepo{}
+		routeRepo = &testapi.FakeRouteRepository{}
+		reqFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
+	})
+
+	callCopyAppConfig := func(args []string) (ui *testterm.FakeUI) {
+		ui = new(testterm.FakeUI)
+		cmd := NewCopyAppConfig(ui, testconfig.NewRepositoryWithDefaults(), appRepo, appSummaryRepo, spaceRepo, serviceRepo, bindingRepo, routeRepo)
+		testcmd.RunCommand(cmd, testcmd.NewContext("copy-app-config", args), reqFactory)
+		return
+	}
+
+	It("fails with usage without a source and a target app", func() {
+		ui := callCopyAppConfig([]string{"my-app"})
+		Expect(ui.FailedWithUsage).To(BeTrue())
+		Expect(testcmd.CommandDidPassRequirements).To(BeFalse())
+	})
+
+	It("copies env, services, scale and routes when no part is selected", func() {
+		ui := callCopyAppConfig([]string{"my-app", "my-copy"})
+
+		Expect(appSummaryRepo.GetSummaryAppGuid).To(Equal("my-app-guid"))
+		Expect(appRepo.ReadFromSpaceGuids).To(Equal([]string{"my-space-guid"}))
+
+		Expect(appRepo.UpdatedAppGuids).To(Equal([]string{"my-copy-guid", "my-copy-guid"}))
+		Expect(*appRepo.UpdatedParams[0].EnvironmentVars).To(Equal(map[string]string{"A": "1", "B": "2", "C": "3"}))
+		Expect(*appRepo.UpdatedParams[1].InstanceCount).To(Equal(3))
+		Expect(*appRepo.UpdatedParams[1].Memory).To(Equal(uint64(512)))
+
+		Expect(serviceRepo.FindInstanceByNameInSpaceGuid).To(Equal("my-space-guid"))
+		Expect(bindingRepo.CreatedInstanceGuids).To(Equal([]string{"my-db-guid"}))
+		Expect(bindingRepo.CreateApplicationGuid).To(Equal("my-copy-guid"))
+
+		Expect(routeRepo.BoundRouteGuids).To(Equal([]string{"route-guid"}))
+		Expect(routeRepo.BoundAppGuid).To(Equal("my-copy-guid"))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Copying config", "my-app", "my-copy", "my-org", "my-space", "my-user"},
+			{"OK"},
+			{"item", "result", "details"},
+			{"env", "copied", "1 added, 1 changed"},
+			{"service my-db", "copied", "bound"},
+			{"scale", "copied", "3 x 512M"},
+			{"route my-app.example.com", "copied", "bound"},
+			{"route my-copy.example.com", "up to date", "already bound"},
+			{"TIP", "restart my-copy"},
+		})
+	})
+
+	It("only copies the selected parts", func() {
+		ui := callCopyAppConfig([]string{"--scale", "my-app", "my-copy"})
+
+		Expect(appRepo.UpdatedParams).To(HaveLen(1))
+		Expect(appRepo.UpdatedParams[0].EnvironmentVars).To(BeNil())
+		Expect(bindingRepo.CreatedInstanceGuids).To(BeEmpty())
+		Expect(routeRepo.BoundRouteGuids).To(BeEmpty())
+
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"env"},
+			{"service my-db"},
+		})
+	})
+
+	It("reports services that are already bound as up to date", func() {
+		bindingRepo.CreateErrorCode = service.AppAlreadyBoundErrorCode
+
+		ui := callCopyAppConfig([]string{"--services", "my-app", "my-copy"})
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"service my-db", "up to date", "already bound"},
+		})
+	})
+
+	It("looks up the target app and services in another space and skips the routes", func() {
+		ui := callCopyAppConfig([]string{"-s", "other-space", "--services", "--routes", "my-app", "my-copy"})
+
+		Expect(appRepo.ReadFromSpaceGuids).To(Equal([]string{"other-space-guid"}))
+		Expect(serviceRepo.FindInstanceByNameInSpaceGuid).To(Equal("other-space-guid"))
+		Expect(routeRepo.BoundRouteGuids).To(BeEmpty())
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Copying config", "my-app", "my-copy", "other-space"},
+			{"route my-app.example.com", "skipped", "not in space other-space"},
+		})
+	})
+
+	It("fails after showing the results when an item could not be copied", func() {
+		serviceRepo.FindInstanceByNameNotFound = true
+
+		ui := callCopyAppConfig([]string{"--services", "--env", "my-app", "my-copy"})
+
+		Expect(appRepo.UpdatedParams).To(HaveLen(1))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"env", "copied"},
+			{"service my-db", "failed", "not found in space my-space"},
+			{"FAILED"},
+			{"1 of 2 items were not copied"},
+		})
+	})
+
+	It("fails when the source app does not exist", func() {
+		ui := callCopyAppConfig([]string{"missing-app", "my-copy"})
+
+		Expect(appRepo.UpdatedParams).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"missing-app", "not found"},
+		})
+	})
+})
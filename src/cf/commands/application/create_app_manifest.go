@@ -0,0 +1,124 @@
+package application
+
+import (
+	"cf/api"
+	"cf/configuration"
+	"cf/manifest"
+	"cf/models"
+	"cf/requirements"
+	"cf/terminal"
+	"errors"
+	"fmt"
+	"github.com/codegangsta/cli"
+	"io/ioutil"
+	"os"
+)
+
+type CreateAppManifest struct {
+	ui             terminal.UI
+	config         configuration.Reader
+	appSummaryRepo api.AppSummaryRepository
+	appReq         requirements.ApplicationRequirement
+}
+
+func NewCreateAppManifest(ui terminal.UI, config configuration.Reader, appSummaryRepo api.AppSummaryRepository) (cmd *CreateAppManifest) {
+	cmd = new(CreateAppManifest)
+	cmd.ui = ui
+	cmd.config = config
+	cmd.appSummaryRepo = appSummaryRepo
+	return
+}
+
+func (cmd *CreateAppManifest) GetRequirements(reqFactory requirements.Factory, c *cli.Context) (reqs []requirements.Requirement, err error) {
+	if len(c.Args()) != 1 {
+		err = errors.New("Incorrect Usage")
+		cmd.ui.FailWithUsage(c, "create-app-manifest")
+		return
+	}
+
+	cmd.appReq = reqFactory.NewApplicationRequirement(c.Args()[0])
+	reqs = []requirements.Requirement{
+		reqFactory.NewLoginRequirement(),
+		reqFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+	return
+}
+
+func (cmd *CreateAppManifest) Run(c *cli.Context) {
+	app := cmd.appReq.GetApplication()
+
+	path := c.String("p")
+	if path == "" {
+		path = fmt.Sprintf("./%s_manifest.yml", app.Name)
+	}
+
+	if _, err := os.Stat(path); err == nil && !c.Bool("f") {
+		response := cmd.ui.Confirm(
+			"Really overwrite %s?%s",
+			terminal.EntityNameColor(path),
+			terminal.PromptColor(">"),
+		)
+		if !response {
+			return
+		}
+	}
+
+	cmd.ui.Say("Creating an app manifest from current settings of app %s in org %s / space %s as %s...",
+		terminal.EntityNameColor(app.Name),
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+	)
+
+	summary, apiResponse := cmd.appSummaryRepo.GetSummary(app.Guid)
+	if apiResponse.IsNotSuccessful() {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	params := appManifestParams(app, summary)
+
+	// the manifest has the environment variables of the app, which often
+	// hold credentials
+	yaml := manifest.RenderYAML(manifest.GenerateManifest([]models.AppParams{params}))
+	err := ioutil.WriteFile(path, []byte(yaml), 0600)
+	if err != nil {
+		cmd.ui.Failed("Error creating manifest file: %s", err)
+		return
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("Manifest file created successfully at %s\n", terminal.EntityNameColor(path))
+}
+
+func appManifestParams(app models.Application, summary models.AppSummary) (params models.AppParams) {
+	params.Name = &app.Name
+	params.Memory = &app.Memory
+	params.DiskQuota = &summary.DiskQuota
+	params.InstanceCount = &app.InstanceCount
+	params.BuildpackUrl = &app.BuildpackUrl
+	params.Command = &app.Command
+	params.StackName = &app.Stack.Name
+	params.EnvironmentVars = &app.EnvironmentVars
+	params.Services = &summary.ServiceNames
+
+	// a route on the bare domain has no host, which a manifest can only
+	// describe as a route, the domain alone binds the host named after the app
+	switch {
+	case len(summary.RouteSummaries) == 0:
+		noRoute := true
+		params.NoRoute = &noRoute
+	case len(summary.RouteSummaries) == 1 && summary.RouteSummaries[0].Host != "":
+		route := summary.RouteSummaries[0]
+		params.Host = &route.Host
+		params.Domain = &route.Domain.Name
+	default:
+		routes := []string{}
+		for _, route := range summary.RouteSummaries {
+			routes = append(routes, route.URL())
+		}
+		params.Routes = &routes
+	}
+	return
+}
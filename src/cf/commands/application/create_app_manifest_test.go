@@ -0,0 +1,218 @@
+package application_test
+
+import (
+	. "cf/commands/application"
+	"cf/manifest"
+	"cf/models"
+	"fileutils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	testapi "testhelpers/api"
+	testassert "testhelpers/assert"
+	testcmd "testhelpers/commands"
+	testconfig "testhelpers/configuration"
+	testreq "testhelpers/requirements"
+	testterm "testhelpers/terminal"
+)
+
+var _ = Describe("create-app-manifest command", func() {
+	var (
+		reqFactory     *testreq.FakeReqFactory
+		appSummaryRepo *testapi.FakeAppSummaryRepo
+	)
+
+	BeforeEach(func() {
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		app.Memory = 256
+		app.InstanceCount = 2
+		app.Command = "bundle exec rackup"
+		app.Stack = models.Stack{Name: "lucid64"}
+		app.EnvironmentVars = map[string]string{"RAILS_ENV": "production"}
+
+		reqFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: app}
+
+		route := models.RouteSummary{}
+		route.Host = "my-app-host"
+		route.Domain.Name = "example.com"
+
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{}
+		appSummaryRepo.GetSummarySummary.DiskQuota = 1024
+		appSummaryRepo.GetSummarySummary.RouteSummaries = []models.RouteSummary{route}
+		appSummaryRepo.GetSummarySummary.ServiceNames = []string{"my-db"}
+	})
+
+	It("fails with usage when no app name is given", func() {
+		ui := callCreateAppManifest([]string{}, reqFactory, appSummaryRepo)
+		Expect(ui.FailedWithUsage).To(BeTrue())
+	})
+
+	It("requires a login and a targeted space", func() {
+		reqFactory.TargetedSpaceSuccess = false
+		callCreateAppManifest([]string{"my-app"}, reqFactory, appSummaryRepo)
+		Expect(testcmd.CommandDidPassRequirements).To(BeFalse())
+		Expect(reqFactory.ApplicationName).To(Equal("my-app"))
+	})
+
+	It("writes the settings of the app to a manifest", func() {
+		fileutils.TempDir("create_app_manifest_test", func(dir string, err error) {
+			Expect(err).NotTo(HaveOccurred())
+			path := filepath.Join(dir, "manifest.yml")
+
+			ui := callCreateAppManifest([]string{"-p", path, "my-app"}, reqFactory, appSummaryRepo)
+
+			Expect(appSummaryRepo.GetSummaryAppGuid).To(Equal("my-app-guid"))
+			testassert.SliceContains(ui.Outputs, testassert.Lines{
+				{"Creating an app manifest", "my-app", "my-org", "my-space", "my-user"},
+				{"OK"},
+				{"Manifest file created successfully at", path},
+			})
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal(`---
+applications:
+- command: bundle exec rackup
+  disk_quota: 1024M
+  domain: example.com
+  env:
+    RAILS_ENV: production
+  host: my-app-host
+  instances: 2
+  memory: 256M
+  name: my-app
+  services:
+  - my-db
+  stack: lucid64
+`))
+		})
+	})
+
+	It("writes the manifest so that only the user can read it", func() {
+		fileutils.TempDir("create_app_manifest_test", func(dir string, err error) {
+			path := filepath.Join(dir, "manifest.yml")
+			callCreateAppManifest([]string{"-p", path, "my-app"}, reqFactory, appSummaryRepo)
+
+			info, err := os.Stat(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+	})
+
+	It("asks before overwriting an existing manifest", func() {
+		fileutils.TempDir("create_app_manifest_test", func(dir string, err error) {
+			path := filepath.Join(dir, "manifest.yml")
+			err = ioutil.WriteFile(path, []byte("my own manifest"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			ui := callCreateAppManifestWithInputs([]string{"-p", path, "my-app"}, []string{"n"}, reqFactory, appSummaryRepo)
+
+			Expect(ui.Prompts).To(ContainElement(ContainSubstring("Really overwrite")))
+			Expect(appSummaryRepo.GetSummaryAppGuid).To(Equal(""))
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("my own manifest"))
+
+			callCreateAppManifestWithInputs([]string{"-p", path, "my-app"}, []string{"y"}, reqFactory, appSummaryRepo)
+
+			contents, err = ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("name: my-app"))
+		})
+	})
+
+	It("overwrites an existing manifest without asking with -f", func() {
+		fileutils.TempDir("create_app_manifest_test", func(dir string, err error) {
+			path := filepath.Join(dir, "manifest.yml")
+			err = ioutil.WriteFile(path, []byte("my own manifest"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			ui := callCreateAppManifest([]string{"-p", path, "-f", "my-app"}, reqFactory, appSummaryRepo)
+
+			Expect(ui.Prompts).To(BeEmpty())
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("name: my-app"))
+		})
+	})
+
+	It("marks apps without routes as workers", func() {
+		appSummaryRepo.GetSummarySummary.RouteSummaries = []models.RouteSummary{}
+
+		fileutils.TempDir("create_app_manifest_test", func(dir string, err error) {
+			path := filepath.Join(dir, "manifest.yml")
+			callCreateAppManifest([]string{"-p", path, "my-app"}, reqFactory, appSummaryRepo)
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("  no-route: true\n"))
+			Expect(string(contents)).NotTo(ContainSubstring("host:"))
+		})
+	})
+
+	It("lists the routes of apps that have more than one", func() {
+		apiRoute := models.RouteSummary{}
+		apiRoute.Host = "api"
+		apiRoute.Domain.Name = "example.com"
+		rootRoute := models.RouteSummary{}
+		rootRoute.Domain.Name = "internal.example.com"
+		appSummaryRepo.GetSummarySummary.RouteSummaries = []models.RouteSummary{apiRoute, rootRoute}
+
+		fileutils.TempDir("create_app_manifest_test", func(dir string, err error) {
+			path := filepath.Join(dir, "manifest.yml")
+			callCreateAppManifest([]string{"-p", path, "my-app"}, reqFactory, appSummaryRepo)
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("  routes:\n  - route: api.example.com\n  - route: internal.example.com\n"))
+			Expect(string(contents)).NotTo(ContainSubstring("host:"))
+		})
+	})
+
+	It("writes a route on the bare domain so that pushing the manifest binds it again", func() {
+		rootRoute := models.RouteSummary{}
+		rootRoute.Domain.Name = "example.com"
+		appSummaryRepo.GetSummarySummary.RouteSummaries = []models.RouteSummary{rootRoute}
+
+		fileutils.TempDir("create_app_manifest_test", func(dir string, err error) {
+			path := filepath.Join(dir, "manifest.yml")
+			callCreateAppManifest([]string{"-p", path, "my-app"}, reqFactory, appSummaryRepo)
+
+			m, _, errs := manifest.NewManifestDiskRepository().ReadManifest([]string{path}, manifest.ManifestVars{})
+			Expect(errs).To(BeEmpty())
+			Expect(m.Applications).To(HaveLen(1))
+
+			app := m.Applications[0]
+			Expect(*app.Routes).To(Equal([]string{"example.com"}))
+			Expect(app.Host).To(BeNil())
+			Expect(app.Domain).To(BeNil())
+		})
+	})
+
+	It("fails when the app summary cannot be read", func() {
+		appSummaryRepo.GetSummaryErrorCode = "123"
+
+		ui := callCreateAppManifest([]string{"my-app"}, reqFactory, appSummaryRepo)
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+		})
+	})
+})
+
+func callCreateAppManifest(args []string, reqFactory *testreq.FakeReqFactory, appSummaryRepo *testapi.FakeAppSummaryRepo) (ui *testterm.FakeUI) {
+	return callCreateAppManifestWithInputs(args, []string{}, reqFactory, appSummaryRepo)
+}
+
+func callCreateAppManifestWithInputs(args []string, inputs []string, reqFactory *testreq.FakeReqFactory, appSummaryRepo *testapi.FakeAppSummaryRepo) (ui *testterm.FakeUI) {
+	ui = &testterm.FakeUI{Inputs: inputs}
+	ctxt := testcmd.NewContext("create-app-manifest", args)
+
+	configRepo := testconfig.NewRepositoryWithDefaults()
+	cmd := NewCreateAppManifest(ui, configRepo, appSummaryRepo)
+	testcmd.RunCommand(cmd, ctxt, reqFactory)
+	return
+}
@@ -0,0 +1,90 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// parseDotenv reads variables in the dotenv format: KEY=VALUE lines, with
+// optional 'export ' prefixes, blank lines and # comments. Single quoted
+// values are taken literally, double quoted values understand \n, \t, \"
+// and \\ escapes, and both kinds of quoted values may span several lines.
+func parseDotenv(content string) (vars map[string]string, err error) {
+	vars = map[string]string{}
+	lines := strings.Split(strings.Replace(content, "\r\n", "\n", -1), "\n")
+
+	for lineIndex := 0; lineIndex < len(lines); lineIndex++ {
+		lineNumber := lineIndex + 1
+		line := strings.TrimSpace(lines[lineIndex])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		separator := strings.Index(line, "=")
+		if separator < 1 {
+			err = errors.New(fmt.Sprintf("line %d: expected KEY=VALUE", lineNumber))
+			return
+		}
+
+		key := strings.TrimSpace(line[:separator])
+		if strings.ContainsAny(key, " \t\"'") {
+			err = errors.New(fmt.Sprintf("line %d: invalid key '%s'", lineNumber, key))
+			return
+		}
+
+		value := strings.TrimLeft(line[separator+1:], " \t")
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			vars[key] = unquotedDotenvValue(value)
+			continue
+		}
+
+		quote := value[0]
+		rest := value[1:]
+		for {
+			closing := closingQuoteIndex(rest, quote)
+			if closing >= 0 {
+				value = rest[:closing]
+				break
+			}
+
+			lineIndex++
+			if lineIndex >= len(lines) {
+				err = errors.New(fmt.Sprintf("line %d: missing closing quote for %s", lineNumber, key))
+				return
+			}
+			rest = rest + "\n" + lines[lineIndex]
+		}
+
+		if quote == '"' {
+			value = unescapeDotenvValue(value)
+		}
+		vars[key] = value
+	}
+	return
+}
+
+func unquotedDotenvValue(value string) string {
+	if comment := strings.Index(value, " #"); comment >= 0 {
+		value = value[:comment]
+	}
+	return strings.TrimSpace(value)
+}
+
+func closingQuoteIndex(value string, quote byte) int {
+	for index := 0; index < len(value); index++ {
+		switch {
+		case value[index] == '\\' && quote == '"':
+			index++
+		case value[index] == quote:
+			return index
+		}
+	}
+	return -1
+}
+
+func unescapeDotenvValue(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\r`, "\r", `\"`, `"`, `\\`, `\`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,367 @@
+package application
+
+import (
+	"cf/api"
+	"cf/configuration"
+	"cf/formatters"
+	"cf/net"
+	"cf/requirements"
+	"cf/terminal"
+	"errors"
+	"github.com/codegangsta/cli"
+	"glob"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type Download struct {
+	ui           terminal.UI
+	config       configuration.Reader
+	appFilesRepo api.AppFilesRepository
+	appReq       requirements.ApplicationRequirement
+}
+
+// remoteFile is a path on an app instance, relative to the root of the
+// instance and without a leading slash
+type remoteFile struct {
+	path  string
+	isDir bool
+}
+
+type fileDownload struct {
+	remotePath string
+	localPath  string
+}
+
+type fileDownloadResult struct {
+	fileDownload
+	size uint64
+	err  error
+}
+
+func NewDownload(ui terminal.UI, config configuration.Reader, appFilesRepo api.AppFilesRepository) (cmd *Download) {
+	cmd = new(Download)
+	cmd.ui = ui
+	cmd.config = config
+	cmd.appFilesRepo = appFilesRepo
+	return
+}
+
+func (cmd *Download) GetRequirements(reqFactory requirements.Factory, c *cli.Context) (reqs []requirements.Requirement, err error) {
+	if len(c.Args()) < 2 || len(c.Args()) > 3 {
+		err = errors.New("Incorrect Usage")
+		cmd.ui.FailWithUsage(c, "download")
+		return
+	}
+
+	cmd.appReq = reqFactory.NewApplicationRequirement(c.Args()[0])
+
+	reqs = []requirements.Requirement{
+		reqFactory.NewLoginRequirement(),
+		reqFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+	return
+}
+
+func (cmd *Download) Run(c *cli.Context) {
+	app := cmd.appReq.GetApplication()
+	instance := c.Int("i")
+
+	localDir := "."
+	if len(c.Args()) > 2 {
+		localDir = c.Args()[2]
+	}
+
+	concurrency := c.Int("concurrency")
+	if concurrency < 1 {
+		cmd.ui.Failed("Invalid concurrency: %d\nExpected a number of files to download at once", concurrency)
+		return
+	}
+
+	filter, err := newDownloadFilter(c.StringSlice("include"), c.StringSlice("exclude"))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+
+	cmd.ui.Say("Downloading files of app %s instance #%d in org %s / space %s as %s...",
+		terminal.EntityNameColor(app.Name),
+		instance,
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+	)
+
+	remotePath := strings.Trim(path.Clean("/"+c.Args()[1]), "/")
+
+	isDir, apiResponse := cmd.isRemoteDir(app.Guid, instance, remotePath, c.Args()[1])
+	if apiResponse.IsNotSuccessful() {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	var downloads []fileDownload
+	if isDir {
+		// like cp -r, a directory is downloaded into a directory of the same name
+		root := localDir
+		if remotePath != "" {
+			root = filepath.Join(localDir, path.Base(remotePath))
+		}
+		downloads, apiResponse = cmd.listDownloads(app.Guid, instance, remotePath, root, filter)
+		if apiResponse.IsNotSuccessful() {
+			cmd.ui.Failed(apiResponse.Message)
+			return
+		}
+	} else {
+		downloads = []fileDownload{{
+			remotePath: remotePath,
+			localPath:  filepath.Join(localDir, path.Base(remotePath)),
+		}}
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	if len(downloads) == 0 {
+		cmd.ui.Say("No files to download")
+		return
+	}
+
+	cmd.downloadFiles(app.Guid, instance, downloads, concurrency, localDir)
+}
+
+func (cmd *Download) isRemoteDir(appGuid string, instance int, remotePath, arg string) (isDir bool, apiResponse net.ApiResponse) {
+	if remotePath == "" || strings.HasSuffix(arg, "/") {
+		isDir = true
+		return
+	}
+
+	parent := path.Dir(remotePath)
+	if parent == "." {
+		parent = ""
+	}
+
+	listing, apiResponse := cmd.appFilesRepo.ListInstanceFiles(appGuid, instance, listingPath(parent))
+	if apiResponse.IsNotSuccessful() {
+		return
+	}
+
+	for _, file := range parseFileListing(parent, listing) {
+		if file.path == remotePath {
+			isDir = file.isDir
+			return
+		}
+	}
+
+	apiResponse = net.NewNotFoundApiResponse("%s %s not found", "Path", arg)
+	return
+}
+
+func (cmd *Download) listDownloads(appGuid string, instance int, remoteDir, localRoot string, filter downloadFilter) (downloads []fileDownload, apiResponse net.ApiResponse) {
+	var walk func(dir string) net.ApiResponse
+	walk = func(dir string) net.ApiResponse {
+		listing, apiResponse := cmd.appFilesRepo.ListInstanceFiles(appGuid, instance, listingPath(dir))
+		if apiResponse.IsNotSuccessful() {
+			return apiResponse
+		}
+
+		for _, file := range parseFileListing(dir, listing) {
+			relativePath := strings.TrimPrefix(strings.TrimPrefix(file.path, remoteDir), "/")
+
+			if file.isDir {
+				if filter.excludes(relativePath) {
+					continue
+				}
+				apiResponse = walk(file.path)
+				if apiResponse.IsNotSuccessful() {
+					return apiResponse
+				}
+				continue
+			}
+
+			if !filter.includes(relativePath) {
+				continue
+			}
+			downloads = append(downloads, fileDownload{
+				remotePath: file.path,
+				localPath:  filepath.Join(localRoot, filepath.FromSlash(relativePath)),
+			})
+		}
+		return apiResponse
+	}
+
+	apiResponse = walk(remoteDir)
+	return
+}
+
+// downloadFiles downloads up to concurrency files at once, showing each file
+// as soon as it is done.
+func (cmd *Download) downloadFiles(appGuid string, instance int, downloads []fileDownload, concurrency int, localDir string) {
+	pending := make(chan fileDownload)
+	finished := make(chan fileDownloadResult)
+
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			for download := range pending {
+				finished <- cmd.downloadFile(appGuid, instance, download)
+			}
+		}()
+	}
+
+	go func() {
+		for _, download := range downloads {
+			pending <- download
+		}
+		close(pending)
+	}()
+
+	var totalSize uint64
+	failedCount := 0
+	for i := 0; i < len(downloads); i++ {
+		result := <-finished
+		if result.err != nil {
+			failedCount++
+			cmd.ui.Say("%s %s: %s", terminal.FailureColor("failed"), result.remotePath, result.err.Error())
+			continue
+		}
+
+		totalSize += result.size
+		cmd.ui.Say("%s (%s)", result.remotePath, formatters.ByteSize(result.size))
+	}
+
+	cmd.ui.Say("")
+	if failedCount > 0 {
+		cmd.ui.Failed("%d of %d files could not be downloaded", failedCount, len(downloads))
+		return
+	}
+
+	cmd.ui.Say("Downloaded %d files (%s) to %s", len(downloads), formatters.ByteSize(totalSize), terminal.EntityNameColor(localDir))
+}
+
+func (cmd *Download) downloadFile(appGuid string, instance int, download fileDownload) (result fileDownloadResult) {
+	result.fileDownload = download
+
+	result.err = os.MkdirAll(filepath.Dir(download.localPath), 0755)
+	if result.err != nil {
+		return
+	}
+
+	file, err := os.Create(download.localPath)
+	if err != nil {
+		result.err = err
+		return
+	}
+
+	writer := &countingWriter{writer: file}
+	apiResponse := cmd.appFilesRepo.DownloadFile(appGuid, instance, download.remotePath, writer)
+	file.Close()
+
+	if apiResponse.IsNotSuccessful() {
+		os.Remove(download.localPath)
+		result.err = errors.New(apiResponse.Message)
+		return
+	}
+
+	result.size = writer.count
+	return
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  uint64
+}
+
+func (w *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = w.writer.Write(p)
+	w.count += uint64(n)
+	return
+}
+
+func listingPath(dir string) string {
+	if dir == "" {
+		return "/"
+	}
+	return dir + "/"
+}
+
+// parseFileListing reads the listing of a directory, where every line holds
+// a name and a size, and directory names end with a slash.
+func parseFileListing(dir, listing string) (files []remoteFile) {
+	for _, line := range strings.Split(listing, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name := line
+		if index := strings.LastIndexAny(line, " \t"); index > 0 {
+			name = strings.TrimSpace(line[:index])
+		}
+
+		isDir := strings.HasSuffix(name, "/")
+		name = strings.TrimSuffix(name, "/")
+		if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+			continue
+		}
+
+		files = append(files, remoteFile{
+			path:  strings.TrimPrefix(path.Join(dir, name), "/"),
+			isDir: isDir,
+		})
+	}
+	return
+}
+
+type downloadFilter struct {
+	includeGlobs []glob.Glob
+	excludeGlobs []glob.Glob
+}
+
+func newDownloadFilter(includes, excludes []string) (filter downloadFilter, err error) {
+	filter.includeGlobs, err = compileGlobs(includes)
+	if err != nil {
+		return
+	}
+	filter.excludeGlobs, err = compileGlobs(excludes)
+	return
+}
+
+func compileGlobs(patterns []string) (globs []glob.Glob, err error) {
+	for _, pattern := range patterns {
+		var g glob.Glob
+		g, err = glob.CompileGlob(pattern)
+		if err != nil {
+			return
+		}
+		globs = append(globs, g)
+	}
+	return
+}
+
+func (filter downloadFilter) includes(relativePath string) bool {
+	if filter.excludes(relativePath) {
+		return false
+	}
+	return len(filter.includeGlobs) == 0 || matchesAnyGlob(filter.includeGlobs, relativePath)
+}
+
+func (filter downloadFilter) excludes(relativePath string) bool {
+	return matchesAnyGlob(filter.excludeGlobs, relativePath)
+}
+
+// a pattern without a slash, like *.log, matches files in any directory
+func matchesAnyGlob(globs []glob.Glob, relativePath string) bool {
+	for _, g := range globs {
+		if g.Match(relativePath) {
+			return true
+		}
+		if !strings.Contains(g.Pattern, "/") && g.Match(path.Base(relativePath)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package application_test
+
+import (
+	. "cf/commands/application"
+	"cf/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	testapi "testhelpers/api"
+	testassert "testhelpers/assert"
+	testcmd "testhelpers/commands"
+	testconfig "testhelpers/configuration"
+	testreq "testhelpers/requirements"
+	testterm "testhelpers/terminal"
+)
+
+var _ = Describe("download command", func() {
+	var (
+		reqFactory   *testreq.FakeReqFactory
+		appFilesRepo *testapi.FakeAppFilesRepo
+		localDir     string
+	)
+
+	BeforeEach(func() {
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		reqFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: app}
+
+		appFilesRepo = &testapi.FakeAppFilesRepo{
+			Listings: map[string]string{
+				"/":        "app/                -\nlogs/               -\n",
+				"app/":     "server.rb           120B\nheap.dump           2K\ntmp/                -\n",
+				"app/tmp/": "cache.db            1K\n",
+				"logs/":    "staging.log         9B\nstdout.log          6B\n",
+			},
+			FileContents: map[string]string{
+				"app/server.rb":    "puts 'hi'",
+				"app/heap.dump":    "\x00\xff\x1f\x8b\r\n\x00",
+				"app/tmp/cache.db": "cache",
+				"logs/staging.log": "staged\n",
+				"logs/stdout.log":  "hello\n",
+			},
+		}
+
+		var err error
+		localDir, err = ioutil.TempDir("", "download-test")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(localDir)
+	})
+
+	callDownload := func(args []string) (ui *testterm.FakeUI) {
+		ui = new(testterm.FakeUI)
+		cmd := NewDownload(ui, testconfig.NewRepositoryWithDefaults(), appFilesRepo)
+		testcmd.RunCommand(cmd, testcmd.NewContext("download", args), reqFactory)
+		return
+	}
+
+	readLocalFile := func(name string) string {
+		bytes, err := ioutil.ReadFile(filepath.Join(localDir, name))
+		Expect(err).NotTo(HaveOccurred())
+		return string(bytes)
+	}
+
+	It("fails with usage without an app and a remote path", func() {
+		ui := callDownload([]string{"my-app"})
+		Expect(ui.FailedWithUsage).To(BeTrue())
+		Expect(testcmd.CommandDidPassRequirements).To(BeFalse())
+	})
+
+	It("downloads a directory tree of the instance, keeping its structure and binary content", func() {
+		ui := callDownload([]string{"-i", "2", "my-app", "app", localDir})
+
+		Expect(appFilesRepo.Instance).To(Equal(2))
+		Expect(readLocalFile("app/server.rb")).To(Equal("puts 'hi'"))
+		Expect(readLocalFile("app/heap.dump")).To(Equal("\x00\xff\x1f\x8b\r\n\x00"))
+		Expect(readLocalFile("app/tmp/cache.db")).To(Equal("cache"))
+
+		_, err := os.Stat(filepath.Join(localDir, "logs"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Downloading files", "my-app", "instance #2", "my-org", "my-space", "my-user"},
+			{"OK"},
+		})
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Downloaded 3 files", localDir},
+		})
+	})
+
+	It("downloads a single file into the local directory", func() {
+		ui := callDownload([]string{"my-app", "/logs/stdout.log", localDir})
+
+		Expect(appFilesRepo.DownloadPaths).To(Equal([]string{"logs/stdout.log"}))
+		Expect(readLocalFile("stdout.log")).To(Equal("hello\n"))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"logs/stdout.log", "(6)"},
+			{"Downloaded 1 files"},
+		})
+	})
+
+	It("includes and excludes files by glob", func() {
+		callDownload([]string{"--include", "*.log", "--include", "app/**", "--exclude", "tmp", "--concurrency", "1", "my-app", "/", localDir})
+
+		Expect(appFilesRepo.DownloadPaths).To(Equal([]string{"app/server.rb", "app/heap.dump", "logs/staging.log", "logs/stdout.log"}))
+		Expect(appFilesRepo.ListedPaths).NotTo(ContainElement("app/tmp/"))
+		Expect(readLocalFile("logs/staging.log")).To(Equal("staged\n"))
+	})
+
+	It("fails when the remote path does not exist", func() {
+		ui := callDownload([]string{"my-app", "missing.log", localDir})
+
+		Expect(appFilesRepo.DownloadPaths).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"missing.log", "not found"},
+		})
+	})
+
+	It("removes files that could not be downloaded and fails after the others are done", func() {
+		delete(appFilesRepo.FileContents, "logs/staging.log")
+
+		ui := callDownload([]string{"my-app", "logs/", localDir})
+
+		Expect(readLocalFile("logs/stdout.log")).To(Equal("hello\n"))
+		_, err := os.Stat(filepath.Join(localDir, "logs", "staging.log"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"1 of 2 files could not be downloaded"},
+		})
+	})
+})
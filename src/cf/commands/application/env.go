@@ -1,23 +1,34 @@
 package application
 
 import (
+	"cf/api"
 	"cf/configuration"
+	"cf/models"
 	"cf/requirements"
 	"cf/terminal"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/codegangsta/cli"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+const hiddenCredentials = "[PRIVATE DATA HIDDEN]"
+
 type Env struct {
-	ui     terminal.UI
-	config configuration.Reader
-	appReq requirements.ApplicationRequirement
+	ui      terminal.UI
+	config  configuration.Reader
+	appRepo api.ApplicationRepository
+	appReq  requirements.ApplicationRequirement
 }
 
-func NewEnv(ui terminal.UI, config configuration.Reader) (cmd *Env) {
+func NewEnv(ui terminal.UI, config configuration.Reader, appRepo api.ApplicationRepository) (cmd *Env) {
 	cmd = new(Env)
 	cmd.ui = ui
 	cmd.config = config
+	cmd.appRepo = appRepo
 	return
 }
 
@@ -28,6 +39,22 @@ func (cmd *Env) GetRequirements(reqFactory requirements.Factory, c *cli.Context)
 		return
 	}
 
+	switch c.String("export") {
+	case "", "shell", "dotenv":
+	default:
+		err = errors.New("Incorrect Usage")
+		cmd.ui.FailWithUsage(c, "env")
+		return
+	}
+
+	// exported with their credentials hidden, the services could not be
+	// used to run the app locally
+	if c.String("export") != "" && !c.Bool("show-secrets") {
+		err = errors.New("Exporting needs the credentials")
+		cmd.ui.Failed("--export needs --show-secrets, the credentials of the bound services are part of the exported variables")
+		return
+	}
+
 	cmd.appReq = reqFactory.NewApplicationRequirement(c.Args()[0])
 
 	reqs = []requirements.Requirement{
@@ -39,23 +66,142 @@ func (cmd *Env) GetRequirements(reqFactory requirements.Factory, c *cli.Context)
 
 func (cmd *Env) Run(c *cli.Context) {
 	app := cmd.appReq.GetApplication()
+	exportFormat := c.String("export")
+
+	if exportFormat == "" {
+		cmd.ui.Say("Getting env variables for app %s in org %s / space %s as %s...",
+			terminal.EntityNameColor(app.Name),
+			terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			terminal.EntityNameColor(cmd.config.Username()),
+		)
+	}
 
-	cmd.ui.Say("Getting env variables for app %s in org %s / space %s as %s...",
-		terminal.EntityNameColor(app.Name),
-		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
-		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
-		terminal.EntityNameColor(cmd.config.Username()),
-	)
-	envVars := app.EnvironmentVars
+	env, apiResponse := cmd.appRepo.ReadEnv(app.Guid)
+	if apiResponse.IsNotFound() {
+		// older cloud controllers cannot show the environment the system provides
+		env = models.Environment{User: map[string]interface{}{}}
+		for key, value := range app.EnvironmentVars {
+			env.User[key] = value
+		}
+	} else if apiResponse.IsNotSuccessful() {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	if !c.Bool("show-secrets") {
+		env.System = hideCredentials(env.System).(map[string]interface{})
+	}
+
+	if exportFormat != "" {
+		cmd.exportEnv(env, exportFormat)
+		return
+	}
 
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
-	if len(envVars) == 0 {
+	if len(env.System) == 0 && len(env.Application) == 0 && len(env.User) == 0 && len(env.Running) == 0 && len(env.Staging) == 0 {
 		cmd.ui.Say("No env variables exist")
 		return
 	}
-	for key, value := range envVars {
-		cmd.ui.Say("%s: %s", key, terminal.EntityNameColor(value))
+
+	if len(env.System) > 0 || len(env.Application) > 0 {
+		cmd.ui.Say(terminal.HeaderColor("System-Provided:"))
+		cmd.sayJSON(env.System)
+		cmd.sayJSON(env.Application)
+	}
+
+	cmd.sayVariables("User-Provided:", env.User, "No user-defined env variables have been set")
+	cmd.sayVariables("Running Environment Variable Groups:", env.Running, "No running env variables have been set")
+	cmd.sayVariables("Staging Environment Variable Groups:", env.Staging, "No staging env variables have been set")
+}
+
+func (cmd *Env) sayJSON(variables map[string]interface{}) {
+	if len(variables) == 0 {
+		return
 	}
+	bytes, err := json.MarshalIndent(variables, "", " ")
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+	cmd.ui.Say("%s\n", string(bytes))
+}
+
+func (cmd *Env) sayVariables(header string, variables map[string]interface{}, emptyMessage string) {
+	cmd.ui.Say(terminal.HeaderColor(header))
+	if len(variables) == 0 {
+		cmd.ui.Say("%s\n", emptyMessage)
+		return
+	}
+
+	for _, key := range sortedKeys(variables) {
+		cmd.ui.Say("%s: %s", key, terminal.EntityNameColor(envValueText(variables[key])))
+	}
+	cmd.ui.Say("")
+}
+
+// exportEnv prints the variables the running app sees, so that they can be
+// loaded to run the app locally. User-provided variables take precedence
+// over the running group, like they do on the platform.
+func (cmd *Env) exportEnv(env models.Environment, format string) {
+	variables := map[string]interface{}{}
+	for _, group := range []map[string]interface{}{env.Running, env.User, env.System, env.Application} {
+		for key, value := range group {
+			variables[key] = value
+		}
+	}
+
+	for _, key := range sortedKeys(variables) {
+		value := envValueText(variables[key])
+		if format == "dotenv" {
+			cmd.ui.Say("%s=%s", key, strconv.Quote(value))
+		} else {
+			cmd.ui.Say("export %s='%s'", key, strings.Replace(value, "'", `'\''`, -1))
+		}
+	}
+}
+
+// hideCredentials replaces the value of every credentials key found in
+// value, wherever it is nested.
+func hideCredentials(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		hidden := map[string]interface{}{}
+		for key, nested := range value {
+			if key == "credentials" {
+				hidden[key] = hiddenCredentials
+			} else {
+				hidden[key] = hideCredentials(nested)
+			}
+		}
+		return hidden
+	case []interface{}:
+		hidden := make([]interface{}, len(value))
+		for index, nested := range value {
+			hidden[index] = hideCredentials(nested)
+		}
+		return hidden
+	}
+	return value
+}
+
+func envValueText(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(bytes)
+}
+
+func sortedKeys(variables map[string]interface{}) (keys []string) {
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
 }
@@ -0,0 +1,80 @@
+package application
+
+import (
+	"cf"
+	"cf/api"
+	"cf/models"
+	"cf/terminal"
+	"sort"
+)
+
+const maskedEnvValue = "****"
+
+type envChanges struct {
+	added   []string
+	changed []string
+	removed []string
+}
+
+func diffEnv(before, after map[string]string) (changes envChanges) {
+	for key, value := range after {
+		previous, found := before[key]
+		switch {
+		case !found:
+			changes.added = append(changes.added, key)
+		case previous != value:
+			changes.changed = append(changes.changed, key)
+		}
+	}
+	for key := range before {
+		if _, found := after[key]; !found {
+			changes.removed = append(changes.removed, key)
+		}
+	}
+
+	sort.Strings(changes.added)
+	sort.Strings(changes.changed)
+	sort.Strings(changes.removed)
+	return
+}
+
+func (changes envChanges) isEmpty() bool {
+	return len(changes.added) == 0 && len(changes.changed) == 0 && len(changes.removed) == 0
+}
+
+// applyEnvChanges shows which variables are added, changed and removed,
+// without their values, and then replaces the variables of app with env in
+// a single update, unless it is a dry run.
+func applyEnvChanges(ui terminal.UI, appRepo api.ApplicationRepository, app models.Application, env map[string]string, dryRun bool) {
+	changes := diffEnv(app.EnvironmentVars, env)
+	if changes.isEmpty() {
+		ui.Ok()
+		ui.Say("No env variables changed")
+		return
+	}
+
+	for _, key := range changes.added {
+		ui.Say("+ %s=%s", terminal.EntityNameColor(key), maskedEnvValue)
+	}
+	for _, key := range changes.changed {
+		ui.Say("~ %s=%s", terminal.EntityNameColor(key), maskedEnvValue)
+	}
+	for _, key := range changes.removed {
+		ui.Say("- %s", terminal.EntityNameColor(key))
+	}
+
+	if dryRun {
+		ui.Say("\nDry run, %d added, %d changed and %d removed env variables were not applied",
+			len(changes.added), len(changes.changed), len(changes.removed))
+		return
+	}
+
+	_, apiResponse := appRepo.Update(app.Guid, models.AppParams{EnvironmentVars: &env})
+	if apiResponse.IsNotSuccessful() {
+		ui.Failed(apiResponse.Message)
+		return
+	}
+
+	ui.Ok()
+	ui.Say("TIP: Use '%s' to ensure your env variable changes take effect", terminal.CommandColor(cf.Name()+" push"))
+}
@@ -5,6 +5,7 @@ import (
 	"cf/models"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	testapi "testhelpers/api"
 	testassert "testhelpers/assert"
 	testcmd "testhelpers/commands"
 	testconfig "testhelpers/configuration"
@@ -17,18 +18,18 @@ var _ = Describe("Testing with ginkgo", func() {
 		reqFactory := getEnvDependencies()
 
 		reqFactory.LoginSuccess = true
-		callEnv([]string{"my-app"}, reqFactory)
+		callEnv([]string{"my-app"}, reqFactory, &testapi.FakeApplicationRepository{})
 		Expect(testcmd.CommandDidPassRequirements).To(BeTrue())
 		Expect(reqFactory.ApplicationName).To(Equal("my-app"))
 
 		reqFactory.LoginSuccess = false
-		callEnv([]string{"my-app"}, reqFactory)
+		callEnv([]string{"my-app"}, reqFactory, &testapi.FakeApplicationRepository{})
 		Expect(testcmd.CommandDidPassRequirements).To(BeFalse())
 	})
 	It("TestEnvFailsWithUsage", func() {
 
 		reqFactory := getEnvDependencies()
-		ui := callEnv([]string{}, reqFactory)
+		ui := callEnv([]string{}, reqFactory, &testapi.FakeApplicationRepository{})
 
 		Expect(ui.FailedWithUsage).To(BeTrue())
 		Expect(testcmd.CommandDidPassRequirements).To(BeFalse())
@@ -36,12 +37,15 @@ var _ = Describe("Testing with ginkgo", func() {
 	It("TestEnvListsEnvironmentVariables", func() {
 
 		reqFactory := getEnvDependencies()
-		reqFactory.Application.EnvironmentVars = map[string]string{
+		appRepo := &testapi.FakeApplicationRepository{}
+		appRepo.ReadEnvResult.User = map[string]interface{}{
 			"my-key":  "my-value",
 			"my-key2": "my-value2",
 		}
 
-		ui := callEnv([]string{"my-app"}, reqFactory)
+		ui := callEnv([]string{"my-app"}, reqFactory, appRepo)
+
+		Expect(appRepo.ReadEnvAppGuid).To(Equal("my-app-guid"))
 
 		testassert.SliceContains(ui.Outputs, testassert.Lines{
 			{"Getting env variables for app", "my-app", "my-org", "my-space", "my-user"},
@@ -52,9 +56,8 @@ var _ = Describe("Testing with ginkgo", func() {
 	It("TestEnvShowsEmptyMessage", func() {
 
 		reqFactory := getEnvDependencies()
-		reqFactory.Application.EnvironmentVars = map[string]string{}
 
-		ui := callEnv([]string{"my-app"}, reqFactory)
+		ui := callEnv([]string{"my-app"}, reqFactory, &testapi.FakeApplicationRepository{})
 
 		testassert.SliceContains(ui.Outputs, testassert.Lines{
 			{"Getting env variables for app", "my-app"},
@@ -62,14 +65,110 @@ var _ = Describe("Testing with ginkgo", func() {
 			{"No env variables exist"},
 		})
 	})
+
+	It("shows the user-provided variables of the app when the env endpoint is missing", func() {
+		reqFactory := getEnvDependencies()
+		reqFactory.Application.EnvironmentVars = map[string]string{"my-key": "my-value"}
+
+		ui := callEnv([]string{"my-app"}, reqFactory, &testapi.FakeApplicationRepository{ReadEnvNotFound: true})
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"OK"},
+			{"User-Provided:"},
+			{"my-key", "my-value"},
+		})
+	})
+
+	Context("when the app is bound to services", func() {
+		var appRepo *testapi.FakeApplicationRepository
+
+		BeforeEach(func() {
+			appRepo = &testapi.FakeApplicationRepository{}
+			appRepo.ReadEnvResult = models.Environment{
+				System: map[string]interface{}{
+					"VCAP_SERVICES": map[string]interface{}{
+						"p-mysql": []interface{}{
+							map[string]interface{}{"name": "db", "credentials": map[string]interface{}{"password": "s3cret"}},
+						},
+					},
+				},
+				Application: map[string]interface{}{
+					"VCAP_APPLICATION": map[string]interface{}{"name": "my-app"},
+				},
+				User:    map[string]interface{}{"my-key": "it's mine", "RUNNING": "overridden"},
+				Running: map[string]interface{}{"RUNNING": "true"},
+				Staging: map[string]interface{}{"STAGING": "true"},
+			}
+		})
+
+		It("shows every group in its own section and hides the credentials", func() {
+			ui := callEnv([]string{"my-app"}, getEnvDependencies(), appRepo)
+
+			testassert.SliceContains(ui.Outputs, testassert.Lines{
+				{"System-Provided:"},
+				{"VCAP_SERVICES"},
+				{"\"name\": \"db\""},
+				{"VCAP_APPLICATION"},
+				{"User-Provided:"},
+				{"RUNNING", "overridden"},
+				{"my-key", "it's mine"},
+				{"Running Environment Variable Groups:"},
+				{"RUNNING", "true"},
+				{"Staging Environment Variable Groups:"},
+				{"STAGING", "true"},
+			})
+			testassert.SliceContains(ui.Outputs, testassert.Lines{{"credentials", "[PRIVATE DATA HIDDEN]"}})
+			testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{{"s3cret"}})
+		})
+
+		It("shows the credentials when asked to", func() {
+			ui := callEnv([]string{"--show-secrets", "my-app"}, getEnvDependencies(), appRepo)
+
+			testassert.SliceContains(ui.Outputs, testassert.Lines{{"password", "s3cret"}})
+		})
+
+		It("exports the variables of the running app for a shell", func() {
+			ui := callEnv([]string{"--export", "shell", "--show-secrets", "my-app"}, getEnvDependencies(), appRepo)
+
+			Expect(ui.Outputs).To(Equal([]string{
+				"export RUNNING='overridden'",
+				`export VCAP_APPLICATION='{"name":"my-app"}'`,
+				`export VCAP_SERVICES='{"p-mysql":[{"credentials":{"password":"s3cret"},"name":"db"}]}'`,
+				`export my-key='it'\''s mine'`,
+			}))
+		})
+
+		It("exports the variables of the running app in dotenv format", func() {
+			ui := callEnv([]string{"--export", "dotenv", "--show-secrets", "my-app"}, getEnvDependencies(), appRepo)
+
+			testassert.SliceContains(ui.Outputs, testassert.Lines{
+				{`RUNNING="overridden"`},
+				{`VCAP_APPLICATION="{\"name\":\"my-app\"}"`},
+				{`VCAP_SERVICES=`, `s3cret`},
+				{`my-key="it's mine"`},
+			})
+		})
+
+		It("does not export the variables with the credentials hidden", func() {
+			ui := callEnv([]string{"--export", "dotenv", "my-app"}, getEnvDependencies(), appRepo)
+
+			Expect(testcmd.CommandDidPassRequirements).To(BeFalse())
+			Expect(appRepo.ReadEnvAppGuid).To(Equal(""))
+			testassert.SliceContains(ui.Outputs, testassert.Lines{
+				{"FAILED"},
+				{"--export needs --show-secrets"},
+			})
+			testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{{"VCAP_SERVICES"}})
+		})
+	})
 })
 
-func callEnv(args []string, reqFactory *testreq.FakeReqFactory) (ui *testterm.FakeUI) {
+func callEnv(args []string, reqFactory *testreq.FakeReqFactory, appRepo *testapi.FakeApplicationRepository) (ui *testterm.FakeUI) {
 	ui = &testterm.FakeUI{}
 	ctxt := testcmd.NewContext("env", args)
 
 	configRepo := testconfig.NewRepositoryWithDefaults()
-	cmd := NewEnv(ui, configRepo)
+	cmd := NewEnv(ui, configRepo, appRepo)
 	testcmd.RunCommand(cmd, ctxt, reqFactory)
 
 	return
@@ -78,6 +177,7 @@ func callEnv(args []string, reqFactory *testreq.FakeReqFactory) (ui *testterm.Fa
 func getEnvDependencies() (reqFactory *testreq.FakeReqFactory) {
 	app := models.Application{}
 	app.Name = "my-app"
+	app.Guid = "my-app-guid"
 	reqFactory = &testreq.FakeReqFactory{LoginSuccess: true, Application: app}
 	return
 }
@@ -4,17 +4,53 @@ import (
 	"cf/api"
 	"cf/configuration"
 	"cf/models"
+	"cf/net"
 	"cf/requirements"
 	"cf/terminal"
 	"errors"
+	"fmt"
 	"github.com/codegangsta/cli"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
 )
 
+const eventsFollowInterval = 2 * time.Second
+
+// the largest page the cloud controller returns
+const eventsMaxPageSize = 100
+
+type eventType struct {
+	name        string
+	description string // events of the type are also filtered by their description
+}
+
+var eventTypes = map[string]eventType{
+	"crash":  {name: "app.crash"},
+	"create": {name: "audit.app.create"},
+	"update": {name: "audit.app.update"},
+	"delete": {name: "audit.app.delete-request"},
+	"start":  {name: "audit.app.update", description: "state: STARTED"},
+	"stop":   {name: "audit.app.update", description: "state: STOPPED"},
+}
+
 type Events struct {
 	ui         terminal.UI
 	config     configuration.Reader
 	appReq     requirements.ApplicationRequirement
 	eventsRepo api.AppEventsRepository
+
+	Clock       Clock
+	FollowPolls int
+}
+
+type eventsQuery struct {
+	filter      api.EventFilter
+	description string
+	instance    int
+	anyInstance bool
 }
 
 func NewEvents(ui terminal.UI, config configuration.Reader, eventsRepo api.AppEventsRepository) (cmd *Events) {
@@ -22,6 +58,7 @@ func NewEvents(ui terminal.UI, config configuration.Reader, eventsRepo api.AppEv
 	cmd.ui = ui
 	cmd.config = config
 	cmd.eventsRepo = eventsRepo
+	cmd.Clock = systemClock{}
 	return
 }
 
@@ -45,6 +82,12 @@ func (cmd *Events) GetRequirements(reqFactory requirements.Factory, c *cli.Conte
 func (cmd *Events) Run(c *cli.Context) {
 	app := cmd.appReq.GetApplication()
 
+	query, err := cmd.newEventsQuery(c)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+
 	cmd.ui.Say("Getting events for app %s in org %s / space %s as %s...\n",
 		terminal.EntityNameColor(app.Name),
 		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
@@ -52,25 +95,206 @@ func (cmd *Events) Run(c *cli.Context) {
 		terminal.EntityNameColor(cmd.config.Username()),
 	)
 
-	table := cmd.ui.Table([]string{"time", "event", "description"})
-	noEvents := true
-
-	apiResponse := cmd.eventsRepo.ListEvents(app.Guid, func(event models.EventFields) bool {
-		table.Print([][]string{{
-			event.Timestamp.Local().Format(TIMESTAMP_FORMAT),
-			event.Name,
-			event.Description,
-		}})
-		noEvents = false
-		return true
-	})
-
+	startedAt := cmd.Clock.Now()
+	events, apiResponse := cmd.listEvents(app.Guid, query, c.Int("limit"))
 	if apiResponse.IsNotSuccessful() {
 		cmd.ui.Failed("Failed fetching events.\n%s", apiResponse.Message)
 		return
 	}
-	if noEvents {
+
+	table := cmd.ui.Table([]string{"time", "event", "description"})
+	if len(events) == 0 {
 		cmd.ui.Say("No events for app %s", terminal.EntityNameColor(app.Name))
+	} else {
+		rows := [][]string{}
+		for _, event := range events {
+			rows = append(rows, eventRow(event))
+		}
+		table.Print(rows)
+	}
+
+	if c.Bool("follow") {
+		cmd.followEvents(app, query, table, events, startedAt)
+	}
+}
+
+func (cmd *Events) newEventsQuery(c *cli.Context) (query eventsQuery, err error) {
+	if c.Bool("follow") && c.String("until") != "" {
+		err = errors.New("--follow cannot be used with --until")
+		return
+	}
+	if c.Int("limit") < 0 {
+		err = fmt.Errorf("Invalid limit: %d\nExpected a number of events to show", c.Int("limit"))
+		return
+	}
+
+	now := cmd.Clock.Now()
+	query.filter.Since, err = parseEventTime(c.String("since"), now)
+	if err != nil {
+		return
+	}
+	query.filter.Until, err = parseEventTime(c.String("until"), now)
+	if err != nil {
+		return
+	}
+
+	typeName := c.String("type")
+	if eventType, found := eventTypes[typeName]; found {
+		query.filter.Type = eventType.name
+		query.description = eventType.description
+	} else if strings.Contains(typeName, ".") || typeName == "" {
+		query.filter.Type = typeName
+	} else {
+		err = fmt.Errorf("Invalid event type: %s\nExpected one of %s, or an event type like app.crash", typeName, strings.Join(eventTypeNames(), ", "))
+		return
+	}
+
+	query.anyInstance = !c.IsSet("instance")
+	query.instance = c.Int("instance")
+	return
+}
+
+// parseEventTime reads a time like 2014-01-21T15:04:05Z, or a duration like
+// 2h that is taken as that long ago.
+func parseEventTime(value string, now time.Time) (eventTime time.Time, err error) {
+	if value == "" {
+		return
+	}
+
+	eventTime, err = time.Parse(time.RFC3339, value)
+	if err == nil {
 		return
 	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		err = fmt.Errorf("Invalid time: %s\nExpected a time like 2014-01-21T15:04:05Z or a duration like 30m", value)
+		return
+	}
+	eventTime = now.Add(-duration)
+	return
+}
+
+func eventTypeNames() (names []string) {
+	for name := range eventTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+func (query eventsQuery) matches(event models.EventFields) bool {
+	if !query.anyInstance && (event.InstanceIndex == nil || *event.InstanceIndex != query.instance) {
+		return false
+	}
+	return strings.Contains(event.Description, query.description)
+}
+
+// listEvents lists the matching events oldest first. With a limit, the
+// newest events are fetched first so that the rest need not be listed.
+func (cmd *Events) listEvents(appGuid string, query eventsQuery, limit int) (events []models.EventFields, apiResponse net.ApiResponse) {
+	filter := query.filter
+	if limit > 0 {
+		filter.Descending = true
+		filter.PageSize = limit
+		if filter.PageSize > eventsMaxPageSize {
+			filter.PageSize = eventsMaxPageSize
+		}
+	}
+
+	apiResponse = cmd.eventsRepo.ListEventsWithFilter(appGuid, filter, func(event models.EventFields) bool {
+		if !query.matches(event) {
+			return true
+		}
+		events = append(events, event)
+		return limit == 0 || len(events) < limit
+	})
+
+	if limit > 0 {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+	return
+}
+
+// followEvents polls for the events since the newest one shown, until it is
+// interrupted. Events at the same time as the newest one are listed again, so
+// the ones already shown are skipped.
+func (cmd *Events) followEvents(app models.Application, query eventsQuery, table terminal.Table, shown []models.EventFields, startedAt time.Time) {
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	since := query.filter.Since
+	if len(shown) == 0 && since.IsZero() {
+		since = startedAt
+	}
+	seen := map[string]bool{}
+	for _, event := range shown {
+		since, seen = markEventSeen(since, seen, event)
+	}
+
+	for poll := 0; cmd.FollowPolls == 0 || poll < cmd.FollowPolls; poll++ {
+		cmd.Clock.Sleep(eventsFollowInterval)
+
+		select {
+		case <-interrupts:
+			cmd.ui.Say("")
+			cmd.ui.Say("Stopped following events for app %s", terminal.EntityNameColor(app.Name))
+			return
+		default:
+		}
+
+		filter := query.filter
+		filter.Since = since
+
+		newEvents := []models.EventFields{}
+		apiResponse := cmd.eventsRepo.ListEventsWithFilter(app.Guid, filter, func(event models.EventFields) bool {
+			if !seen[eventKey(event)] {
+				newEvents = append(newEvents, event)
+			}
+			return true
+		})
+		if apiResponse.IsNotSuccessful() {
+			cmd.ui.Failed("Failed fetching events.\n%s", apiResponse.Message)
+			return
+		}
+
+		for _, event := range newEvents {
+			since, seen = markEventSeen(since, seen, event)
+			if query.matches(event) {
+				table.Print([][]string{eventRow(event)})
+			}
+		}
+	}
+}
+
+// markEventSeen remembers the events at the newest time seen so far, which
+// are the only ones that can be listed again.
+func markEventSeen(since time.Time, seen map[string]bool, event models.EventFields) (time.Time, map[string]bool) {
+	if event.Timestamp.Before(since) {
+		return since, seen
+	}
+	if event.Timestamp.After(since) {
+		since = event.Timestamp
+		seen = map[string]bool{}
+	}
+	seen[eventKey(event)] = true
+	return since, seen
+}
+
+func eventKey(event models.EventFields) string {
+	if event.Guid != "" {
+		return event.Guid
+	}
+	return fmt.Sprintf("%s %s %s", event.Timestamp.UTC().Format(time.RFC3339Nano), event.Name, event.Description)
+}
+
+func eventRow(event models.EventFields) []string {
+	return []string{
+		event.Timestamp.Local().Format(TIMESTAMP_FORMAT),
+		event.Name,
+		event.Description,
+	}
 }
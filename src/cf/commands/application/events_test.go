@@ -1,16 +1,19 @@
 package application_test
 
 import (
+	"cf/api"
 	. "cf/commands/application"
 	"cf/models"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"strings"
 	testapi "testhelpers/api"
 	testassert "testhelpers/assert"
 	testcmd "testhelpers/commands"
 	testconfig "testhelpers/configuration"
 	testreq "testhelpers/requirements"
 	testterm "testhelpers/terminal"
+	testtime "testhelpers/time"
 	"time"
 )
 
@@ -83,6 +86,179 @@ var _ = Describe("Testing with ginkgo", func() {
 	})
 })
 
+var _ = Describe("events command filters", func() {
+	var (
+		reqFactory *testreq.FakeReqFactory
+		eventsRepo *testapi.FakeAppEventsRepo
+		clock      *testtime.FakeClock
+		now        time.Time
+	)
+
+	newEvent := func(guid, name, description string, minutesAgo int, instance *int) (event models.EventFields) {
+		event.Guid = guid
+		event.Name = name
+		event.Description = description
+		event.Timestamp = now.Add(-time.Duration(minutesAgo) * time.Minute)
+		event.InstanceIndex = instance
+		return
+	}
+
+	BeforeEach(func() {
+		reqFactory, eventsRepo = getEventsDependencies()
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		reqFactory.Application = app
+
+		now = time.Date(2014, 1, 21, 12, 0, 0, 0, time.UTC)
+		clock = &testtime.FakeClock{CurrentTime: now}
+	})
+
+	callFilteredEvents := func(args []string, polls int) (ui *testterm.FakeUI) {
+		ui = new(testterm.FakeUI)
+		cmd := NewEvents(ui, testconfig.NewRepositoryWithDefaults(), eventsRepo)
+		cmd.Clock = clock
+		cmd.FollowPolls = polls
+		testcmd.RunCommand(cmd, testcmd.NewContext("events", args), reqFactory)
+		return
+	}
+
+	It("sends the time range and the type to the events repo", func() {
+		callFilteredEvents([]string{"--since", "2h", "--until", "2014-01-21T11:30:00Z", "--type", "crash", "my-app"}, 0)
+
+		Expect(eventsRepo.AppGuid).To(Equal("my-app-guid"))
+		Expect(eventsRepo.Filters).To(Equal([]api.EventFilter{{
+			Since: now.Add(-2 * time.Hour),
+			Until: time.Date(2014, 1, 21, 11, 30, 0, 0, time.UTC),
+			Type:  "app.crash",
+		}}))
+	})
+
+	It("passes event types it does not know through and fails on unknown short names", func() {
+		callFilteredEvents([]string{"--type", "audit.app.restage", "my-app"}, 0)
+		Expect(eventsRepo.Filters[0].Type).To(Equal("audit.app.restage"))
+
+		ui := callFilteredEvents([]string{"--type", "restart", "my-app"}, 0)
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid event type", "restart"},
+		})
+	})
+
+	It("fails on times it cannot read", func() {
+		ui := callFilteredEvents([]string{"--since", "yesterday", "my-app"}, 0)
+
+		Expect(eventsRepo.Filters).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid time", "yesterday"},
+		})
+	})
+
+	It("filters starts and stops by the state in their description", func() {
+		eventsRepo.Events = []models.EventFields{
+			newEvent("event-1", "audit.app.update", "state: STOPPED", 3, nil),
+			newEvent("event-2", "audit.app.update", "instances: 2", 2, nil),
+			newEvent("event-3", "audit.app.update", "state: STARTED", 1, nil),
+		}
+
+		ui := callFilteredEvents([]string{"--type", "start", "my-app"}, 0)
+
+		Expect(eventsRepo.Filters[0].Type).To(Equal("audit.app.update"))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"audit.app.update", "state: STARTED"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"state: STOPPED"},
+			{"instances: 2"},
+		})
+	})
+
+	It("only shows the events about an instance", func() {
+		zero, one := 0, 1
+		eventsRepo.Events = []models.EventFields{
+			newEvent("event-1", "app.crash", "index: 0", 3, &zero),
+			newEvent("event-2", "audit.app.update", "instances: 2", 2, nil),
+			newEvent("event-3", "app.crash", "index: 1", 1, &one),
+		}
+
+		ui := callFilteredEvents([]string{"--instance", "0", "my-app"}, 0)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"app.crash", "index: 0"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"instances: 2"},
+			{"index: 1"},
+		})
+	})
+
+	It("lists the newest events first with a limit and shows them oldest first", func() {
+		eventsRepo.Events = []models.EventFields{
+			newEvent("event-3", "app.crash", "third", 1, nil),
+			newEvent("event-2", "app.crash", "second", 2, nil),
+			newEvent("event-1", "app.crash", "first", 3, nil),
+		}
+
+		ui := callFilteredEvents([]string{"--limit", "2", "my-app"}, 0)
+
+		Expect(eventsRepo.Filters[0].Descending).To(BeTrue())
+		Expect(eventsRepo.Filters[0].PageSize).To(Equal(2))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"time", "event", "description"},
+			{"second"},
+			{"third"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"first"},
+		})
+	})
+
+	It("keeps polling for new events since the newest one shown when following", func() {
+		first := newEvent("event-1", "app.crash", "first", 2, nil)
+		second := newEvent("event-2", "app.crash", "second", 1, nil)
+		sameTime := newEvent("", "app.crash", "same time", 1, nil)
+		eventsRepo.EventsByListing = [][]models.EventFields{
+			{first, second},
+			{second},
+			{second, sameTime},
+		}
+
+		ui := callFilteredEvents([]string{"--follow", "--type", "crash", "my-app"}, 2)
+
+		Expect(clock.Sleeps).To(HaveLen(2))
+		Expect(eventsRepo.Filters).To(HaveLen(3))
+		Expect(eventsRepo.Filters[1].Since).To(Equal(second.Timestamp))
+		Expect(eventsRepo.Filters[2].Type).To(Equal("app.crash"))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"first"},
+			{"second"},
+			{"same time"},
+		})
+		Expect(strings.Count(strings.Join(ui.Outputs, "\n"), "second")).To(Equal(1))
+	})
+
+	It("follows the events since it started when there were none", func() {
+		ui := callFilteredEvents([]string{"--follow", "my-app"}, 1)
+
+		Expect(eventsRepo.Filters[1].Since).To(Equal(now))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"No events", "my-app"},
+		})
+	})
+
+	It("fails when following with an end time", func() {
+		ui := callFilteredEvents([]string{"--follow", "--until", "1h", "my-app"}, 1)
+
+		Expect(eventsRepo.Filters).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"--follow", "--until"},
+		})
+	})
+})
+
 func getEventsDependencies() (reqFactory *testreq.FakeReqFactory, eventsRepo *testapi.FakeAppEventsRepo) {
 	reqFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
 	eventsRepo = &testapi.FakeAppEventsRepo{}
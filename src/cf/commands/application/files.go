@@ -7,6 +7,15 @@ import (
 	"cf/terminal"
 	"errors"
 	"github.com/codegangsta/cli"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+const (
+	tailInitialBytes = 4096
+	tailInitialLines = 10
 )
 
 type Files struct {
@@ -14,6 +23,9 @@ type Files struct {
 	config       configuration.Reader
 	appFilesRepo api.AppFilesRepository
 	appReq       requirements.ApplicationRequirement
+
+	Clock     Clock
+	TailPolls int
 }
 
 func NewFiles(ui terminal.UI, config configuration.Reader, appFilesRepo api.AppFilesRepository) (cmd *Files) {
@@ -21,11 +33,12 @@ func NewFiles(ui terminal.UI, config configuration.Reader, appFilesRepo api.AppF
 	cmd.ui = ui
 	cmd.config = config
 	cmd.appFilesRepo = appFilesRepo
+	cmd.Clock = systemClock{}
 	return
 }
 
 func (cmd *Files) GetRequirements(reqFactory requirements.Factory, c *cli.Context) (reqs []requirements.Requirement, err error) {
-	if len(c.Args()) < 1 {
+	if len(c.Args()) < 1 || (c.Bool("tail") && len(c.Args()) < 2) {
 		err = errors.New("Incorrect Usage")
 		cmd.ui.FailWithUsage(c, "files")
 		return
@@ -44,6 +57,11 @@ func (cmd *Files) GetRequirements(reqFactory requirements.Factory, c *cli.Contex
 func (cmd *Files) Run(c *cli.Context) {
 	app := cmd.appReq.GetApplication()
 
+	if c.Bool("tail") && c.Int("interval") < 1 {
+		cmd.ui.Failed("Invalid interval: %d\nExpected a number of seconds between reads of the file", c.Int("interval"))
+		return
+	}
+
 	cmd.ui.Say("Getting files for app %s in org %s / space %s as %s...",
 		terminal.EntityNameColor(app.Name),
 		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
@@ -56,7 +74,12 @@ func (cmd *Files) Run(c *cli.Context) {
 		path = c.Args()[1]
 	}
 
-	list, apiResponse := cmd.appFilesRepo.ListFiles(app.Guid, path)
+	if c.Bool("tail") {
+		cmd.tailFile(app.Guid, c.Int("i"), path, time.Duration(c.Int("interval"))*time.Second)
+		return
+	}
+
+	list, apiResponse := cmd.appFilesRepo.ListInstanceFiles(app.Guid, c.Int("i"), path)
 	if apiResponse.IsNotSuccessful() {
 		cmd.ui.Failed(apiResponse.Message)
 		return
@@ -66,3 +89,89 @@ func (cmd *Files) Run(c *cli.Context) {
 	cmd.ui.Say("")
 	cmd.ui.Say("%s", list)
 }
+
+// tailFile polls the file for the bytes appended since the previous read and
+// prints them line by line, like tail -f, until it is interrupted.
+func (cmd *Files) tailFile(appGuid string, instance int, path string, interval time.Duration) {
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	offset := int64(-tailInitialBytes)
+	partialLine := ""
+
+	for poll := 0; cmd.TailPolls == 0 || poll < cmd.TailPolls; poll++ {
+		if poll > 0 {
+			cmd.Clock.Sleep(interval)
+
+			select {
+			case <-interrupts:
+				cmd.stopTailing(path, partialLine)
+				return
+			default:
+			}
+		}
+
+		content, fileSize, apiResponse := cmd.appFilesRepo.ReadFileFrom(appGuid, instance, path, offset)
+		if apiResponse.IsNotSuccessful() {
+			cmd.ui.Failed(apiResponse.Message)
+			return
+		}
+
+		switch {
+		case poll == 0:
+			cmd.ui.Ok()
+			cmd.ui.Say("")
+			partialLine = cmd.printLines(initialTail(content, fileSize > tailInitialBytes))
+		case fileSize < offset:
+			// a rotated file that is already longer than the old one cannot be told apart
+			cmd.ui.Warn("%s was truncated or rotated, following it from the start", path)
+			partialLine = cmd.flushPartialLine(partialLine)
+			offset = 0
+			continue
+		default:
+			partialLine = cmd.printLines(partialLine + string(content))
+		}
+		offset = fileSize
+	}
+}
+
+func (cmd *Files) stopTailing(path, partialLine string) {
+	cmd.flushPartialLine(partialLine)
+	cmd.ui.Say("")
+	cmd.ui.Say("Stopped following %s", terminal.EntityNameColor(path))
+}
+
+// printLines prints every complete line of text and returns the rest, which
+// is printed once the line is complete.
+func (cmd *Files) printLines(text string) (partialLine string) {
+	lines := strings.Split(text, "\n")
+	for _, line := range lines[:len(lines)-1] {
+		cmd.ui.Say("%s", strings.TrimSuffix(line, "\r"))
+	}
+	return lines[len(lines)-1]
+}
+
+func (cmd *Files) flushPartialLine(partialLine string) string {
+	if partialLine != "" {
+		cmd.ui.Say("%s", partialLine)
+	}
+	return ""
+}
+
+// initialTail keeps the last lines of the end of a file, dropping the first
+// line when it has been cut in the middle.
+func initialTail(content []byte, cut bool) string {
+	text := string(content)
+	if cut {
+		text = text[strings.Index(text, "\n")+1:]
+	}
+
+	lines := strings.Split(text, "\n")
+	partialLine := lines[len(lines)-1]
+	lines = lastLines(lines[:len(lines)-1], tailInitialLines)
+	if len(lines) == 0 {
+		return partialLine
+	}
+	return strings.Join(lines, "\n") + "\n" + partialLine
+}
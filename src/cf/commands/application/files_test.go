@@ -3,14 +3,18 @@ package application_test
 import (
 	. "cf/commands/application"
 	"cf/models"
+	"fmt"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"strings"
 	testapi "testhelpers/api"
 	testassert "testhelpers/assert"
 	testcmd "testhelpers/commands"
 	testconfig "testhelpers/configuration"
 	testreq "testhelpers/requirements"
 	testterm "testhelpers/terminal"
+	testtime "testhelpers/time"
+	"time"
 )
 
 var _ = Describe("Testing with ginkgo", func() {
@@ -61,6 +65,19 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(appFilesRepo.AppGuid).To(Equal("my-app-guid"))
 		Expect(appFilesRepo.Path).To(Equal("/foo"))
 	})
+	It("TestListingFilesIgnoresTheInterval", func() {
+		reqFactory := &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: models.Application{}}
+		appFilesRepo := &testapi.FakeAppFilesRepo{FileList: "file 1"}
+
+		ui := callFiles([]string{"--interval", "0", "my-app", "/foo"}, reqFactory, appFilesRepo)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"file 1"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+		})
+	})
 	It("TestListingFilesWithTemplateTokens", func() {
 
 		app := models.Application{}
@@ -78,6 +95,95 @@ var _ = Describe("Testing with ginkgo", func() {
 	})
 })
 
+var _ = Describe("files --tail", func() {
+	var (
+		reqFactory   *testreq.FakeReqFactory
+		appFilesRepo *testapi.FakeAppFilesRepo
+		clock        *testtime.FakeClock
+	)
+
+	BeforeEach(func() {
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		reqFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: app}
+		appFilesRepo = &testapi.FakeAppFilesRepo{}
+		clock = &testtime.FakeClock{}
+	})
+
+	callTail := func(args []string, polls int) (ui *testterm.FakeUI) {
+		ui = new(testterm.FakeUI)
+		cmd := NewFiles(ui, testconfig.NewRepositoryWithDefaults(), appFilesRepo)
+		cmd.Clock = clock
+		cmd.TailPolls = polls
+		testcmd.RunCommand(cmd, testcmd.NewContext("files", args), reqFactory)
+		return
+	}
+
+	It("fails with usage without a path", func() {
+		ui := callTail([]string{"--tail", "my-app"}, 1)
+		Expect(ui.FailedWithUsage).To(BeTrue())
+	})
+
+	It("fails with an interval below one second", func() {
+		ui := callTail([]string{"--tail", "--interval", "0", "my-app", "logs/app.log"}, 1)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid interval", "0"},
+		})
+		Expect(appFilesRepo.ReadOffsets).To(BeEmpty())
+	})
+
+	It("prints the end of the file and then the lines appended to it", func() {
+		appFilesRepo.FileVersions = []string{
+			"one\ntwo\n",
+			"one\ntwo\nthree\nfo",
+			"one\ntwo\nthree\nfour\n",
+		}
+
+		ui := callTail([]string{"--tail", "-i", "1", "--interval", "2", "my-app", "logs/app.log"}, 3)
+
+		Expect(appFilesRepo.Instance).To(Equal(1))
+		Expect(appFilesRepo.Path).To(Equal("logs/app.log"))
+		Expect(appFilesRepo.ReadOffsets).To(Equal([]int64{-4096, 8, 16}))
+		Expect(clock.Sleeps).To(Equal([]time.Duration{2 * time.Second, 2 * time.Second}))
+		Expect(ui.Outputs[len(ui.Outputs)-4:]).To(Equal([]string{"one", "two", "three", "four"}))
+	})
+
+	It("only prints the last lines of a long file, without the line it starts in", func() {
+		lines := []string{}
+		for i := 0; i < 1000; i++ {
+			lines = append(lines, fmt.Sprintf("line %d", i))
+		}
+		appFilesRepo.FileVersions = []string{strings.Join(lines, "\n") + "\n"}
+
+		ui := callTail([]string{"--tail", "my-app", "logs/app.log"}, 1)
+
+		Expect(ui.Outputs).To(ContainElement("line 999"))
+		Expect(ui.Outputs).To(ContainElement("line 990"))
+		Expect(ui.Outputs).NotTo(ContainElement("line 989"))
+	})
+
+	It("follows the file from the start again when it is truncated", func() {
+		appFilesRepo.FileVersions = []string{
+			"old line\n",
+			"new\n",
+			"new\nnewer\n",
+		}
+
+		ui := callTail([]string{"--tail", "my-app", "logs/app.log"}, 4)
+
+		Expect(appFilesRepo.ReadOffsets).To(Equal([]int64{-4096, 9, 0, 10}))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"old line"},
+			{"logs/app.log was truncated or rotated"},
+			{"new"},
+			{"newer"},
+		})
+	})
+})
+
 func callFiles(args []string, reqFactory *testreq.FakeReqFactory, appFilesRepo *testapi.FakeAppFilesRepo) (ui *testterm.FakeUI) {
 	ui = &testterm.FakeUI{}
 	ctxt := testcmd.NewContext("files", args)
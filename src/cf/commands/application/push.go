@@ -18,6 +18,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Push struct {
@@ -33,13 +34,23 @@ type Push struct {
 	serviceRepo    api.ServiceRepository
 	stackRepo      api.StackRepository
 	appBitsRepo    api.ApplicationBitsRepository
+	appInstances   api.AppInstancesRepository
+	userProvided   api.UserProvidedServiceInstanceRepository
+	appSummaryRepo api.AppSummaryRepository
 	globalServices []models.ServiceInstance
+	dryRun         bool
+	avoidTaken     bool
+
+	PingerThrottle time.Duration
+	RandomWord     func() string
 }
 
 func NewPush(ui terminal.UI, config configuration.Reader, manifestRepo manifest.ManifestRepository,
 	starter ApplicationStarter, stopper ApplicationStopper, binder service.ServiceBinder,
 	appRepo api.ApplicationRepository, domainRepo api.DomainRepository, routeRepo api.RouteRepository,
-	stackRepo api.StackRepository, serviceRepo api.ServiceRepository, appBitsRepo api.ApplicationBitsRepository) (cmd *Push) {
+	stackRepo api.StackRepository, serviceRepo api.ServiceRepository, appBitsRepo api.ApplicationBitsRepository,
+	appInstances api.AppInstancesRepository, userProvided api.UserProvidedServiceInstanceRepository,
+	appSummaryRepo api.AppSummaryRepository) (cmd *Push) {
 	cmd = &Push{}
 	cmd.ui = ui
 	cmd.config = config
@@ -53,6 +64,11 @@ func NewPush(ui terminal.UI, config configuration.Reader, manifestRepo manifest.
 	cmd.serviceRepo = serviceRepo
 	cmd.stackRepo = stackRepo
 	cmd.appBitsRepo = appBitsRepo
+	cmd.appInstances = appInstances
+	cmd.userProvided = userProvided
+	cmd.appSummaryRepo = appSummaryRepo
+	cmd.PingerThrottle = DefaultPingerThrottle
+	cmd.RandomWord = randomWord
 	return
 }
 
@@ -65,50 +81,181 @@ func (cmd *Push) GetRequirements(reqFactory requirements.Factory, c *cli.Context
 }
 
 func (cmd *Push) Run(c *cli.Context) {
+	if c.Bool("print-manifest") {
+		cmd.printManifest(cmd.instantiateManifest(c))
+		return
+	}
+
+	cmd.dryRun = c.Bool("dry-run")
+	cmd.avoidTaken = c.Bool("avoid-taken-routes")
+	if !cmd.configureStarter(c) {
+		return
+	}
+	blueGreen := cmd.blueGreenStrategy(c)
+	parallelism := cmd.parallelism(c)
 	appSet := cmd.findAndValidateAppsToPush(c)
 
+	appSet, err := orderAppsByDependencies(appSet)
+	if err != nil {
+		cmd.ui.Failed("Error: %s", err)
+		return
+	}
+
+	if cmd.dryRun {
+		cmd.ui.Say("Dry run, nothing will be changed\n")
+	}
+
+	if parallelism > 0 {
+		cmd.pushAppsInParallel(appSet, parallelism, blueGreen, c)
+		return
+	}
+
 	for _, appParams := range appSet {
-		cmd.fetchStackGuid(&appParams)
+		_, err = cmd.pushApp(appParams, blueGreen, c)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+			return
+		}
+	}
+}
 
-		app := cmd.createOrUpdateApp(appParams)
+func (cmd *Push) configureStarter(c *cli.Context) (ok bool) {
+	if c.String("wait-for") != "" {
+		threshold, err := ParseInstancesThreshold(c.String("wait-for"))
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+			return
+		}
+		cmd.starter.SetInstancesThreshold(threshold)
+	}
+
+	healthCheck, err := NewHealthCheck(c)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+	cmd.starter.SetHealthCheck(healthCheck)
 
-		cmd.bindAppToRoute(app, appParams, c)
+	ok = true
+	return
+}
 
-		cmd.ui.Say("Uploading %s...", terminal.EntityNameColor(app.Name))
+// pushApp pushes a single app. It returns a failure rather than failing the
+// command, so that apps pushed in parallel can fail on their own.
+func (cmd *Push) pushApp(appParams models.AppParams, blueGreen bool, c *cli.Context) (upToDate bool, err error) {
+	err = cmd.fetchStackGuid(&appParams)
+	if err != nil {
+		return
+	}
 
-		apiResponse := cmd.appBitsRepo.UploadApp(app.Guid, *appParams.Path, cmd.describeUploadOperation)
-		if apiResponse.IsNotSuccessful() {
-			cmd.ui.Failed(fmt.Sprintf("Error uploading application.\n%s", apiResponse.Message))
+	if blueGreen {
+		var pushed bool
+		pushed, err = cmd.pushBlueGreen(appParams, c)
+		if pushed || err != nil {
 			return
 		}
-		cmd.ui.Ok()
+	}
+
+	app, restartNeeded, err := cmd.createOrUpdateApp(appParams)
+	if err != nil {
+		return
+	}
 
-		if appParams.Services != nil {
-			cmd.bindAppToServices(*appParams.Services, app)
+	err = cmd.bindAppToRoute(app, appParams, c)
+	if err != nil {
+		return
+	}
+
+	if cmd.dryRun {
+		err = cmd.planUploadAndRestart(app, appParams, restartNeeded, c)
+		return
+	}
+
+	cmd.ui.Say("Uploading %s...", terminal.EntityNameColor(app.Name))
+
+	progress := newUploadProgress(cmd.ui)
+	uploaded, apiResponse := cmd.appBitsRepo.UploadApp(app.Guid, *appParams.Path, c.Bool("force-upload"), cmd.describeUploadOperation, progress)
+	progress.Done()
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(fmt.Sprintf("Error uploading application.\n%s", apiResponse.Message))
+		return
+	}
+	if !uploaded {
+		cmd.ui.Say("App bits are unchanged since the last upload, skipping (use --force-upload to upload anyway)")
+	}
+	cmd.ui.Ok()
+
+	if appParams.Services != nil {
+		var boundNewService bool
+		boundNewService, err = cmd.bindAppToServices(appParams, app)
+		if err != nil {
+			return
+		}
+		if boundNewService {
+			restartNeeded = true
 		}
+	}
 
-		cmd.restart(app, appParams, c)
+	if !uploaded && !restartNeeded {
+		cmd.ui.Say("\nApp %s is up to date, skipping restart\n", terminal.EntityNameColor(app.Name))
+		upToDate = true
+		return
 	}
+
+	err = cmd.restart(app, appParams, c)
+	return
 }
 
-func (cmd *Push) bindAppToServices(services []string, app models.Application) {
-	for _, serviceName := range services {
+func (cmd *Push) bindAppToServices(params models.AppParams, app models.Application) (boundNewService bool, err error) {
+	for _, serviceName := range *params.Services {
 		serviceInstance, response := cmd.serviceRepo.FindInstanceByName(serviceName)
 
+		declared, isDeclared := declaredServiceInstance(params, serviceName)
+		if response.IsNotFound() && isDeclared {
+			if cmd.dryRun {
+				cmd.ui.Say("Would create service %s", terminal.EntityNameColor(serviceName))
+				cmd.ui.Say("Would bind service %s to %s", terminal.EntityNameColor(serviceName), terminal.EntityNameColor(app.Name))
+				boundNewService = true
+				continue
+			}
+			serviceInstance, response, err = cmd.createServiceInstance(declared)
+			if err != nil {
+				return
+			}
+		} else if response.IsSuccessful() && isDeclared {
+			cmd.warnAboutServiceMismatch(serviceInstance, declared)
+		}
+
 		if response.IsNotSuccessful() {
-			cmd.ui.Failed("Could not find service %s to bind to %s", serviceName, app.Name)
+			err = errors.New(fmt.Sprintf("Could not find service %s to bind to %s", serviceName, app.Name))
 			return
 		}
 
+		if cmd.dryRun {
+			if serviceInstanceIsBoundToApp(serviceInstance, app) {
+				cmd.ui.Say("Service %s is already bound to %s", terminal.EntityNameColor(serviceName), terminal.EntityNameColor(app.Name))
+				continue
+			}
+
+			cmd.ui.Say("Would bind service %s to %s", terminal.EntityNameColor(serviceName), terminal.EntityNameColor(app.Name))
+			boundNewService = true
+			continue
+		}
+
 		cmd.ui.Say("Binding service %s to %s in org %s / space %s as %s", serviceName, app.Name, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name, cmd.config.Username())
 		bindResponse := cmd.binder.BindApplication(app, serviceInstance)
 		cmd.ui.Ok()
 
 		if bindResponse.IsNotSuccessful() && bindResponse.ErrorCode != service.AppAlreadyBoundErrorCode {
-			cmd.ui.Failed("Could not find to service %s\nError: %s", serviceName, bindResponse.Message)
+			err = errors.New(fmt.Sprintf("Could not find to service %s\nError: %s", serviceName, bindResponse.Message))
 			return
 		}
+
+		if bindResponse.IsSuccessful() {
+			boundNewService = true
+		}
 	}
+	return
 }
 
 func (cmd *Push) describeUploadOperation(path string, zipFileBytes, fileCount uint64) {
@@ -116,7 +263,48 @@ func (cmd *Push) describeUploadOperation(path string, zipFileBytes, fileCount ui
 	cmd.ui.Say("Uploading from: %s\n%s, %d files", path, humanReadableBytes, fileCount)
 }
 
-func (cmd *Push) fetchStackGuid(appParams *models.AppParams) {
+type uploadProgress struct {
+	ui    terminal.UI
+	bar   terminal.ProgressBar
+	phase terminal.PhaseIndicator
+}
+
+func newUploadProgress(ui terminal.UI) *uploadProgress {
+	return &uploadProgress{ui: ui}
+}
+
+func (progress *uploadProgress) Sent(bytesSent, totalBytes int64) {
+	if progress.phase != nil {
+		// the request is being sent again, e.g. after refreshing the auth token
+		progress.Done()
+	}
+
+	if progress.bar == nil {
+		progress.bar = progress.ui.NewProgressBar()
+	}
+	progress.bar.Update(bytesSent, totalBytes)
+}
+
+func (progress *uploadProgress) Processing() {
+	if progress.bar != nil {
+		progress.bar.Finish()
+		progress.bar = nil
+	}
+	progress.phase = progress.ui.StartPhase("Processing upload")
+}
+
+func (progress *uploadProgress) Done() {
+	if progress.bar != nil {
+		progress.bar.Finish()
+		progress.bar = nil
+	}
+	if progress.phase != nil {
+		progress.phase.Stop()
+		progress.phase = nil
+	}
+}
+
+func (cmd *Push) fetchStackGuid(appParams *models.AppParams) (err error) {
 	if appParams.StackName == nil {
 		return
 	}
@@ -126,15 +314,16 @@ func (cmd *Push) fetchStackGuid(appParams *models.AppParams) {
 
 	stack, apiResponse := cmd.stackRepo.FindByName(stackName)
 	if apiResponse.IsNotSuccessful() {
-		cmd.ui.Failed(apiResponse.Message)
+		err = errors.New(apiResponse.Message)
 		return
 	}
 
 	cmd.ui.Ok()
 	appParams.StackGuid = &stack.Guid
+	return
 }
 
-func (cmd *Push) bindAppToRoute(app models.Application, params models.AppParams, c *cli.Context) {
+func (cmd *Push) bindAppToRoute(app models.Application, params models.AppParams, c *cli.Context) (err error) {
 	if c.Bool("no-route") {
 		return
 	}
@@ -145,15 +334,18 @@ func (cmd *Push) bindAppToRoute(app models.Application, params models.AppParams,
 	}
 
 	routeFlagsPresent := c.String("n") != "" || c.String("d") != "" || c.Bool("no-hostname")
-	if len(app.Routes) > 0 && !routeFlagsPresent {
+	if !routeFlagsPresent && appParamsHaveRoutes(params) {
+		err = cmd.bindAppToRoutes(app, params, c)
 		return
 	}
 
-	var defaultHostname string
-	if params.Host != nil {
-		defaultHostname = *params.Host
-	} else {
-		defaultHostname = hostNameForString(app.Name)
+	if c.Bool("prune-routes") {
+		cmd.ui.Warn("Not pruning the routes of %s: --prune-routes only keeps the routes, hosts and domains lists of a manifest, and %s has none",
+			app.Name, app.Name)
+	}
+
+	if len(app.Routes) > 0 && !routeFlagsPresent {
+		return
 	}
 
 	var domainName string
@@ -162,27 +354,55 @@ func (cmd *Push) bindAppToRoute(app models.Application, params models.AppParams,
 	} else {
 		domainName = c.String("d")
 	}
+	domain, err := cmd.domain(c, domainName)
+	if err != nil {
+		return
+	}
+
+	var defaultHostname string
+	if params.Host != nil {
+		defaultHostname = *params.Host
+	} else if params.RandomRoute != nil && *params.RandomRoute && c.String("n") == "" {
+		defaultHostname, err = cmd.availableHostname(hostNameForString(app.Name), domain)
+		if err != nil {
+			return
+		}
+	} else {
+		defaultHostname = hostNameForString(app.Name)
+	}
 
 	hostName := cmd.hostname(c, defaultHostname)
-	domain := cmd.domain(c, domainName)
-	route := cmd.route(hostName, domain)
+	route, err := cmd.route(hostName, domain)
+	if err != nil {
+		return
+	}
+	err = cmd.bindRouteToApp(app, route, route.URL())
+	return
+}
 
+func (cmd *Push) bindRouteToApp(app models.Application, route models.Route, url string) (err error) {
 	for _, boundRoute := range app.Routes {
 		if boundRoute.Guid == route.Guid {
 			return
 		}
 	}
 
-	cmd.ui.Say("Binding %s to %s...", terminal.EntityNameColor(domain.UrlForHost(hostName)), terminal.EntityNameColor(app.Name))
+	if cmd.dryRun {
+		cmd.ui.Say("Would bind %s to %s\n", terminal.EntityNameColor(url), terminal.EntityNameColor(app.Name))
+		return
+	}
+
+	cmd.ui.Say("Binding %s to %s...", terminal.EntityNameColor(url), terminal.EntityNameColor(app.Name))
 
 	apiResponse := cmd.routeRepo.Bind(route.Guid, app.Guid)
 	if apiResponse.IsNotSuccessful() {
-		cmd.ui.Failed(apiResponse.Message)
+		err = errors.New(apiResponse.Message)
 		return
 	}
 
 	cmd.ui.Ok()
 	cmd.ui.Say("")
+	return
 }
 
 var forbiddenHostCharRegex = regexp.MustCompile("[^a-z0-9-]")
@@ -195,10 +415,13 @@ func hostNameForString(name string) string {
 	return string(nameBytes)
 }
 
-func (cmd *Push) restart(app models.Application, params models.AppParams, c *cli.Context) {
+func (cmd *Push) restart(app models.Application, params models.AppParams, c *cli.Context) (err error) {
 	if app.State != "stopped" {
 		cmd.ui.Say("")
-		app, _ = cmd.stopper.ApplicationStop(app)
+		app, err = cmd.stopper.ApplicationStop(app)
+		if err != nil {
+			return
+		}
 	}
 
 	cmd.ui.Say("")
@@ -211,48 +434,76 @@ func (cmd *Push) restart(app models.Application, params models.AppParams, c *cli
 		cmd.starter.SetStartTimeoutSeconds(*params.HealthCheckTimeout)
 	}
 
-	cmd.starter.ApplicationStart(app)
+	_, err = cmd.starter.ApplicationStart(app)
+	return
 }
 
-func (cmd *Push) route(hostName string, domain models.DomainFields) (route models.Route) {
+func (cmd *Push) route(hostName string, domain models.DomainFields) (route models.Route, err error) {
 	route, apiResponse := cmd.routeRepo.FindByHostAndDomain(hostName, domain.Name)
+	if apiResponse.IsSuccessful() && cmd.routeIsInAnotherSpace(route) {
+		url := domain.UrlForHost(hostName)
+		if !cmd.avoidTaken {
+			err = errors.New(fmt.Sprintf("The route %s is already taken by another space.\n"+
+				"TIP: Change the host with -n HOST, use --random-route, or use --avoid-taken-routes to add a suffix to the host", url))
+			return
+		}
+
+		hostName, err = cmd.availableHostname(hostName, domain)
+		if err != nil {
+			return
+		}
+		cmd.ui.Say("The route %s is already taken by another space, using %s instead",
+			terminal.EntityNameColor(url), terminal.EntityNameColor(domain.UrlForHost(hostName)))
+		route, apiResponse = cmd.routeRepo.FindByHostAndDomain(hostName, domain.Name)
+	}
+
 	if apiResponse.IsNotSuccessful() {
+		if cmd.dryRun {
+			cmd.ui.Say("Would create route %s", terminal.EntityNameColor(domain.UrlForHost(hostName)))
+			route.Host = hostName
+			route.Domain = domain
+			return
+		}
+
 		cmd.ui.Say("Creating route %s...", terminal.EntityNameColor(domain.UrlForHost(hostName)))
 
 		route, apiResponse = cmd.routeRepo.Create(hostName, domain.Guid)
 		if apiResponse.IsNotSuccessful() {
-			cmd.ui.Failed(apiResponse.Message)
+			err = errors.New(apiResponse.Message)
 			return
 		}
+		route.Host = hostName
+		route.Domain = domain
 
 		cmd.ui.Ok()
 		cmd.ui.Say("")
 	} else {
 		cmd.ui.Say("Using route %s", terminal.EntityNameColor(route.URL()))
+		route.Host = hostName
+		route.Domain = domain
 	}
 
 	return
 }
 
-func (cmd *Push) domain(c *cli.Context, domainName string) (domain models.DomainFields) {
+func (cmd *Push) domain(c *cli.Context, domainName string) (domain models.DomainFields, err error) {
 	var apiResponse net.ApiResponse
 
 	if domainName != "" {
 		domain, apiResponse = cmd.domainRepo.FindByNameInOrg(domainName, cmd.config.OrganizationFields().Guid)
 		if apiResponse.IsNotSuccessful() {
-			cmd.ui.Failed(apiResponse.Message)
+			err = errors.New(apiResponse.Message)
 		}
 		return
 	}
 
-	domain, err := cmd.findDefaultDomain()
-
+	domain, err = cmd.findDefaultDomain()
 	if err != nil {
-		cmd.ui.Failed(err.Error())
+		return
 	}
 
 	if domain.Guid == "" {
-		cmd.ui.Failed("No default domain exists")
+		err = errors.New("No default domain exists")
 	}
 
 	return
@@ -298,15 +549,20 @@ func (cmd *Push) hostname(c *cli.Context, defaultName string) (hostName string)
 	return
 }
 
-func (cmd *Push) createOrUpdateApp(appParams models.AppParams) (app models.Application) {
+func (cmd *Push) createOrUpdateApp(appParams models.AppParams) (app models.Application, restartNeeded bool, err error) {
 	if appParams.Name == nil {
-		cmd.ui.Failed("Error: No name found for app")
+		err = errors.New("Error: No name found for app")
 		return
 	}
 
 	app, apiResponse := cmd.appRepo.Read(*appParams.Name)
 	if apiResponse.IsError() {
-		cmd.ui.Failed(apiResponse.Message)
+		err = errors.New(apiResponse.Message)
+		return
+	}
+
+	if cmd.dryRun {
+		app, restartNeeded = cmd.planCreateOrUpdateApp(app, apiResponse.IsNotFound(), appParams)
 		return
 	}
 
@@ -314,19 +570,56 @@ func (cmd *Push) createOrUpdateApp(appParams models.AppParams) (app models.Appli
 	if apiResponse.IsNotFound() {
 		app, apiResponse = cmd.createApp(appParams)
 		if apiResponse.IsNotSuccessful() {
-			cmd.ui.Failed(apiResponse.Message)
+			err = errors.New(apiResponse.Message)
 			return
 		}
 		didCreate = true
+		restartNeeded = true
 	}
 
 	if !didCreate {
-		app = cmd.updateApp(app, appParams)
+		restartNeeded = appParamsRequireRestart(app, appParams)
+		app, err = cmd.updateApp(app, appParams)
 	}
 
 	return
 }
 
+// appParamsRequireRestart tells whether pushing params onto the existing app
+// changes anything that only takes effect once the app is restarted.
+// Scaling instances alone is applied by the cloud controller on the fly.
+func appParamsRequireRestart(app models.Application, params models.AppParams) bool {
+	if app.State != "started" {
+		return true
+	}
+
+	if params.Memory != nil && *params.Memory != app.Memory {
+		return true
+	}
+	if params.DiskQuota != nil && *params.DiskQuota != app.DiskQuota {
+		return true
+	}
+	if params.Command != nil && *params.Command != app.Command {
+		return true
+	}
+	if params.BuildpackUrl != nil && *params.BuildpackUrl != app.BuildpackUrl {
+		return true
+	}
+	if params.StackGuid != nil && *params.StackGuid != app.Stack.Guid {
+		return true
+	}
+
+	if params.EnvironmentVars != nil {
+		for key, val := range *params.EnvironmentVars {
+			if currentVal, ok := app.EnvironmentVars[key]; !ok || currentVal != val {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (cmd *Push) createApp(appParams models.AppParams) (app models.Application, apiResponse net.ApiResponse) {
 	spaceGuid := cmd.config.SpaceFields().Guid
 	appParams.SpaceGuid = &spaceGuid
@@ -340,7 +633,6 @@ func (cmd *Push) createApp(appParams models.AppParams) (app models.Application,
 
 	app, apiResponse = cmd.appRepo.Create(appParams)
 	if apiResponse.IsNotSuccessful() {
-		cmd.ui.Failed(apiResponse.Message)
 		return
 	}
 
@@ -350,7 +642,7 @@ func (cmd *Push) createApp(appParams models.AppParams) (app models.Application,
 	return
 }
 
-func (cmd *Push) updateApp(app models.Application, appParams models.AppParams) (updatedApp models.Application) {
+func (cmd *Push) updateApp(app models.Application, appParams models.AppParams) (updatedApp models.Application, err error) {
 	cmd.ui.Say("Updating app %s in org %s / space %s as %s...",
 		terminal.EntityNameColor(app.Name),
 		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
@@ -369,7 +661,7 @@ func (cmd *Push) updateApp(app models.Application, appParams models.AppParams) (
 	var apiResponse net.ApiResponse
 	updatedApp, apiResponse = cmd.appRepo.Update(app.Guid, appParams)
 	if apiResponse.IsNotSuccessful() {
-		cmd.ui.Failed(apiResponse.Message)
+		err = errors.New(apiResponse.Message)
 		return
 	}
 
@@ -407,22 +699,26 @@ func (cmd *Push) instantiateManifest(c *cli.Context) (m *manifest.Manifest) {
 		return
 	}
 
-	var path string
-	if c.String("f") != "" {
-		path = c.String("f")
-	} else {
-		var err error
-		path, err = os.Getwd()
+	paths := c.StringSlice("f")
+	if len(paths) == 0 {
+		cwd, err := os.Getwd()
 		if err != nil {
 			cmd.ui.Failed("Could not determine the current working directory!", err)
 			return
 		}
+		paths = []string{cwd}
+	}
+
+	vars, err := manifestVarsFromContext(c)
+	if err != nil {
+		cmd.ui.Failed("Error: %s", err)
+		return
 	}
 
-	m, manifestPath, errs := cmd.manifestRepo.ReadManifest(path)
+	m, manifestPath, errs := cmd.manifestRepo.ReadManifest(paths, vars)
 
 	if !errs.Empty() {
-		if manifestPath == "" && c.String("f") == "" {
+		if manifestPath == "" && len(c.StringSlice("f")) == 0 {
 			m = manifest.NewEmptyManifest()
 		} else {
 			cmd.ui.Failed("Error reading manifest file:\n%s", errs)
@@ -430,10 +726,38 @@ func (cmd *Push) instantiateManifest(c *cli.Context) (m *manifest.Manifest) {
 		return
 	}
 
-	cmd.ui.Say("Using manifest file %s\n", terminal.EntityNameColor(manifestPath))
+	if !c.Bool("print-manifest") {
+		cmd.ui.Say("Using manifest file %s\n", terminal.EntityNameColor(manifestPath))
+		for _, warning := range m.Warnings {
+			cmd.ui.Warn("%s", warning)
+		}
+	}
 	return
 }
 
+func manifestVarsFromContext(c *cli.Context) (vars manifest.ManifestVars, err error) {
+	vars.Files = c.StringSlice("vars-file")
+	vars.Values = map[string]string{}
+
+	for _, assignment := range c.StringSlice("var") {
+		parts := strings.SplitN(assignment, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			err = errors.New(fmt.Sprintf("Invalid var param: %s\nExpected NAME=VALUE", assignment))
+			return
+		}
+		vars.Values[parts[0]] = parts[1]
+	}
+	return
+}
+
+func (cmd *Push) printManifest(m *manifest.Manifest) {
+	if m.Document == nil {
+		cmd.ui.Failed("No manifest file found to print")
+		return
+	}
+	cmd.ui.Say("%s", strings.TrimSuffix(manifest.RenderYAML(m.Document), "\n"))
+}
+
 func (cmd *Push) createAppSetFromContextAndManifest(c *cli.Context, contextParams models.AppParams, m *manifest.Manifest) (appSet []models.AppParams, err error) {
 	if len(m.Applications) > 1 {
 		if contextParams.Name != nil {
@@ -555,5 +879,10 @@ func newAppParamsFromContext(c *cli.Context) (appParams models.AppParams, err er
 		}
 		appParams.Path = &path
 	}
+
+	if c.Bool("random-route") {
+		randomRoute := true
+		appParams.RandomRoute = &randomRoute
+	}
 	return
 }
@@ -0,0 +1,381 @@
+package application
+
+import (
+	"cf"
+	"cf/models"
+	"cf/net"
+	"cf/terminal"
+	"errors"
+	"fmt"
+	"github.com/codegangsta/cli"
+)
+
+const (
+	blueGreenStrategy  = "blue-green"
+	blueGreenNewSuffix = "-new"
+	blueGreenOldSuffix = "-old"
+)
+
+func (cmd *Push) blueGreenStrategy(c *cli.Context) bool {
+	switch c.String("strategy") {
+	case "":
+		return false
+	case blueGreenStrategy:
+	default:
+		cmd.ui.Failed("Invalid push strategy: %s\nThe only supported strategy is %s", c.String("strategy"), blueGreenStrategy)
+		return false
+	}
+
+	if c.Bool("no-start") {
+		cmd.ui.Failed("The %s strategy cannot be used with --no-start", blueGreenStrategy)
+		return false
+	}
+	if cmd.dryRun {
+		cmd.ui.Say("The %s strategy is not simulated by --dry-run, showing a regular push instead\n", blueGreenStrategy)
+		return false
+	}
+	return true
+}
+
+// pushBlueGreen pushes params to a temporary app and only moves the routes of
+// the existing app over once the new version started, by default with every
+// one of its instances running. When the new version fails it is deleted and
+// the failure is returned.
+// It returns false when there is no existing app, so a regular push is done.
+func (cmd *Push) pushBlueGreen(params models.AppParams, c *cli.Context) (pushed bool, err error) {
+	if params.Name == nil {
+		err = errors.New("Error: No name found for app")
+		return
+	}
+
+	oldApp, apiResponse := cmd.appRepo.Read(*params.Name)
+	if apiResponse.IsNotFound() {
+		cmd.ui.Say("App %s does not exist yet, nothing to keep running during the push\n", terminal.EntityNameColor(*params.Name))
+		return
+	}
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(apiResponse.Message)
+		return
+	}
+	pushed = true
+
+	// unlike the app, the summary has the domains of the routes and the
+	// services bound with bind-service
+	summary, apiResponse := cmd.appSummaryRepo.GetSummary(oldApp.Guid)
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(apiResponse.Message)
+		return
+	}
+	oldApp.Routes = summary.RouteSummaries
+
+	newParams := blueGreenAppParams(oldApp, params)
+	err = cmd.deleteLeftoverApp(*newParams.Name)
+	if err != nil {
+		return
+	}
+
+	cmd.ui.Say("Pushing the new version of %s as %s, %s keeps serving traffic\n",
+		terminal.EntityNameColor(oldApp.Name),
+		terminal.EntityNameColor(*newParams.Name),
+		terminal.EntityNameColor(oldApp.Name),
+	)
+
+	newApp, apiResponse := cmd.createApp(newParams)
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(apiResponse.Message)
+		return
+	}
+
+	params.Services = blueGreenServices(summary, params)
+	err = cmd.prepareNewVersion(oldApp, newApp, params, c)
+	if err != nil {
+		err = cmd.rollbackBlueGreen(oldApp, newApp, err.Error())
+		return
+	}
+
+	cmd.ui.Say("Mapping the routes of %s to %s...", terminal.EntityNameColor(oldApp.Name), terminal.EntityNameColor(newApp.Name))
+	for _, route := range oldApp.Routes {
+		cmd.ui.Say("Binding %s to %s...", terminal.EntityNameColor(route.URL()), terminal.EntityNameColor(newApp.Name))
+		apiResponse = cmd.routeRepo.Bind(route.Guid, newApp.Guid)
+		if apiResponse.IsNotSuccessful() {
+			err = cmd.rollbackBlueGreen(oldApp, newApp, apiResponse.Message)
+			return
+		}
+	}
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	routedApp := newApp
+	routedApp.Name = oldApp.Name
+	routedApp.Routes = oldApp.Routes
+	err = cmd.bindAppToRoute(routedApp, params, c)
+	if err != nil {
+		return
+	}
+
+	cmd.ui.Say("Unmapping the routes of the old version of %s...", terminal.EntityNameColor(oldApp.Name))
+	for _, route := range oldApp.Routes {
+		apiResponse = cmd.routeRepo.Unbind(route.Guid, oldApp.Guid)
+		if apiResponse.IsNotSuccessful() {
+			cmd.ui.Warn("Could not unbind %s from the old version: %s", route.URL(), apiResponse.Message)
+		}
+	}
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	err = cmd.retireOldApp(oldApp, newApp)
+	return
+}
+
+// deleteLeftoverApp deletes the new version of an earlier push that was
+// neither finished nor rolled back, as its name is needed again.
+func (cmd *Push) deleteLeftoverApp(name string) (err error) {
+	leftoverApp, apiResponse := cmd.appRepo.Read(name)
+	if apiResponse.IsNotFound() {
+		return
+	}
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(apiResponse.Message)
+		return
+	}
+
+	cmd.ui.Warn("App %s was left over from an earlier push that did not finish", name)
+	cmd.ui.Say("Deleting %s...", terminal.EntityNameColor(name))
+	apiResponse = cmd.appRepo.Delete(leftoverApp.Guid)
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(fmt.Sprintf("Could not delete %s: %s\nTIP: delete it with '%s delete %s' before pushing again",
+			name, apiResponse.Message, cf.Name(), name))
+		return
+	}
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+	return
+}
+
+// prepareNewVersion uploads, binds and starts the new version of the app
+// with the starter of the push, so --wait-for and the health check decide
+// whether the routes are switched.
+func (cmd *Push) prepareNewVersion(oldApp, newApp models.Application, params models.AppParams, c *cli.Context) (err error) {
+	cmd.ui.Say("Uploading %s...", terminal.EntityNameColor(newApp.Name))
+	progress := newUploadProgress(cmd.ui)
+	_, apiResponse := cmd.appBitsRepo.UploadApp(newApp.Guid, *params.Path, true, cmd.describeUploadOperation, progress)
+	progress.Done()
+	if apiResponse.IsNotSuccessful() {
+		err = fmt.Errorf("Error uploading application.\n%s", apiResponse.Message)
+		return
+	}
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	if len(*params.Services) > 0 {
+		_, err = cmd.bindAppToServices(params, newApp)
+		if err != nil {
+			return
+		}
+	}
+
+	// the new version has no routes until it is healthy, the health check
+	// requests it on a route of its own
+	if c.String("health-check-path") != "" {
+		route, created, err := cmd.mapTemporaryRoute(oldApp, newApp, c)
+		if err != nil {
+			return err
+		}
+		defer cmd.unmapTemporaryRoute(newApp, route, created)
+	}
+
+	// a copy, as the instances threshold only applies to the new version
+	starter := cmd.starter.WithUI(cmd.ui)
+	if c.String("wait-for") == "" {
+		starter.SetInstancesThreshold(InstancesThreshold{All: true})
+	}
+	if params.HealthCheckTimeout != nil {
+		starter.SetStartTimeoutSeconds(*params.HealthCheckTimeout)
+	}
+	_, err = starter.ApplicationStart(newApp)
+	return
+}
+
+// mapTemporaryRoute binds a route named after the new version of the app, in
+// the domain of the first route of the old version.
+func (cmd *Push) mapTemporaryRoute(oldApp, newApp models.Application, c *cli.Context) (route models.Route, created bool, err error) {
+	var domain models.DomainFields
+	if len(oldApp.Routes) > 0 {
+		domain = oldApp.Routes[0].Domain
+	} else {
+		domain, err = cmd.domain(c, "")
+		if err != nil {
+			return
+		}
+	}
+
+	hostName := hostNameForString(newApp.Name)
+	route, apiResponse := cmd.routeRepo.FindByHostAndDomain(hostName, domain.Name)
+	if apiResponse.IsSuccessful() && cmd.routeIsInAnotherSpace(route) {
+		hostName, err = cmd.availableHostname(hostName, domain)
+		if err != nil {
+			return
+		}
+		route, apiResponse = cmd.routeRepo.FindByHostAndDomain(hostName, domain.Name)
+	}
+	if apiResponse.IsError() {
+		err = errors.New(apiResponse.Message)
+		return
+	}
+
+	if apiResponse.IsNotFound() {
+		cmd.ui.Say("Creating route %s...", terminal.EntityNameColor(domain.UrlForHost(hostName)))
+		route, apiResponse = cmd.routeRepo.Create(hostName, domain.Guid)
+		if apiResponse.IsNotSuccessful() {
+			err = errors.New(apiResponse.Message)
+			return
+		}
+		created = true
+		cmd.ui.Ok()
+	}
+	route.Host = hostName
+	route.Domain = domain
+
+	cmd.ui.Say("Binding %s to %s to check its health...", terminal.EntityNameColor(route.URL()), terminal.EntityNameColor(newApp.Name))
+	apiResponse = cmd.routeRepo.Bind(route.Guid, newApp.Guid)
+	if apiResponse.IsNotSuccessful() {
+		if created {
+			cmd.routeRepo.Delete(route.Guid)
+		}
+		err = errors.New(apiResponse.Message)
+		return
+	}
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+	return
+}
+
+func (cmd *Push) unmapTemporaryRoute(newApp models.Application, route models.Route, created bool) {
+	if route.Guid == "" {
+		return
+	}
+
+	cmd.ui.Say("Removing the temporary route %s...", terminal.EntityNameColor(route.URL()))
+	var apiResponse net.ApiResponse
+	if created {
+		apiResponse = cmd.routeRepo.Delete(route.Guid)
+	} else {
+		apiResponse = cmd.routeRepo.Unbind(route.Guid, newApp.Guid)
+	}
+	if apiResponse.IsNotSuccessful() {
+		cmd.ui.Warn("Could not remove %s: %s", route.URL(), apiResponse.Message)
+		return
+	}
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+}
+
+// blueGreenServices are the services bound to the old version, including the
+// ones bound with bind-service, and the services of the manifest.
+func blueGreenServices(summary models.AppSummary, params models.AppParams) *[]string {
+	services := []string{}
+	seen := map[string]bool{}
+
+	names := summary.ServiceNames
+	if params.Services != nil {
+		names = append(append([]string{}, names...), *params.Services...)
+	}
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			services = append(services, name)
+		}
+	}
+	return &services
+}
+
+func blueGreenAppParams(oldApp models.Application, params models.AppParams) (newParams models.AppParams) {
+	newParams = oldApp.ToParams()
+	newParams.Guid = nil
+	newParams.State = nil
+	newParams.SpaceGuid = nil
+
+	// settings the old app left to the cloud controller defaults stay that way
+	if oldApp.BuildpackUrl == "" {
+		newParams.BuildpackUrl = nil
+	}
+	if oldApp.Command == "" {
+		newParams.Command = nil
+	}
+	if oldApp.DiskQuota == 0 {
+		newParams.DiskQuota = nil
+	}
+	if oldApp.InstanceCount == 0 {
+		newParams.InstanceCount = nil
+	}
+	if oldApp.Memory == 0 {
+		newParams.Memory = nil
+	}
+	if oldApp.Stack.Guid == "" {
+		newParams.StackGuid = nil
+	}
+
+	envVars := map[string]string{}
+	for key, val := range oldApp.EnvironmentVars {
+		envVars[key] = val
+	}
+	if params.EnvironmentVars != nil {
+		for key, val := range *params.EnvironmentVars {
+			envVars[key] = val
+		}
+	}
+
+	newParams.Merge(&params)
+	newParams.EnvironmentVars = &envVars
+
+	name := oldApp.Name + blueGreenNewSuffix
+	newParams.Name = &name
+	return
+}
+
+// rollbackBlueGreen deletes the new version of the app. The returned error
+// tells why the push failed and whether the old version was left unchanged.
+func (cmd *Push) rollbackBlueGreen(oldApp, newApp models.Application, reason string) error {
+	cmd.ui.Say("")
+	cmd.ui.Warn("The new version of %s is not healthy, rolling back", oldApp.Name)
+	cmd.ui.Say("Deleting %s...", terminal.EntityNameColor(newApp.Name))
+
+	apiResponse := cmd.appRepo.Delete(newApp.Guid)
+	if apiResponse.IsNotSuccessful() {
+		return errors.New(fmt.Sprintf("%s\n\nRollback failed, could not delete %s: %s", reason, newApp.Name, apiResponse.Message))
+	}
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	return errors.New(fmt.Sprintf("%s\n\n%s was left unchanged", reason, oldApp.Name))
+}
+
+func (cmd *Push) retireOldApp(oldApp, newApp models.Application) (err error) {
+	oldName := oldApp.Name + blueGreenOldSuffix
+	cmd.ui.Say("Renaming %s to %s...", terminal.EntityNameColor(oldApp.Name), terminal.EntityNameColor(oldName))
+	_, apiResponse := cmd.appRepo.Update(oldApp.Guid, models.AppParams{Name: &oldName})
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(fmt.Sprintf("%s\n\nThe new version is serving traffic as %s", apiResponse.Message, newApp.Name))
+		return
+	}
+	cmd.ui.Ok()
+
+	cmd.ui.Say("Renaming %s to %s...", terminal.EntityNameColor(newApp.Name), terminal.EntityNameColor(oldApp.Name))
+	_, apiResponse = cmd.appRepo.Update(newApp.Guid, models.AppParams{Name: &oldApp.Name})
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(fmt.Sprintf("%s\n\nThe new version is serving traffic as %s", apiResponse.Message, newApp.Name))
+		return
+	}
+	cmd.ui.Ok()
+
+	cmd.ui.Say("Deleting the old version %s...", terminal.EntityNameColor(oldName))
+	apiResponse = cmd.appRepo.Delete(oldApp.Guid)
+	if apiResponse.IsNotSuccessful() {
+		cmd.ui.Warn("Could not delete %s: %s", oldName, apiResponse.Message)
+		return
+	}
+	cmd.ui.Ok()
+
+	cmd.ui.Say(terminal.HeaderColor(fmt.Sprintf("\nApp %s was replaced without downtime\n", oldApp.Name)))
+	return
+}
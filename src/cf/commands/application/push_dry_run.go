@@ -0,0 +1,156 @@
+package application
+
+import (
+	"cf/formatters"
+	"cf/models"
+	"cf/terminal"
+	"errors"
+	"fmt"
+	"github.com/codegangsta/cli"
+	"sort"
+	"strconv"
+)
+
+type appFieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+func (cmd *Push) planCreateOrUpdateApp(app models.Application, notFound bool, params models.AppParams) (plannedApp models.Application, restartNeeded bool) {
+	if notFound {
+		cmd.ui.Say("Would create app %s in org %s / space %s",
+			terminal.EntityNameColor(*params.Name),
+			terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		)
+		plannedApp.Name = *params.Name
+		restartNeeded = true
+	} else {
+		cmd.ui.Say("Would update app %s in org %s / space %s",
+			terminal.EntityNameColor(app.Name),
+			terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		)
+		plannedApp = app
+		restartNeeded = appParamsRequireRestart(app, params)
+	}
+
+	changes := appParamsChanges(plannedApp, params)
+	if len(changes) == 0 {
+		cmd.ui.Say("  no changes to app settings")
+	}
+	for _, change := range changes {
+		if notFound {
+			cmd.ui.Say("  %s: %s", change.Field, change.To)
+		} else {
+			cmd.ui.Say("  %s: %s -> %s", change.Field, change.From, change.To)
+		}
+	}
+	cmd.ui.Say("")
+	return
+}
+
+func (cmd *Push) planUploadAndRestart(app models.Application, params models.AppParams, restartNeeded bool, c *cli.Context) (err error) {
+	plan, apiResponse := cmd.appBitsRepo.PlanUpload(app.Guid, *params.Path, c.Bool("force-upload"))
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(fmt.Sprintf("Error matching application files.\n%s", apiResponse.Message))
+		return
+	}
+
+	if plan.Unchanged {
+		cmd.ui.Say("App bits are unchanged since the last upload, nothing to upload")
+	} else {
+		cmd.ui.Say("Would upload %d of %d files from %s, %s",
+			plan.UploadFileCount,
+			plan.TotalFileCount,
+			*params.Path,
+			formatters.ByteSize(plan.UploadBytes),
+		)
+		restartNeeded = true
+	}
+
+	if params.Services != nil {
+		var boundNewService bool
+		boundNewService, err = cmd.bindAppToServices(params, app)
+		if err != nil {
+			return
+		}
+		if boundNewService {
+			restartNeeded = true
+		}
+	}
+
+	switch {
+	case c.Bool("no-start"):
+		cmd.ui.Say("Would not start %s\n", terminal.EntityNameColor(app.Name))
+	case restartNeeded:
+		cmd.ui.Say("Would restart %s\n", terminal.EntityNameColor(app.Name))
+	default:
+		cmd.ui.Say("App %s is up to date, would skip restart\n", terminal.EntityNameColor(app.Name))
+	}
+	return
+}
+
+func appParamsChanges(app models.Application, params models.AppParams) (changes []appFieldChange) {
+	addChange := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, appFieldChange{Field: field, From: from, To: to})
+		}
+	}
+
+	if params.Memory != nil {
+		addChange("memory", megabytes(app.Memory), megabytes(*params.Memory))
+	}
+	if params.DiskQuota != nil {
+		addChange("disk quota", megabytes(app.DiskQuota), megabytes(*params.DiskQuota))
+	}
+	if params.InstanceCount != nil {
+		addChange("instances", strconv.Itoa(app.InstanceCount), strconv.Itoa(*params.InstanceCount))
+	}
+	if params.Command != nil {
+		addChange("command", strconv.Quote(app.Command), strconv.Quote(*params.Command))
+	}
+	if params.BuildpackUrl != nil {
+		addChange("buildpack", strconv.Quote(app.BuildpackUrl), strconv.Quote(*params.BuildpackUrl))
+	}
+	if params.StackName != nil {
+		addChange("stack", strconv.Quote(app.Stack.Name), strconv.Quote(*params.StackName))
+	}
+
+	if params.EnvironmentVars != nil {
+		keys := []string{}
+		for key := range *params.EnvironmentVars {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			from := "(not set)"
+			if val, ok := app.EnvironmentVars[key]; ok {
+				from = strconv.Quote(val)
+			}
+			addChange("env "+key, from, strconv.Quote((*params.EnvironmentVars)[key]))
+		}
+	}
+	return
+}
+
+func megabytes(size uint64) string {
+	if size == 0 {
+		return "(default)"
+	}
+	return formatters.ByteSize(size * formatters.MEGABYTE)
+}
+
+func serviceInstanceIsBoundToApp(serviceInstance models.ServiceInstance, app models.Application) bool {
+	if app.Guid == "" {
+		return false
+	}
+	for _, binding := range serviceInstance.ServiceBindings {
+		if binding.AppGuid == app.Guid {
+			return true
+		}
+	}
+	return false
+}
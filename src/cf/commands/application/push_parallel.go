@@ -0,0 +1,292 @@
+package application
+
+import (
+	"cf/models"
+	"cf/terminal"
+	"errors"
+	"fmt"
+	"github.com/codegangsta/cli"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	appPushPending = iota
+	appPushRunning
+	appPushSucceeded
+	appPushUpToDate
+	appPushFailed
+	appPushSkipped
+)
+
+type appPushResult struct {
+	state   int
+	message string
+}
+
+type finishedAppPush struct {
+	index  int
+	result appPushResult
+}
+
+func (cmd *Push) parallelism(c *cli.Context) (parallelism int) {
+	if c.String("parallel") == "" {
+		return
+	}
+
+	parallelism, err := strconv.Atoi(c.String("parallel"))
+	if err != nil || parallelism < 1 {
+		cmd.ui.Failed("Invalid parallel param: %s\nExpected a number of apps to push at once", c.String("parallel"))
+		return 0
+	}
+	return
+}
+
+// orderAppsByDependencies moves every app after the apps listed in its
+// depends_on, keeping the manifest order otherwise. Dependencies on apps
+// that are not being pushed are ignored.
+func orderAppsByDependencies(apps []models.AppParams) (ordered []models.AppParams, err error) {
+	indexByName := map[string]int{}
+	for index, app := range apps {
+		if app.Name != nil {
+			indexByName[*app.Name] = index
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make([]int, len(apps))
+
+	var visit func(index int, path []string) error
+	visit = func(index int, path []string) error {
+		app := apps[index]
+		switch states[index] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.New(fmt.Sprintf("Circular depends_on between apps: %s", strings.Join(append(path, *app.Name), " -> ")))
+		}
+
+		states[index] = visiting
+		for _, name := range appDependencies(app) {
+			depIndex, found := indexByName[name]
+			if !found {
+				continue
+			}
+			if err := visit(depIndex, append(path, *app.Name)); err != nil {
+				return err
+			}
+		}
+		states[index] = visited
+
+		ordered = append(ordered, app)
+		return nil
+	}
+
+	for index := range apps {
+		err = visit(index, []string{})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func appDependencies(app models.AppParams) []string {
+	if app.DependsOn == nil {
+		return []string{}
+	}
+	return *app.DependsOn
+}
+
+// pushAppsInParallel pushes up to parallelism apps at once. An app is only
+// pushed once all the apps it depends on have been pushed successfully.
+func (cmd *Push) pushAppsInParallel(appSet []models.AppParams, parallelism int, blueGreen bool, c *cli.Context) {
+	uiMutex := new(sync.Mutex)
+	results := make([]appPushResult, len(appSet))
+	finished := make(chan finishedAppPush)
+
+	indexByName := map[string]int{}
+	for index, app := range appSet {
+		indexByName[*app.Name] = index
+	}
+
+	running := 0
+	for {
+		for index, app := range appSet {
+			if results[index].state != appPushPending || running >= parallelism {
+				continue
+			}
+
+			ready, failedDependency := dependenciesPushed(app, indexByName, results)
+			if failedDependency != "" {
+				results[index] = appPushResult{appPushSkipped, fmt.Sprintf("%s was not pushed", failedDependency)}
+				continue
+			}
+			if !ready {
+				continue
+			}
+
+			results[index].state = appPushRunning
+			running++
+
+			go func(index int, app models.AppParams) {
+				appCmd := *cmd
+				appCmd.ui = newPrefixedUI(cmd.ui, *app.Name, uiMutex)
+				appCmd.starter = cmd.starter.WithUI(appCmd.ui)
+				appCmd.stopper = cmd.stopper.WithUI(appCmd.ui)
+				finished <- finishedAppPush{index, appCmd.pushAppForSummary(app, blueGreen, c)}
+			}(index, app)
+		}
+
+		if running == 0 {
+			break
+		}
+		push := <-finished
+		results[push.index] = push.result
+		running--
+	}
+
+	cmd.showPushSummary(appSet, results)
+}
+
+func dependenciesPushed(app models.AppParams, indexByName map[string]int, results []appPushResult) (ready bool, failedDependency string) {
+	ready = true
+	for _, name := range appDependencies(app) {
+		index, found := indexByName[name]
+		if !found {
+			continue
+		}
+
+		switch results[index].state {
+		case appPushFailed, appPushSkipped:
+			failedDependency = name
+			return
+		case appPushSucceeded, appPushUpToDate:
+		default:
+			ready = false
+		}
+	}
+	return
+}
+
+// pushAppForSummary pushes a single app and reports its failure under its
+// name, so that the other apps keep being pushed.
+func (cmd *Push) pushAppForSummary(app models.AppParams, blueGreen bool, c *cli.Context) (result appPushResult) {
+	upToDate, err := cmd.pushApp(app, blueGreen, c)
+	switch {
+	case err != nil:
+		cmd.ui.Say("%s", terminal.FailureColor("FAILED"))
+		cmd.ui.Say("%s", err.Error())
+		result = appPushResult{appPushFailed, err.Error()}
+	case upToDate:
+		result.state = appPushUpToDate
+	default:
+		result.state = appPushSucceeded
+	}
+	return
+}
+
+func (cmd *Push) showPushSummary(appSet []models.AppParams, results []appPushResult) {
+	cmd.ui.Say("")
+	table := cmd.ui.Table([]string{"app", "result", "details"})
+	rows := [][]string{}
+	failedCount := 0
+
+	for index, app := range appSet {
+		var result string
+		switch results[index].state {
+		case appPushSucceeded:
+			result = terminal.SuccessColor("pushed")
+		case appPushUpToDate:
+			result = terminal.SuccessColor("up to date")
+		case appPushSkipped:
+			result = terminal.WarningColor("skipped")
+			failedCount++
+		default:
+			result = terminal.FailureColor("failed")
+			failedCount++
+		}
+
+		details := strings.Split(results[index].message, "\n")[0]
+		rows = append(rows, []string{*app.Name, result, details})
+	}
+
+	table.Print(rows)
+
+	if failedCount > 0 {
+		cmd.ui.Say("")
+		cmd.ui.Failed("%d of %d apps were not pushed", failedCount, len(appSet))
+	}
+}
+
+// prefixedUI lets the apps pushed in parallel share the terminal: every line
+// is prefixed with the app name and written while holding a shared lock.
+type prefixedUI struct {
+	terminal.UI
+	prefix string
+	mutex  *sync.Mutex
+}
+
+func newPrefixedUI(ui terminal.UI, name string, mutex *sync.Mutex) *prefixedUI {
+	return &prefixedUI{UI: ui, prefix: fmt.Sprintf("[%s] ", name), mutex: mutex}
+}
+
+func (ui *prefixedUI) PrintPaginator(rows []string, err error) {
+	if err != nil {
+		ui.Failed(err.Error())
+		return
+	}
+	for _, row := range rows {
+		ui.Say("%s", row)
+	}
+}
+
+func (ui *prefixedUI) Say(message string, args ...interface{}) {
+	lines := strings.Split(fmt.Sprintf(message, args...), "\n")
+
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	for _, line := range lines {
+		ui.UI.Say("%s%s", ui.prefix, line)
+	}
+}
+
+func (ui *prefixedUI) Warn(message string, args ...interface{}) {
+	ui.Say("%s", terminal.WarningColor(fmt.Sprintf(message, args...)))
+}
+
+func (ui *prefixedUI) Ok() {
+	ui.Say("%s", terminal.SuccessColor("OK"))
+}
+
+func (ui *prefixedUI) LoadingIndication() {}
+
+func (ui *prefixedUI) Table(headers []string) terminal.Table {
+	return terminal.NewTable(ui, headers)
+}
+
+func (ui *prefixedUI) NewProgressBar() terminal.ProgressBar {
+	return silentProgress{}
+}
+
+func (ui *prefixedUI) StartPhase(message string, args ...interface{}) terminal.PhaseIndicator {
+	ui.Say(message+"...", args...)
+	return silentProgress{}
+}
+
+func (ui *prefixedUI) NewLiveView() terminal.LiveView {
+	return terminal.NewAppendingView(ui)
+}
+
+// silentProgress hides progress bars that would garble the interleaved output.
+type silentProgress struct{}
+
+func (progress silentProgress) Update(current, total int64) {}
+func (progress silentProgress) Finish()                     {}
+func (progress silentProgress) Stop()                       {}
@@ -0,0 +1,210 @@
+package application
+
+import (
+	"cf/models"
+	"cf/terminal"
+	"errors"
+	"fmt"
+	"github.com/codegangsta/cli"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxHostnameAttempts = 10
+
+var (
+	hostAdjectives = []string{"brave", "calm", "eager", "fancy", "gentle", "happy", "jolly", "lucky", "proud", "quick", "silly", "witty"}
+	hostNouns      = []string{"badger", "bison", "falcon", "gecko", "heron", "koala", "lynx", "otter", "panda", "walrus", "yak", "zebra"}
+	hostRandom     = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	// hostRandom is not safe for concurrent use, apps pushed in parallel
+	// pick their random hosts at the same time
+	hostRandomMutex sync.Mutex
+)
+
+func randomWord() string {
+	hostRandomMutex.Lock()
+	defer hostRandomMutex.Unlock()
+
+	return fmt.Sprintf("%s-%s", hostAdjectives[hostRandom.Intn(len(hostAdjectives))], hostNouns[hostRandom.Intn(len(hostNouns))])
+}
+
+func appParamsHaveRoutes(params models.AppParams) bool {
+	return params.Routes != nil || params.Hosts != nil || params.Domains != nil
+}
+
+// bindAppToRoutes binds every combination of the hosts and domains of the
+// app, as well as each of its routes. With --prune-routes, the routes of the
+// app that are not among them are unbound.
+func (cmd *Push) bindAppToRoutes(app models.Application, params models.AppParams, c *cli.Context) (err error) {
+	wantedURLs := map[string]bool{}
+	bindRoute := func(hostName string, domain models.DomainFields) (err error) {
+		url := domain.UrlForHost(hostName)
+		if wantedURLs[url] {
+			return
+		}
+		wantedURLs[url] = true
+
+		route, err := cmd.route(hostName, domain)
+		if err != nil {
+			return
+		}
+		wantedURLs[route.URL()] = true
+		return cmd.bindRouteToApp(app, route, route.URL())
+	}
+
+	hostNames := appendNonNil(params.Host, params.Hosts)
+	domainNames := appendNonNil(params.Domain, params.Domains)
+	randomRoute := params.RandomRoute != nil && *params.RandomRoute
+	if len(hostNames) > 0 || len(domainNames) > 0 {
+		if len(domainNames) == 0 {
+			domainNames = []string{""}
+		}
+
+		for _, domainName := range domainNames {
+			var domain models.DomainFields
+			domain, err = cmd.domain(c, domainName)
+			if err != nil {
+				return
+			}
+
+			switch {
+			case len(hostNames) > 0:
+				for _, hostName := range hostNames {
+					err = bindRoute(hostName, domain)
+					if err != nil {
+						return
+					}
+				}
+			case randomRoute:
+				var hostName string
+				hostName, err = cmd.randomHostnameInDomain(app, domain)
+				if err != nil {
+					return
+				}
+				err = bindRoute(hostName, domain)
+			default:
+				err = bindRoute(hostNameForString(app.Name), domain)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	if params.Routes != nil {
+		for _, url := range *params.Routes {
+			hostName, domain, err := cmd.splitRouteURL(url)
+			if err != nil {
+				return err
+			}
+			err = bindRoute(hostName, domain)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.Bool("prune-routes") {
+		err = cmd.unbindOtherRoutes(app, wantedURLs)
+	}
+	return
+}
+
+func appendNonNil(value *string, values *[]string) (result []string) {
+	if value != nil {
+		result = append(result, *value)
+	}
+	if values != nil {
+		result = append(result, *values...)
+	}
+	return
+}
+
+// splitRouteURL finds the domain of a route: either the whole URL is a
+// domain, or everything after the host is.
+func (cmd *Push) splitRouteURL(url string) (hostName string, domain models.DomainFields, err error) {
+	orgGuid := cmd.config.OrganizationFields().Guid
+
+	domain, apiResponse := cmd.domainRepo.FindByNameInOrg(url, orgGuid)
+	if apiResponse.IsSuccessful() {
+		return
+	}
+	if !apiResponse.IsNotFound() {
+		err = errors.New(apiResponse.Message)
+		return
+	}
+
+	parts := strings.SplitN(url, ".", 2)
+	if len(parts) == 2 {
+		domain, apiResponse = cmd.domainRepo.FindByNameInOrg(parts[1], orgGuid)
+		if apiResponse.IsSuccessful() {
+			hostName = parts[0]
+			return
+		}
+		if !apiResponse.IsNotFound() {
+			err = errors.New(apiResponse.Message)
+			return
+		}
+	}
+
+	err = errors.New(fmt.Sprintf("Could not find a domain for route %s", url))
+	return
+}
+
+func (cmd *Push) unbindOtherRoutes(app models.Application, wantedURLs map[string]bool) (err error) {
+	for _, route := range app.Routes {
+		if wantedURLs[route.URL()] {
+			continue
+		}
+
+		if cmd.dryRun {
+			cmd.ui.Say("Would unbind %s from %s\n", terminal.EntityNameColor(route.URL()), terminal.EntityNameColor(app.Name))
+			continue
+		}
+
+		cmd.ui.Say("Unbinding %s from %s...", terminal.EntityNameColor(route.URL()), terminal.EntityNameColor(app.Name))
+		apiResponse := cmd.routeRepo.Unbind(route.Guid, app.Guid)
+		if apiResponse.IsNotSuccessful() {
+			err = errors.New(apiResponse.Message)
+			return
+		}
+		cmd.ui.Ok()
+		cmd.ui.Say("")
+	}
+	return
+}
+
+func (cmd *Push) routeIsInAnotherSpace(route models.Route) bool {
+	return route.Space.Guid != "" && route.Space.Guid != cmd.config.SpaceFields().Guid
+}
+
+// availableHostname adds random words to the host until no route in the
+// domain uses it.
+func (cmd *Push) availableHostname(hostName string, domain models.DomainFields) (string, error) {
+	for attempt := 0; attempt < maxHostnameAttempts; attempt++ {
+		candidate := fmt.Sprintf("%s-%s", hostName, cmd.RandomWord())
+		_, apiResponse := cmd.routeRepo.FindByHostAndDomain(candidate, domain.Name)
+		if apiResponse.IsNotFound() {
+			return candidate, nil
+		}
+		if apiResponse.IsError() {
+			return "", errors.New(apiResponse.Message)
+		}
+	}
+
+	return "", errors.New(fmt.Sprintf("Could not find an unused host for %s after %d attempts", domain.UrlForHost(hostName), maxHostnameAttempts))
+}
+
+// randomHostnameInDomain keeps the host the app already has in the domain, so
+// that pushing again does not add another random route.
+func (cmd *Push) randomHostnameInDomain(app models.Application, domain models.DomainFields) (string, error) {
+	for _, route := range app.Routes {
+		if route.Domain.Guid == domain.Guid {
+			return route.Host, nil
+		}
+	}
+	return cmd.availableHostname(hostNameForString(app.Name), domain)
+}
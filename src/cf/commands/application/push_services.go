@@ -0,0 +1,112 @@
+package application
+
+import (
+	"cf/models"
+	"cf/net"
+	"cf/terminal"
+	"errors"
+	"fmt"
+)
+
+func declaredServiceInstance(params models.AppParams, serviceName string) (instance models.ServiceInstanceParams, found bool) {
+	if params.ServiceInstances == nil {
+		return
+	}
+	for _, instance = range *params.ServiceInstances {
+		if instance.Name == serviceName {
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// createServiceInstance creates a service instance declared in the manifest
+// and looks it up again so that it can be bound.
+func (cmd *Push) createServiceInstance(params models.ServiceInstanceParams) (instance models.ServiceInstance, apiResponse net.ApiResponse, err error) {
+	cmd.ui.Say("Creating service %s in org %s / space %s as %s...",
+		terminal.EntityNameColor(params.Name),
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+	)
+
+	if params.IsUserProvided() {
+		apiResponse = cmd.userProvided.Create(params.Name, "", params.Credentials)
+		if apiResponse.IsNotSuccessful() {
+			err = errors.New(apiResponse.Message)
+			return
+		}
+		cmd.ui.Ok()
+		instance, apiResponse = cmd.serviceRepo.FindInstanceByName(params.Name)
+		return
+	}
+
+	planGuid, err := cmd.findServicePlanGuid(params)
+	if err != nil {
+		return
+	}
+
+	identicalAlreadyExists, apiResponse := cmd.serviceRepo.CreateServiceInstance(params.Name, planGuid)
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(apiResponse.Message)
+		return
+	}
+
+	cmd.ui.Ok()
+	if identicalAlreadyExists {
+		cmd.ui.Warn("Service %s already exists", params.Name)
+	}
+	instance, apiResponse = cmd.serviceRepo.FindInstanceByName(params.Name)
+	return
+}
+
+func (cmd *Push) findServicePlanGuid(params models.ServiceInstanceParams) (planGuid string, err error) {
+	offerings, apiResponse := cmd.serviceRepo.GetServiceOfferingsForSpace(cmd.config.SpaceFields().Guid)
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(apiResponse.Message)
+		return
+	}
+
+	for _, offering := range offerings {
+		if offering.Label != params.Label || (params.Provider != "" && offering.Provider != params.Provider) {
+			continue
+		}
+		for _, plan := range offering.Plans {
+			if plan.Name == params.Plan {
+				planGuid = plan.Guid
+				return
+			}
+		}
+		err = errors.New(fmt.Sprintf("Could not find plan %s of service %s", params.Plan, params.Label))
+		return
+	}
+
+	if params.Provider != "" {
+		err = errors.New(fmt.Sprintf("Could not find service %s from provider %s", params.Label, params.Provider))
+		return
+	}
+	err = errors.New(fmt.Sprintf("Could not find service %s", params.Label))
+	return
+}
+
+// warnAboutServiceMismatch reports an existing instance that differs from
+// the manifest. Push does not change existing instances.
+func (cmd *Push) warnAboutServiceMismatch(instance models.ServiceInstance, params models.ServiceInstanceParams) {
+	if params.IsUserProvided() {
+		if !instance.IsUserProvided() {
+			cmd.ui.Warn("Service %s is not user-provided, but the manifest gives it credentials", params.Name)
+		}
+		return
+	}
+
+	if instance.IsUserProvided() {
+		cmd.ui.Warn("Service %s is user-provided, but the manifest declares plan %s of %s", params.Name, params.Plan, params.Label)
+		return
+	}
+
+	if instance.ServicePlan.Name != params.Plan || instance.ServiceOffering.Label != params.Label {
+		cmd.ui.Warn("Service %s uses plan %s of %s, but the manifest declares plan %s of %s",
+			params.Name, instance.ServicePlan.Name, instance.ServiceOffering.Label, params.Plan, params.Label)
+	}
+}
@@ -1,11 +1,13 @@
 package application_test
 
 import (
+	"cf/api"
 	. "cf/commands/application"
 	"cf/manifest"
 	"cf/models"
 	"cf/net"
 	"errors"
+	"fmt"
 	"generic"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -20,6 +22,7 @@ import (
 	testmanifest "testhelpers/manifest"
 	testreq "testhelpers/requirements"
 	testterm "testhelpers/terminal"
+	"time"
 )
 
 var _ = Describe("Push Command", func() {
@@ -38,7 +41,7 @@ var _ = Describe("Push Command", func() {
 		appBitsRepo := deps.appBitsRepo
 		serviceRepo := deps.serviceRepo
 
-		cmd := NewPush(ui, configRepo, manifestRepo, starter, stopper, binder, appRepo, domainRepo, routeRepo, stackRepo, serviceRepo, appBitsRepo)
+		cmd := NewPush(ui, configRepo, manifestRepo, starter, stopper, binder, appRepo, domainRepo, routeRepo, stackRepo, serviceRepo, appBitsRepo, deps.appInstancesRepo, deps.userProvidedRepo, deps.appSummaryRepo)
 		ctxt := testcmd.NewContext("push", []string{})
 
 		reqFactory := &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
@@ -129,6 +132,40 @@ var _ = Describe("Push Command", func() {
 		Expect(deps.starter.Timeout).To(Equal(111))
 	})
 
+	It("TestPushingAppWaitingForAllInstances", func() {
+		deps := getPushDependencies()
+		deps.routeRepo.FindByHostAndDomainErr = true
+		deps.appRepo.ReadNotFound = true
+
+		callPush([]string{"--wait-for", "all", "my-new-app"}, deps)
+
+		Expect(deps.starter.AppToStart.Name).To(Equal("my-new-app"))
+		Expect(deps.starter.InstancesThreshold).To(Equal(InstancesThreshold{All: true}))
+	})
+
+	It("TestPushingAppWithAHealthCheckPath", func() {
+		deps := getPushDependencies()
+		deps.routeRepo.FindByHostAndDomainErr = true
+		deps.appRepo.ReadNotFound = true
+
+		callPush([]string{"--health-check-path", "/health", "--expect-status", "204", "my-new-app"}, deps)
+
+		Expect(deps.starter.HealthCheck).To(Equal(HealthCheck{Path: "/health", ExpectedStatus: 204, Timeout: time.Minute}))
+	})
+
+	It("TestPushingAppWithAnInvalidWaitFor", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+
+		ui := callPush([]string{"--wait-for", "most", "my-new-app"}, deps)
+
+		Expect(deps.starter.AppToStart.Name).To(Equal(""))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid wait-for param: most"},
+		})
+	})
+
 	It("TestPushingAppWithACrazyName", func() {
 		deps := getPushDependencies()
 
@@ -452,6 +489,120 @@ var _ = Describe("Push Command", func() {
 		})
 	})
 
+	It("TestPushCreatesMissingServicesDeclaredInTheManifest", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.manifestRepo.ReadManifestReturns.Manifest = manifestWithDeclaredServices()
+		deps.serviceRepo.GetServiceOfferingsForSpaceReturns.ServiceOfferings = postgresServiceOfferings()
+		deps.serviceRepo.MissingInstanceNames = []string{"db", "creds"}
+		deps.serviceRepo.FindInstanceByNameMap = generic.NewMap(map[interface{}]interface{}{
+			"db":    maker.NewServiceInstance("db"),
+			"creds": maker.NewServiceInstance("creds"),
+		})
+
+		ui := callPush([]string{}, deps)
+
+		Expect(deps.serviceRepo.GetServiceOfferingsForSpaceArgs.SpaceGuid).To(Equal("my-space-guid"))
+		Expect(deps.serviceRepo.CreateServiceInstanceName).To(Equal("db"))
+		Expect(deps.serviceRepo.CreateServiceInstancePlanGuid).To(Equal("small-plan-guid"))
+		Expect(deps.userProvidedRepo.CreateName).To(Equal("creds"))
+		Expect(deps.userProvidedRepo.CreateParams).To(Equal(map[string]string{"username": "admin"}))
+
+		Expect(len(deps.binder.InstancesToBindTo)).To(Equal(2))
+		Expect(deps.binder.InstancesToBindTo[0].Name).To(Equal("db"))
+		Expect(deps.binder.InstancesToBindTo[1].Name).To(Equal("creds"))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Creating service", "db"},
+			{"OK"},
+			{"Binding service", "db"},
+			{"Creating service", "creds"},
+			{"OK"},
+			{"Binding service", "creds"},
+		})
+	})
+
+	It("TestPushTreatsAnIdenticalServiceAsCreated", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.manifestRepo.ReadManifestReturns.Manifest = manifestWithDeclaredServices()
+		deps.serviceRepo.GetServiceOfferingsForSpaceReturns.ServiceOfferings = postgresServiceOfferings()
+		deps.serviceRepo.MissingInstanceNames = []string{"db"}
+		deps.serviceRepo.CreateServiceAlreadyExists = true
+
+		ui := callPush([]string{}, deps)
+
+		Expect(len(deps.binder.InstancesToBindTo)).To(Equal(2))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Creating service", "db"},
+			{"Service db already exists"},
+			{"Binding service", "db"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+		})
+	})
+
+	It("TestPushReportsServicesWithAnotherPlan", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.manifestRepo.ReadManifestReturns.Manifest = manifestWithDeclaredServices()
+
+		db := maker.NewServiceInstance("db")
+		db.ServicePlan = models.ServicePlanFields{Name: "large", Guid: "large-plan-guid"}
+		db.ServiceOffering = models.ServiceOfferingFields{Label: "postgres"}
+		deps.serviceRepo.FindInstanceByNameMap = generic.NewMap(map[interface{}]interface{}{
+			"db":    db,
+			"creds": maker.NewServiceInstance("creds"),
+		})
+
+		ui := callPush([]string{}, deps)
+
+		Expect(deps.serviceRepo.CreateServiceInstanceName).To(Equal(""))
+		Expect(deps.userProvidedRepo.CreateName).To(Equal(""))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Service db uses plan large of postgres, but the manifest declares plan small of postgres"},
+			{"Binding service", "db"},
+		})
+	})
+
+	It("TestPushFailsWhenTheDeclaredPlanDoesNotExist", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		m := manifestWithDeclaredServices()
+		(*m.Applications[0].ServiceInstances)[0].Plan = "tiny"
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+		deps.serviceRepo.GetServiceOfferingsForSpaceReturns.ServiceOfferings = postgresServiceOfferings()
+		deps.serviceRepo.MissingInstanceNames = []string{"db"}
+
+		ui := callPush([]string{}, deps)
+
+		Expect(deps.serviceRepo.CreateServiceInstanceName).To(Equal(""))
+		Expect(deps.binder.InstancesToBindTo).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Could not find plan tiny of service postgres"},
+		})
+	})
+
+	It("TestPushDryRunWithMissingDeclaredServices", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.manifestRepo.ReadManifestReturns.Manifest = manifestWithDeclaredServices()
+		deps.serviceRepo.MissingInstanceNames = []string{"db", "creds"}
+
+		ui := callPush([]string{"--dry-run"}, deps)
+
+		Expect(deps.serviceRepo.CreateServiceInstanceName).To(Equal(""))
+		Expect(deps.userProvidedRepo.CreateName).To(Equal(""))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Would create service", "db"},
+			{"Would bind service", "db"},
+			{"Would create service", "creds"},
+			{"Would bind service", "creds"},
+		})
+	})
+
 	It("TestPushingAppWithPath", func() {
 		deps := getPushDependencies()
 		deps.appRepo.ReadNotFound = true
@@ -555,7 +706,94 @@ var _ = Describe("Push Command", func() {
 		})
 
 		cwd, _ := os.Getwd()
-		Expect(deps.manifestRepo.ReadManifestArgs.Path).To(Equal(cwd))
+		Expect(deps.manifestRepo.ReadManifestArgs.Paths).To(Equal([]string{cwd}))
+	})
+
+	It("TestPushingWarnsAboutUnknownManifestKeys", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		m := singleAppManifest()
+		m.Warnings = manifest.ManifestErrors{
+			errors.New(`manifest.yml:7: app "manifest-app-name": Unknown key 'instance', did you mean 'instances'?`),
+		}
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+		deps.manifestRepo.ReadManifestReturns.Path = "manifest.yml"
+
+		ui := callPush([]string{}, deps)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Using manifest file", "manifest.yml"},
+			{"manifest.yml:7", "Unknown key 'instance'", "did you mean 'instances'?"},
+			{"Creating app", "manifest-app-name"},
+		})
+	})
+
+	It("TestPushingWithSeveralManifests", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
+		deps.manifestRepo.ReadManifestReturns.Path = "base.yml, prod.yml"
+
+		ui := callPush([]string{"-f", "base.yml", "-f", "prod.yml"}, deps)
+
+		Expect(deps.manifestRepo.ReadManifestArgs.Paths).To(Equal([]string{"base.yml", "prod.yml"}))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Using manifest file", "base.yml, prod.yml"},
+		})
+	})
+
+	It("TestPushingWithManifestVars", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
+		deps.manifestRepo.ReadManifestReturns.Path = "manifest.yml"
+
+		callPush([]string{
+			"--vars-file", "vars.yml",
+			"--vars-file", "prod-vars.yml",
+			"--var", "env=prod",
+			"--var", "url=http://example.com/?a=b",
+		}, deps)
+
+		Expect(deps.manifestRepo.ReadManifestArgs.Vars.Files).To(Equal([]string{"vars.yml", "prod-vars.yml"}))
+		Expect(deps.manifestRepo.ReadManifestArgs.Vars.Values).To(Equal(map[string]string{
+			"env": "prod",
+			"url": "http://example.com/?a=b",
+		}))
+	})
+
+	It("TestPushingWithAnInvalidManifestVar", func() {
+		deps := getPushDependencies()
+
+		ui := callPush([]string{"--var", "no-value", "app-name"}, deps)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid var param", "no-value"},
+		})
+		Expect(deps.appRepo.CreateAppParams).To(BeEmpty())
+	})
+
+	It("TestPushingPrintsTheInterpolatedManifest", func() {
+		deps := getPushDependencies()
+		m := singleAppManifest()
+		m.Document = generic.NewMap(map[string]interface{}{
+			"applications": []interface{}{
+				map[string]interface{}{"name": "my-app", "memory": "256M"},
+			},
+		})
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+		deps.manifestRepo.ReadManifestReturns.Path = "manifest.yml"
+
+		ui := callPush([]string{"--print-manifest", "--var", "env=prod"}, deps)
+
+		Expect(ui.Outputs).To(Equal([]string{"---", "applications:", "- memory: 256M", "  name: my-app"}))
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"Using manifest file"},
+			{"Creating app"},
+		})
+		Expect(deps.manifestRepo.ReadManifestArgs.Vars.Values).To(Equal(map[string]string{"env": "prod"}))
+		Expect(deps.appRepo.CreateAppParams).To(BeEmpty())
 	})
 
 	It("TestPushingWithNoManifestFlag", func() {
@@ -569,7 +807,7 @@ var _ = Describe("Push Command", func() {
 			{"hacker-manifesto"},
 		})
 
-		Expect(deps.manifestRepo.ReadManifestArgs.Path).To(Equal(""))
+		Expect(deps.manifestRepo.ReadManifestArgs.Paths).To(BeEmpty())
 		Expect(*deps.appRepo.CreatedAppParams().Name).To(Equal("app-name"))
 	})
 
@@ -695,6 +933,745 @@ var _ = Describe("Push Command", func() {
 		Expect(deps.stopper.AppToStop.Guid).To(Equal(""))
 	})
 
+	It("TestPushingAppWithUnchangedBitsAndParamsSkipsRestart", func() {
+		deps := getPushDependencies()
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app"})
+		existingApp.Routes = []models.RouteSummary{{}}
+		deps.appRepo.ReadApp = existingApp
+		deps.appRepo.UpdateAppResult = existingApp
+		deps.appBitsRepo.BitsUnchanged = true
+
+		ui := callPush([]string{"existing-app"}, deps)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Uploading", "existing-app"},
+			{"unchanged", "skipping"},
+			{"OK"},
+			{"existing-app", "up to date"},
+		})
+		Expect(deps.stopper.AppToStop.Guid).To(Equal(""))
+		Expect(deps.starter.AppToStart.Guid).To(Equal(""))
+	})
+
+	It("TestPushingAppWithUnchangedBitsAndChangedMemoryRestarts", func() {
+		deps := getPushDependencies()
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app"})
+		existingApp.Memory = 256
+		deps.appRepo.ReadApp = existingApp
+		deps.appRepo.UpdateAppResult = existingApp
+		deps.appBitsRepo.BitsUnchanged = true
+
+		_ = callPush([]string{"-m", "512M", "existing-app"}, deps)
+
+		Expect(deps.stopper.AppToStop.Guid).To(Equal(existingApp.Guid))
+		Expect(deps.starter.AppToStart.Guid).To(Equal(existingApp.Guid))
+	})
+
+	It("TestPushingAppWithUnchangedBitsAndChangedInstancesDoesNotRestart", func() {
+		deps := getPushDependencies()
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app"})
+		existingApp.InstanceCount = 1
+		deps.appRepo.ReadApp = existingApp
+		deps.appRepo.UpdateAppResult = existingApp
+		deps.appBitsRepo.BitsUnchanged = true
+
+		_ = callPush([]string{"-i", "3", "existing-app"}, deps)
+
+		Expect(*deps.appRepo.UpdateParams.InstanceCount).To(Equal(3))
+		Expect(deps.stopper.AppToStop.Guid).To(Equal(""))
+		Expect(deps.starter.AppToStart.Guid).To(Equal(""))
+	})
+
+	It("TestPushingAppWithForceUpload", func() {
+		deps := getPushDependencies()
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app"})
+		deps.appRepo.ReadApp = existingApp
+		deps.appRepo.UpdateAppResult = existingApp
+		deps.appBitsRepo.BitsUnchanged = true
+
+		_ = callPush([]string{"--force-upload", "existing-app"}, deps)
+
+		Expect(deps.appBitsRepo.ForceUpload).To(BeTrue())
+		Expect(deps.starter.AppToStart.Guid).To(Equal(existingApp.Guid))
+	})
+
+	It("TestPushingAppShowsUploadProgress", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.appBitsRepo.CallbackZipSize = 1024
+
+		ui := callPush([]string{"my-new-app"}, deps)
+
+		Expect(len(ui.ProgressBars)).To(Equal(1))
+		Expect(ui.ProgressBars[0].Current).To(Equal(int64(1024)))
+		Expect(ui.ProgressBars[0].Total).To(Equal(int64(1024)))
+		Expect(ui.ProgressBars[0].Finished).To(BeTrue())
+
+		Expect(len(ui.Phases)).To(Equal(1))
+		Expect(ui.Phases[0].Message).To(Equal("Processing upload"))
+		Expect(ui.Phases[0].Stopped).To(BeTrue())
+	})
+
+	It("TestPushingNewAppWithDryRunChangesNothing", func() {
+		deps := getPushDependencies()
+		deps.routeRepo.FindByHostAndDomainErr = true
+		deps.appRepo.ReadNotFound = true
+		deps.appBitsRepo.UploadPlan = api.UploadPlan{TotalFileCount: 10, UploadFileCount: 3, UploadBytes: 2048}
+
+		ui := callPush([]string{"--dry-run", "-m", "512M", "my-new-app"}, deps)
+
+		Expect(len(deps.appRepo.CreateAppParams)).To(Equal(0))
+		Expect(deps.routeRepo.CreatedHost).To(Equal(""))
+		Expect(deps.routeRepo.BoundRouteGuid).To(Equal(""))
+		Expect(deps.appBitsRepo.UploadedAppGuid).To(Equal(""))
+		Expect(deps.starter.AppToStart.Guid).To(Equal(""))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Dry run"},
+			{"Would create app", "my-new-app", "my-org", "my-space"},
+			{"memory", "512M"},
+			{"Would create route", "my-new-app.foo.cf-app.com"},
+			{"Would bind", "my-new-app.foo.cf-app.com", "my-new-app"},
+			{"Would upload 3 of 10 files", "2K"},
+			{"Would restart", "my-new-app"},
+		})
+	})
+
+	It("TestPushingExistingAppWithDryRunShowsChanges", func() {
+		deps := getPushDependencies()
+		existingApp := maker.NewApp(maker.Overrides{"name": "app1"})
+		existingApp.State = "started"
+		existingApp.Memory = 256
+		existingApp.InstanceCount = 1
+		existingApp.EnvironmentVars = map[string]string{"SOMETHING": "nothing"}
+		existingApp.Routes = []models.RouteSummary{models.RouteSummary{}}
+		deps.appRepo.ReadApp = existingApp
+		deps.appBitsRepo.BitsUnchanged = true
+
+		boundService := maker.NewServiceInstance("app1-service")
+		boundService.ServiceBindings = []models.ServiceBindingFields{{AppGuid: existingApp.Guid}}
+		deps.serviceRepo.FindInstanceByNameMap = generic.NewMap(map[interface{}]interface{}{
+			"app1-service":   boundService,
+			"global-service": maker.NewServiceInstance("global-service"),
+		})
+
+		m := manifestWithServicesAndEnv()
+		m.Applications = m.Applications[:1]
+		instances := 3
+		m.Applications[0].InstanceCount = &instances
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+
+		ui := callPush([]string{"--dry-run"}, deps)
+
+		Expect(deps.appRepo.UpdateAppGuid).To(Equal(""))
+		Expect(len(deps.binder.AppsToBind)).To(Equal(0))
+		Expect(deps.appBitsRepo.PlannedAppGuid).To(Equal(existingApp.Guid))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Would update app", "app1"},
+			{"instances: 1 -> 3"},
+			{"env SOMETHING", "nothing", "definitely-something"},
+			{"App bits are unchanged"},
+			{"app1-service", "already bound"},
+			{"Would bind service", "global-service"},
+			{"Would restart", "app1"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"memory"},
+		})
+	})
+
+	It("TestPushingAppWithHostsAndDomainsBindsEveryCombination", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.routeRepo.RoutesByURL = map[string]models.Route{}
+		deps.domainRepo.DomainsByName = map[string]models.DomainFields{
+			"example.com":          models.DomainFields{Name: "example.com", Guid: "example-domain-guid"},
+			"internal.example.com": models.DomainFields{Name: "internal.example.com", Guid: "internal-domain-guid"},
+		}
+
+		m := singleAppManifest()
+		m.Applications[0].Host = nil
+		m.Applications[0].Domain = nil
+		m.Applications[0].Hosts = &[]string{"api", "api-v2"}
+		m.Applications[0].Domains = &[]string{"example.com", "internal.example.com"}
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+
+		ui := callPush([]string{}, deps)
+
+		Expect(deps.routeRepo.CreatedHosts).To(Equal([]string{"api", "api-v2", "api", "api-v2"}))
+		Expect(len(deps.routeRepo.BoundRouteGuids)).To(Equal(4))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Binding", "api.example.com"},
+			{"Binding", "api-v2.example.com"},
+			{"Binding", "api.internal.example.com"},
+			{"Binding", "api-v2.internal.example.com"},
+		})
+	})
+
+	It("TestPushingAppWithRoutesOnlyBindsTheMissingOnesAndCanPruneTheOthers", func() {
+		deps := getPushDependencies()
+
+		apiRoute := routeSummaryInDomain("api", "example.com", "api-route-guid")
+		oldRoute := routeSummaryInDomain("old", "example.com", "old-route-guid")
+		existingApp := maker.NewApp(maker.Overrides{"name": "manifest-app-name", "guid": "my-app-guid"})
+		existingApp.Routes = []models.RouteSummary{apiRoute, oldRoute}
+		deps.appRepo.ReadApp = existingApp
+		deps.appRepo.UpdateAppResult = existingApp
+
+		deps.domainRepo.DomainsByName = map[string]models.DomainFields{
+			"example.com": models.DomainFields{Name: "example.com", Guid: "example-domain-guid"},
+		}
+		deps.routeRepo.RoutesByURL = map[string]models.Route{
+			"api.example.com": models.Route{
+				RouteFields: models.RouteFields{Host: "api", Guid: "api-route-guid"},
+				Domain:      models.DomainFields{Name: "example.com"},
+			},
+		}
+
+		m := singleAppManifest()
+		m.Applications[0].Host = nil
+		m.Applications[0].Domain = nil
+		m.Applications[0].Routes = &[]string{"api.example.com", "example.com"}
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+
+		ui := callPush([]string{"--prune-routes"}, deps)
+
+		Expect(deps.routeRepo.CreatedHosts).To(Equal([]string{""}))
+		Expect(deps.routeRepo.BoundRouteGuids).To(Equal([]string{"-route-guid"}))
+		Expect(deps.routeRepo.UnboundRouteGuids).To(Equal([]string{"old-route-guid"}))
+		Expect(deps.routeRepo.UnboundAppGuid).To(Equal("my-app-guid"))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Using route", "api.example.com"},
+			{"Creating route", "example.com"},
+			{"Binding", "example.com", "manifest-app-name"},
+			{"Unbinding", "old.example.com", "manifest-app-name"},
+		})
+	})
+
+	It("TestPushingAppWithRoutesOnlyPrunesRoutesWhenAsked", func() {
+		deps := getPushDependencies()
+
+		oldRoute := routeSummaryInDomain("old", "example.com", "old-route-guid")
+		existingApp := maker.NewApp(maker.Overrides{"name": "manifest-app-name", "guid": "my-app-guid"})
+		existingApp.Routes = []models.RouteSummary{oldRoute}
+		deps.appRepo.ReadApp = existingApp
+		deps.appRepo.UpdateAppResult = existingApp
+
+		deps.domainRepo.DomainsByName = map[string]models.DomainFields{
+			"example.com": models.DomainFields{Name: "example.com", Guid: "example-domain-guid"},
+		}
+		deps.routeRepo.RoutesByURL = map[string]models.Route{}
+
+		m := singleAppManifest()
+		m.Applications[0].Routes = &[]string{"api.example.com"}
+		m.Applications[0].Host = nil
+		m.Applications[0].Domain = nil
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+
+		callPush([]string{}, deps)
+		Expect(deps.routeRepo.CreatedHosts).To(Equal([]string{"api"}))
+		Expect(deps.routeRepo.UnboundRouteGuids).To(BeEmpty())
+
+		ui := callPush([]string{"--dry-run", "--prune-routes"}, deps)
+		Expect(deps.routeRepo.UnboundRouteGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Would create route", "api.example.com"},
+			{"Would unbind", "old.example.com", "manifest-app-name"},
+		})
+	})
+
+	It("TestPushingAppWithoutRoutesInTheManifestWarnsThatItDoesNotPrune", func() {
+		deps := getPushDependencies()
+
+		oldRoute := routeSummaryInDomain("old", "example.com", "old-route-guid")
+		existingApp := maker.NewApp(maker.Overrides{"name": "manifest-app-name", "guid": "my-app-guid"})
+		existingApp.Routes = []models.RouteSummary{oldRoute}
+		deps.appRepo.ReadApp = existingApp
+		deps.appRepo.UpdateAppResult = existingApp
+		deps.manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
+
+		ui := callPush([]string{"--prune-routes"}, deps)
+
+		Expect(deps.routeRepo.UnboundRouteGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Not pruning the routes of manifest-app-name", "manifest-app-name has none"},
+		})
+	})
+
+	It("TestPushingAppWithARandomRoute", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.routeRepo.RoutesByURL = map[string]models.Route{
+			"my-new-app-word-1.foo.cf-app.com": models.Route{
+				RouteFields: models.RouteFields{Host: "my-new-app-word-1", Guid: "taken-route-guid"},
+			},
+		}
+
+		ui := callPush([]string{"--random-route", "my-new-app"}, deps)
+
+		Expect(deps.routeRepo.CreatedHosts).To(Equal([]string{"my-new-app-word-2"}))
+		Expect(deps.routeRepo.BoundRouteGuid).To(Equal("my-new-app-word-2-route-guid"))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Creating route", "my-new-app-word-2.foo.cf-app.com"},
+			{"Binding", "my-new-app-word-2.foo.cf-app.com", "my-new-app"},
+		})
+	})
+
+	It("TestPushingAppWithARandomRouteFromTheManifestKeepsItsRoute", func() {
+		deps := getPushDependencies()
+
+		existingRoute := routeSummaryInDomain("manifest-app-name-lucky-otter", "example.com", "existing-route-guid")
+		existingRoute.Domain.Guid = "example-domain-guid"
+		existingApp := maker.NewApp(maker.Overrides{"name": "manifest-app-name", "guid": "my-app-guid"})
+		existingApp.Routes = []models.RouteSummary{existingRoute}
+		deps.appRepo.ReadApp = existingApp
+		deps.appRepo.UpdateAppResult = existingApp
+
+		deps.domainRepo.DomainsByName = map[string]models.DomainFields{
+			"example.com": models.DomainFields{Name: "example.com", Guid: "example-domain-guid"},
+		}
+		deps.routeRepo.RoutesByURL = map[string]models.Route{
+			"manifest-app-name-lucky-otter.example.com": models.Route{
+				RouteFields: models.RouteFields{Host: "manifest-app-name-lucky-otter", Guid: "existing-route-guid"},
+				Domain:      models.DomainFields{Name: "example.com", Guid: "example-domain-guid"},
+			},
+		}
+
+		randomRoute := true
+		m := singleAppManifest()
+		m.Applications[0].Host = nil
+		m.Applications[0].Domain = nil
+		m.Applications[0].Domains = &[]string{"example.com"}
+		m.Applications[0].RandomRoute = &randomRoute
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+
+		ui := callPush([]string{}, deps)
+
+		Expect(deps.routeRepo.CreatedHosts).To(BeEmpty())
+		Expect(deps.routeRepo.BoundRouteGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Using route", "manifest-app-name-lucky-otter.example.com"},
+		})
+	})
+
+	It("TestPushingAppToARouteTakenByAnotherSpaceFails", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.routeRepo.RoutesByURL = map[string]models.Route{
+			"taken.foo.cf-app.com": models.Route{
+				RouteFields: models.RouteFields{Host: "taken", Guid: "taken-route-guid"},
+				Space:       models.SpaceFields{Name: "other-space", Guid: "other-space-guid"},
+			},
+		}
+
+		ui := callPush([]string{"-n", "taken", "my-new-app"}, deps)
+
+		Expect(deps.routeRepo.BoundRouteGuid).To(Equal(""))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"taken.foo.cf-app.com", "already taken by another space"},
+			{"TIP", "--random-route", "--avoid-taken-routes"},
+		})
+	})
+
+	It("TestPushingAppToARouteTakenByAnotherSpaceCanAddASuffix", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.routeRepo.RoutesByURL = map[string]models.Route{
+			"taken.foo.cf-app.com": models.Route{
+				RouteFields: models.RouteFields{Host: "taken", Guid: "taken-route-guid"},
+				Space:       models.SpaceFields{Name: "other-space", Guid: "other-space-guid"},
+			},
+		}
+
+		ui := callPush([]string{"-n", "taken", "--avoid-taken-routes", "my-new-app"}, deps)
+
+		Expect(deps.routeRepo.CreatedHosts).To(Equal([]string{"taken-word-1"}))
+		Expect(deps.routeRepo.BoundRouteGuid).To(Equal("taken-word-1-route-guid"))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"taken.foo.cf-app.com", "already taken by another space", "taken-word-1.foo.cf-app.com"},
+			{"Binding", "taken-word-1.foo.cf-app.com", "my-new-app"},
+		})
+	})
+
+	It("TestPushingAppToARouteInTheTargetedSpaceUsesIt", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.routeRepo.RoutesByURL = map[string]models.Route{
+			"mine.foo.cf-app.com": models.Route{
+				RouteFields: models.RouteFields{Host: "mine", Guid: "my-route-guid"},
+				Domain:      models.DomainFields{Name: "foo.cf-app.com"},
+				Space:       models.SpaceFields{Name: "my-space", Guid: "my-space-guid"},
+			},
+		}
+
+		ui := callPush([]string{"-n", "mine", "my-new-app"}, deps)
+
+		Expect(deps.routeRepo.CreatedHosts).To(BeEmpty())
+		Expect(deps.routeRepo.BoundRouteGuid).To(Equal("my-route-guid"))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Using route", "mine.foo.cf-app.com"},
+			{"Binding", "mine.foo.cf-app.com", "my-new-app"},
+		})
+	})
+
+	It("TestPushingAppWithARouteInAnUnknownDomain", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.domainRepo.DomainsByName = map[string]models.DomainFields{}
+
+		m := singleAppManifest()
+		m.Applications[0].Host = nil
+		m.Applications[0].Domain = nil
+		m.Applications[0].Routes = &[]string{"api.nowhere.com"}
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+
+		ui := callPush([]string{}, deps)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Could not find a domain for route api.nowhere.com"},
+		})
+	})
+
+	It("TestPushingAppBlueGreenSwitchesRoutesOnceNewAppIsRunning", func() {
+		deps := getPushDependencies()
+		route := models.RouteSummary{}
+		route.Guid = "my-route-guid"
+		route.Host = "existing-app"
+		route.Domain = models.DomainFields{Name: "example.com"}
+
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app", "guid": "existing-app-guid"})
+		existingApp.Memory = 256
+		existingApp.EnvironmentVars = map[string]string{"OLD": "value"}
+		deps.appRepo.ReadAppsByName = map[string]models.Application{"existing-app": existingApp}
+		deps.appSummaryRepo.GetSummarySummary.RouteSummaries = []models.RouteSummary{route}
+		deps.appSummaryRepo.GetSummarySummary.ServiceNames = []string{"my-db"}
+
+		serviceInstance := models.ServiceInstance{}
+		serviceInstance.Name = "my-db"
+		serviceInstance.Guid = "my-db-guid"
+		deps.serviceRepo.FindInstanceByNameServiceInstance = serviceInstance
+
+		ui := callPush([]string{"--strategy", "blue-green", "existing-app"}, deps)
+
+		newParams := deps.appRepo.CreatedAppParams()
+		Expect(*newParams.Name).To(Equal("existing-app-new"))
+		Expect(*newParams.Memory).To(Equal(uint64(256)))
+		Expect(*newParams.EnvironmentVars).To(Equal(map[string]string{"OLD": "value"}))
+
+		Expect(deps.appBitsRepo.UploadedAppGuid).To(Equal("existing-app-new-guid"))
+		Expect(deps.binder.AppsToBind[0].Guid).To(Equal("existing-app-new-guid"))
+		Expect(deps.binder.InstancesToBindTo[0].Guid).To(Equal("my-db-guid"))
+		Expect(deps.starter.StartedAppNames).To(Equal([]string{"existing-app-new"}))
+		Expect(deps.starter.InstancesThreshold.All).To(BeTrue())
+		Expect(deps.stopper.AppToStop.Guid).To(Equal(""))
+
+		Expect(deps.routeRepo.BoundRouteGuid).To(Equal("my-route-guid"))
+		Expect(deps.routeRepo.BoundAppGuid).To(Equal("existing-app-new-guid"))
+		Expect(deps.routeRepo.UnboundRouteGuid).To(Equal("my-route-guid"))
+		Expect(deps.routeRepo.UnboundAppGuid).To(Equal("existing-app-guid"))
+
+		Expect(deps.appRepo.UpdatedAppGuids).To(Equal([]string{"existing-app-guid", "existing-app-new-guid"}))
+		Expect(*deps.appRepo.UpdatedParams[0].Name).To(Equal("existing-app-old"))
+		Expect(*deps.appRepo.UpdatedParams[1].Name).To(Equal("existing-app"))
+		Expect(deps.appRepo.DeletedAppGuid).To(Equal("existing-app-guid"))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Pushing the new version", "existing-app-new"},
+			{"Creating app", "existing-app-new"},
+			{"Uploading existing-app-new"},
+			{"Binding", "existing-app.example.com", "existing-app-new"},
+			{"Unmapping"},
+			{"Renaming existing-app to existing-app-old"},
+			{"Renaming existing-app-new to existing-app"},
+			{"Deleting the old version"},
+			{"replaced without downtime"},
+		})
+	})
+
+	It("TestPushingAppBlueGreenRollsBackWhenNewAppFailsToStart", func() {
+		deps := getPushDependencies()
+		route := models.RouteSummary{}
+		route.Guid = "my-route-guid"
+
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app", "guid": "existing-app-guid"})
+		deps.appRepo.ReadAppsByName = map[string]models.Application{"existing-app": existingApp}
+		deps.appSummaryRepo.GetSummarySummary.RouteSummaries = []models.RouteSummary{route}
+		deps.starter.StartError = errors.New("existing-app-new is crashing")
+
+		ui := callPush([]string{"--strategy", "blue-green", "--wait-for", "2", "existing-app"}, deps)
+
+		Expect(deps.starter.InstancesThreshold.Count).To(Equal(2))
+		Expect(deps.appRepo.DeletedAppGuid).To(Equal("existing-app-new-guid"))
+		Expect(deps.routeRepo.BoundRouteGuid).To(Equal(""))
+		Expect(deps.routeRepo.UnboundRouteGuid).To(Equal(""))
+		Expect(deps.appRepo.UpdatedAppGuids).To(BeEmpty())
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"rolling back"},
+			{"Deleting existing-app-new"},
+			{"FAILED"},
+			{"existing-app-new is crashing"},
+			{"existing-app was left unchanged"},
+		})
+	})
+
+	It("TestPushingAppBlueGreenRollsBackWhenAServiceCannotBeBound", func() {
+		deps := getPushDependencies()
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app", "guid": "existing-app-guid"})
+		deps.appRepo.ReadAppsByName = map[string]models.Application{"existing-app": existingApp}
+		deps.appSummaryRepo.GetSummarySummary.ServiceNames = []string{"my-db"}
+		deps.serviceRepo.FindInstanceByNameNotFound = true
+
+		ui := callPush([]string{"--strategy", "blue-green", "existing-app"}, deps)
+
+		Expect(deps.appRepo.DeletedAppGuids).To(Equal([]string{"existing-app-new-guid"}))
+		Expect(deps.appRepo.UpdatedAppGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"rolling back"},
+			{"Deleting existing-app-new"},
+			{"FAILED"},
+			{"Could not find service my-db"},
+			{"existing-app was left unchanged"},
+		})
+	})
+
+	It("TestPushingAppBlueGreenDeletesANewVersionLeftOverByAnEarlierPush", func() {
+		deps := getPushDependencies()
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app", "guid": "existing-app-guid"})
+		leftoverApp := maker.NewApp(maker.Overrides{"name": "existing-app-new", "guid": "leftover-guid"})
+		deps.appRepo.ReadAppsByName = map[string]models.Application{
+			"existing-app":     existingApp,
+			"existing-app-new": leftoverApp,
+		}
+
+		ui := callPush([]string{"--strategy", "blue-green", "existing-app"}, deps)
+
+		Expect(deps.appRepo.DeletedAppGuids).To(Equal([]string{"leftover-guid", "existing-app-guid"}))
+		Expect(*deps.appRepo.CreatedAppParams().Name).To(Equal("existing-app-new"))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"existing-app-new was left over from an earlier push"},
+			{"Deleting existing-app-new"},
+			{"Creating app", "existing-app-new"},
+			{"replaced without downtime"},
+		})
+	})
+
+	It("TestPushingAppBlueGreenChecksTheHealthOfTheNewVersionOnATemporaryRoute", func() {
+		deps := getPushDependencies()
+		route := models.RouteSummary{}
+		route.Guid = "my-route-guid"
+		route.Host = "existing-app"
+		route.Domain = models.DomainFields{Name: "example.com", Guid: "example-domain-guid"}
+
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app", "guid": "existing-app-guid"})
+		deps.appRepo.ReadAppsByName = map[string]models.Application{"existing-app": existingApp}
+		deps.appSummaryRepo.GetSummarySummary.RouteSummaries = []models.RouteSummary{route}
+		deps.routeRepo.FindByHostAndDomainNotFound = true
+
+		ui := callPush([]string{"--strategy", "blue-green", "--health-check-path", "/health", "existing-app"}, deps)
+
+		Expect(deps.starter.HealthCheck.Path).To(Equal("/health"))
+		Expect(deps.starter.StartedAppNames).To(Equal([]string{"existing-app-new"}))
+		Expect(deps.routeRepo.CreatedHosts).To(Equal([]string{"existing-app-new"}))
+		Expect(deps.routeRepo.CreatedDomainGuid).To(Equal("example-domain-guid"))
+		Expect(deps.routeRepo.BoundRouteGuids).To(Equal([]string{"existing-app-new-route-guid", "my-route-guid"}))
+		Expect(deps.routeRepo.DeleteRouteGuid).To(Equal("existing-app-new-route-guid"))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Creating route", "existing-app-new.example.com"},
+			{"Binding", "existing-app-new.example.com", "to check its health"},
+			{"Removing the temporary route", "existing-app-new.example.com"},
+			{"Binding", "existing-app.example.com", "existing-app-new"},
+			{"replaced without downtime"},
+		})
+	})
+
+	It("TestPushingAppBlueGreenRollsBackWhenTheHealthCheckFails", func() {
+		deps := getPushDependencies()
+		route := models.RouteSummary{}
+		route.Guid = "my-route-guid"
+		route.Host = "existing-app"
+		route.Domain = models.DomainFields{Name: "example.com", Guid: "example-domain-guid"}
+
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app", "guid": "existing-app-guid"})
+		deps.appRepo.ReadAppsByName = map[string]models.Application{"existing-app": existingApp}
+		deps.appSummaryRepo.GetSummarySummary.RouteSummaries = []models.RouteSummary{route}
+		deps.routeRepo.FindByHostAndDomainNotFound = true
+		deps.starter.StartError = errors.New("Health check of http://existing-app-new.example.com/health failed")
+
+		ui := callPush([]string{"--strategy", "blue-green", "--health-check-path", "/health", "existing-app"}, deps)
+
+		Expect(deps.routeRepo.BoundRouteGuids).To(Equal([]string{"existing-app-new-route-guid"}))
+		Expect(deps.routeRepo.DeleteRouteGuid).To(Equal("existing-app-new-route-guid"))
+		Expect(deps.appRepo.DeletedAppGuids).To(Equal([]string{"existing-app-new-guid"}))
+		Expect(deps.appRepo.UpdatedAppGuids).To(BeEmpty())
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Removing the temporary route"},
+			{"rolling back"},
+			{"FAILED"},
+			{"Health check of", "failed"},
+			{"existing-app was left unchanged"},
+		})
+	})
+
+	It("TestPushingNewAppBlueGreenDoesARegularPush", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+
+		ui := callPush([]string{"--strategy", "blue-green", "my-new-app"}, deps)
+
+		Expect(*deps.appRepo.CreatedAppParams().Name).To(Equal("my-new-app"))
+		Expect(deps.starter.AppToStart.Guid).To(Equal("my-new-app-guid"))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"my-new-app", "does not exist yet"},
+		})
+	})
+
+	It("TestPushingWithAnUnknownStrategyFails", func() {
+		deps := getPushDependencies()
+
+		ui := callPush([]string{"--strategy", "yolo", "my-app"}, deps)
+
+		Expect(len(deps.appRepo.CreateAppParams)).To(Equal(0))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid push strategy", "yolo"},
+		})
+	})
+
+	It("TestPushingInParallelFollowsDependsOnAndShowsSummary", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.manifestRepo.ReadManifestReturns.Manifest = manifestWithDependencies()
+
+		ui := callPush([]string{"--parallel", "1"}, deps)
+
+		Expect(len(deps.appRepo.CreateAppParams)).To(Equal(2))
+		Expect(*deps.appRepo.CreateAppParams[0].Name).To(Equal("api"))
+		Expect(*deps.appRepo.CreateAppParams[1].Name).To(Equal("worker"))
+		Expect(deps.starter.StartedAppNames).To(Equal([]string{"api", "worker"}))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"[api]", "Creating app", "api"},
+			{"[worker]", "Creating app", "worker"},
+			{"app", "result"},
+			{"api", "pushed"},
+			{"worker", "pushed"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+		})
+	})
+
+	It("TestPushingInParallelStartsAppsWithTheConfiguredStarter", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.manifestRepo.ReadManifestReturns.Manifest = manifestWithDependencies()
+
+		callPush([]string{"--parallel", "2", "--wait-for", "all", "--health-check-path", "/health"}, deps)
+
+		Expect(deps.starter.StartedAppNames).To(Equal([]string{"api", "worker"}))
+		Expect(deps.starter.InstancesThreshold.All).To(BeTrue())
+		Expect(deps.starter.HealthCheck.Path).To(Equal("/health"))
+	})
+
+	It("TestPushingKeepsTheStartTimeoutApartFromTheHealthCheckTimeout", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+
+		callPush([]string{"-t", "111", "--health-check-path", "/health", "--health-check-timeout", "5", "my-new-app"}, deps)
+
+		Expect(deps.starter.Timeout).To(Equal(111))
+		Expect(deps.starter.HealthCheck.Timeout).To(Equal(5 * time.Second))
+	})
+
+	It("TestPushingInParallelSkipsAppsWhoseDependencyFailed", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.appBitsRepo.UploadAppErr = true
+		deps.manifestRepo.ReadManifestReturns.Manifest = manifestWithDependencies()
+
+		ui := callPush([]string{"--parallel", "2"}, deps)
+
+		Expect(len(deps.appRepo.CreateAppParams)).To(Equal(1))
+		Expect(deps.appBitsRepo.UploadedAppGuid).To(Equal("api-guid"))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"[api]", "FAILED"},
+			{"[api]", "Error uploading application"},
+			{"api", "failed", "Error uploading application"},
+			{"worker", "skipped", "api was not pushed"},
+			{"FAILED"},
+			{"2 of 2 apps were not pushed"},
+		})
+	})
+
+	It("TestPushingInParallelCollectsStartFailuresInTheSummary", func() {
+		deps := getPushDependencies()
+		deps.appRepo.ReadNotFound = true
+		deps.starter.StartError = errors.New("Start unsuccessful")
+		deps.manifestRepo.ReadManifestReturns.Manifest = manifestWithDependencies()
+
+		ui := callPush([]string{"--parallel", "2"}, deps)
+
+		Expect(deps.starter.StartedAppNames).To(Equal([]string{"api"}))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"[api]", "FAILED"},
+			{"[api]", "Start unsuccessful"},
+			{"api", "failed", "Start unsuccessful"},
+			{"worker", "skipped", "api was not pushed"},
+			{"FAILED"},
+			{"2 of 2 apps were not pushed"},
+		})
+	})
+
+	It("TestPushingInParallelPushesIndependentAppsConcurrently", func() {
+		deps := getPushDependencies()
+		existingApp := maker.NewApp(maker.Overrides{"name": "existing-app"})
+		existingApp.State = "started"
+		existingApp.Routes = []models.RouteSummary{models.RouteSummary{}}
+		deps.appRepo.ReadApp = existingApp
+		deps.appRepo.UpdateAppResult = existingApp
+		deps.appBitsRepo.BitsUnchanged = true
+
+		m := manifestWithDependencies()
+		m.Applications[0].DependsOn = nil
+		name := "admin"
+		m.Applications = append(m.Applications, models.AppParams{Name: &name})
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+
+		ui := callPush([]string{"--parallel", "3"}, deps)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"worker", "up to date"},
+			{"api", "up to date"},
+			{"admin", "up to date"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+		})
+	})
+
+	It("TestPushingAppsWithCircularDependenciesFails", func() {
+		deps := getPushDependencies()
+		m := manifestWithDependencies()
+		m.Applications[1].DependsOn = &[]string{"worker"}
+		deps.manifestRepo.ReadManifestReturns.Manifest = m
+
+		ui := callPush([]string{}, deps)
+
+		Expect(len(deps.appRepo.CreateAppParams)).To(Equal(0))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Circular depends_on", "worker -> api -> worker"},
+		})
+	})
+
 	It("TestPushingAppWhenItAlreadyExistsAndChangingOptions", func() {
 		deps := getPushDependencies()
 
@@ -1034,6 +2011,27 @@ func singleAppManifest() *manifest.Manifest {
 	}
 }
 
+func manifestWithDeclaredServices() *manifest.Manifest {
+	m := singleAppManifest()
+	m.Applications[0].Services = &[]string{"db", "creds"}
+	m.Applications[0].ServiceInstances = &[]models.ServiceInstanceParams{
+		{Name: "db", Label: "postgres", Plan: "small"},
+		{Name: "creds", Credentials: map[string]string{"username": "admin"}},
+	}
+	return m
+}
+
+func postgresServiceOfferings() []models.ServiceOffering {
+	offering := models.ServiceOffering{}
+	offering.Label = "postgres"
+	offering.Provider = "core"
+	offering.Plans = []models.ServicePlanFields{
+		{Name: "large", Guid: "large-plan-guid"},
+		{Name: "small", Guid: "small-plan-guid"},
+	}
+	return []models.ServiceOffering{offering}
+}
+
 func manifestWithServicesAndEnv() *manifest.Manifest {
 	name1 := "app1"
 	name2 := "app2"
@@ -1057,17 +2055,43 @@ func manifestWithServicesAndEnv() *manifest.Manifest {
 	}
 }
 
+func manifestWithDependencies() *manifest.Manifest {
+	worker := "worker"
+	api := "api"
+	return &manifest.Manifest{
+		Applications: []models.AppParams{
+			models.AppParams{
+				Name:      &worker,
+				DependsOn: &[]string{"api"},
+			},
+			models.AppParams{
+				Name: &api,
+			},
+		},
+	}
+}
+
 type pushDependencies struct {
-	manifestRepo *testmanifest.FakeManifestRepository
-	starter      *testcmd.FakeAppStarter
-	stopper      *testcmd.FakeAppStopper
-	binder       *testcmd.FakeAppBinder
-	appRepo      *testapi.FakeApplicationRepository
-	domainRepo   *testapi.FakeDomainRepository
-	routeRepo    *testapi.FakeRouteRepository
-	stackRepo    *testapi.FakeStackRepository
-	appBitsRepo  *testapi.FakeApplicationBitsRepository
-	serviceRepo  *testapi.FakeServiceRepo
+	manifestRepo     *testmanifest.FakeManifestRepository
+	starter          *testcmd.FakeAppStarter
+	stopper          *testcmd.FakeAppStopper
+	binder           *testcmd.FakeAppBinder
+	appRepo          *testapi.FakeApplicationRepository
+	domainRepo       *testapi.FakeDomainRepository
+	routeRepo        *testapi.FakeRouteRepository
+	stackRepo        *testapi.FakeStackRepository
+	appBitsRepo      *testapi.FakeApplicationBitsRepository
+	serviceRepo      *testapi.FakeServiceRepo
+	appInstancesRepo *testapi.FakeAppInstancesRepo
+	userProvidedRepo *testapi.FakeUserProvidedServiceInstanceRepo
+	appSummaryRepo   *testapi.FakeAppSummaryRepo
+}
+
+func routeSummaryInDomain(host, domain, guid string) (route models.RouteSummary) {
+	route.Host = host
+	route.Guid = guid
+	route.Domain.Name = domain
+	return
 }
 
 func getPushDependencies() (deps pushDependencies) {
@@ -1085,6 +2109,9 @@ func getPushDependencies() (deps pushDependencies) {
 	deps.stackRepo = &testapi.FakeStackRepository{}
 	deps.appBitsRepo = &testapi.FakeApplicationBitsRepository{}
 	deps.serviceRepo = &testapi.FakeServiceRepo{}
+	deps.appInstancesRepo = &testapi.FakeAppInstancesRepo{}
+	deps.userProvidedRepo = &testapi.FakeUserProvidedServiceInstanceRepo{}
+	deps.appSummaryRepo = &testapi.FakeAppSummaryRepo{}
 
 	return
 }
@@ -1097,7 +2124,14 @@ func callPush(args []string, deps pushDependencies) (ui *testterm.FakeUI) {
 
 	cmd := NewPush(ui, configRepo, deps.manifestRepo, deps.starter,
 		deps.stopper, deps.binder, deps.appRepo, deps.domainRepo,
-		deps.routeRepo, deps.stackRepo, deps.serviceRepo, deps.appBitsRepo)
+		deps.routeRepo, deps.stackRepo, deps.serviceRepo, deps.appBitsRepo, deps.appInstancesRepo, deps.userProvidedRepo, deps.appSummaryRepo)
+	cmd.PingerThrottle = 0
+
+	wordCount := 0
+	cmd.RandomWord = func() string {
+		wordCount++
+		return fmt.Sprintf("word-%d", wordCount)
+	}
 
 	reqFactory := &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
 	testcmd.RunCommand(cmd, ctxt, reqFactory)
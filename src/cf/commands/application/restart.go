@@ -1,29 +1,41 @@
 package application
 
 import (
+	"cf/api"
+	"cf/configuration"
 	"cf/models"
 	"cf/requirements"
 	"cf/terminal"
 	"errors"
 	"github.com/codegangsta/cli"
+	"time"
 )
 
 type Restart struct {
-	ui      terminal.UI
-	starter ApplicationStarter
-	stopper ApplicationStopper
-	appReq  requirements.ApplicationRequirement
+	ui           terminal.UI
+	config       configuration.Reader
+	starter      ApplicationStarter
+	stopper      ApplicationStopper
+	appInstances api.AppInstancesRepository
+	appReq       requirements.ApplicationRequirement
+
+	StartupTimeout time.Duration
+	PingerThrottle time.Duration
 }
 
 type ApplicationRestarter interface {
 	ApplicationRestart(app models.Application)
 }
 
-func NewRestart(ui terminal.UI, starter ApplicationStarter, stopper ApplicationStopper) (cmd *Restart) {
+func NewRestart(ui terminal.UI, config configuration.Reader, starter ApplicationStarter, stopper ApplicationStopper, appInstances api.AppInstancesRepository) (cmd *Restart) {
 	cmd = new(Restart)
 	cmd.ui = ui
+	cmd.config = config
 	cmd.starter = starter
 	cmd.stopper = stopper
+	cmd.appInstances = appInstances
+	cmd.StartupTimeout = DefaultStartupTimeout
+	cmd.PingerThrottle = DefaultPingerThrottle
 	return
 }
 
@@ -46,6 +58,16 @@ func (cmd *Restart) GetRequirements(reqFactory requirements.Factory, c *cli.Cont
 
 func (cmd *Restart) Run(c *cli.Context) {
 	app := cmd.appReq.GetApplication()
+
+	if c.Bool("rolling") {
+		batchSize := c.Int("batch")
+		if batchSize < 1 {
+			batchSize = 1
+		}
+		cmd.rollingRestart(app, batchSize)
+		return
+	}
+
 	cmd.ApplicationRestart(app)
 }
 
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"cf/api"
+	"cf/configuration"
+	"cf/requirements"
+	"cf/terminal"
+	"errors"
+	"github.com/codegangsta/cli"
+	"strconv"
+	"time"
+)
+
+type RestartAppInstance struct {
+	ui           terminal.UI
+	config       configuration.Reader
+	appInstances api.AppInstancesRepository
+	appReq       requirements.ApplicationRequirement
+	index        int
+
+	StartupTimeout time.Duration
+	PingerThrottle time.Duration
+}
+
+func NewRestartAppInstance(ui terminal.UI, config configuration.Reader, appInstances api.AppInstancesRepository) (cmd *RestartAppInstance) {
+	cmd = new(RestartAppInstance)
+	cmd.ui = ui
+	cmd.config = config
+	cmd.appInstances = appInstances
+	cmd.StartupTimeout = DefaultStartupTimeout
+	cmd.PingerThrottle = DefaultPingerThrottle
+	return
+}
+
+func (cmd *RestartAppInstance) GetRequirements(reqFactory requirements.Factory, c *cli.Context) (reqs []requirements.Requirement, err error) {
+	if len(c.Args()) != 2 {
+		err = errors.New("Incorrect Usage")
+		cmd.ui.FailWithUsage(c, "restart-app-instance")
+		return
+	}
+
+	cmd.index, err = strconv.Atoi(c.Args()[1])
+	if err != nil || cmd.index < 0 {
+		err = errors.New("Incorrect Usage")
+		cmd.ui.FailWithUsage(c, "restart-app-instance")
+		return
+	}
+
+	cmd.appReq = reqFactory.NewApplicationRequirement(c.Args()[0])
+	reqs = []requirements.Requirement{
+		reqFactory.NewLoginRequirement(),
+		reqFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+	return
+}
+
+func (cmd *RestartAppInstance) Run(c *cli.Context) {
+	app := cmd.appReq.GetApplication()
+
+	if cmd.index >= app.InstanceCount {
+		cmd.ui.Failed("Instance #%d does not exist, app %s has %d instances", cmd.index, app.Name, app.InstanceCount)
+		return
+	}
+
+	cmd.ui.Say("Restarting instance #%d of app %s in org %s / space %s as %s...",
+		cmd.index,
+		terminal.EntityNameColor(app.Name),
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+	)
+
+	before, apiResponse := cmd.appInstances.GetInstances(app.Guid)
+	if apiResponse.IsNotSuccessful() {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	apiResponse = cmd.appInstances.DeleteInstance(app.Guid, cmd.index)
+	if apiResponse.IsNotSuccessful() {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	instances, ok := waitForReplacedInstances(cmd.ui, cmd.appInstances, app, before, cmd.index, cmd.index+1, cmd.StartupTimeout, cmd.PingerThrottle)
+	if !ok {
+		return
+	}
+
+	cmd.ui.Ok()
+	instance := instances[cmd.index]
+	cmd.ui.Say("Instance #%d is %s since %s",
+		cmd.index,
+		terminal.EntityNameColor(string(instance.State)),
+		instance.Since.Format("2006-01-02 03:04:05 PM"),
+	)
+}
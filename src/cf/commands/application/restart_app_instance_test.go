@@ -0,0 +1,101 @@
+package application_test
+
+import (
+	. "cf/commands/application"
+	"cf/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	testapi "testhelpers/api"
+	testassert "testhelpers/assert"
+	testcmd "testhelpers/commands"
+	testconfig "testhelpers/configuration"
+	testreq "testhelpers/requirements"
+	testterm "testhelpers/terminal"
+	"time"
+)
+
+func callRestartAppInstance(args []string, reqFactory *testreq.FakeReqFactory, appInstancesRepo *testapi.FakeAppInstancesRepo) (ui *testterm.FakeUI) {
+	ui = new(testterm.FakeUI)
+	ctxt := testcmd.NewContext("restart-app-instance", args)
+
+	cmd := NewRestartAppInstance(ui, testconfig.NewRepositoryWithDefaults(), appInstancesRepo)
+	cmd.PingerThrottle = 0
+	testcmd.RunCommand(cmd, ctxt, reqFactory)
+	return
+}
+
+var _ = Describe("restart-app-instance command", func() {
+	var (
+		reqFactory       *testreq.FakeReqFactory
+		appInstancesRepo *testapi.FakeAppInstancesRepo
+	)
+
+	BeforeEach(func() {
+		reqFactory = &testreq.FakeReqFactory{Application: startedAppWithInstances(2), LoginSuccess: true, TargetedSpaceSuccess: true}
+		appInstancesRepo = &testapi.FakeAppInstancesRepo{}
+	})
+
+	It("fails with usage when not given an app and an index", func() {
+		ui := callRestartAppInstance([]string{"my-app"}, reqFactory, appInstancesRepo)
+		Expect(ui.FailedWithUsage).To(BeTrue())
+
+		ui = callRestartAppInstance([]string{"my-app", "first"}, reqFactory, appInstancesRepo)
+		Expect(ui.FailedWithUsage).To(BeTrue())
+
+		ui = callRestartAppInstance([]string{"my-app", "-1"}, reqFactory, appInstancesRepo)
+		Expect(ui.FailedWithUsage).To(BeTrue())
+	})
+
+	It("requires a login, a targeted space and the app", func() {
+		reqFactory.LoginSuccess = false
+		callRestartAppInstance([]string{"my-app", "1"}, reqFactory, appInstancesRepo)
+		Expect(testcmd.CommandDidPassRequirements).To(BeFalse())
+		Expect(reqFactory.ApplicationName).To(Equal("my-app"))
+	})
+
+	It("restarts the instance and waits for its replacement", func() {
+		before := time.Unix(1000, 0)
+		after := time.Date(2014, time.March, 1, 13, 30, 0, 0, time.Local)
+		appInstancesRepo.GetInstancesResponses = [][]models.AppInstanceFields{
+			instancesSince(before, models.InstanceRunning, models.InstanceRunning),
+			instancesSince(before, models.InstanceRunning, models.InstanceRunning),
+			{{State: models.InstanceRunning, Since: before}, {State: models.InstanceStarting, Since: after}},
+			{{State: models.InstanceRunning, Since: before}, {State: models.InstanceRunning, Since: after}},
+		}
+
+		ui := callRestartAppInstance([]string{"my-app", "1"}, reqFactory, appInstancesRepo)
+
+		Expect(appInstancesRepo.DeletedInstanceAppGuid).To(Equal("my-app-guid"))
+		Expect(appInstancesRepo.DeletedInstanceIndexes).To(Equal([]int{1}))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Restarting instance #1 of app", "my-app", "my-org", "my-space", "my-user"},
+			{"OK"},
+			{"Instance #1 is running since 2014-03-01 01:30:00 PM"},
+		})
+	})
+
+	It("fails when the replacement is crashing", func() {
+		before := time.Unix(1000, 0)
+		appInstancesRepo.GetInstancesResponses = [][]models.AppInstanceFields{
+			instancesSince(before, models.InstanceRunning, models.InstanceRunning),
+			instancesSince(before, models.InstanceFlapping, models.InstanceRunning),
+		}
+
+		ui := callRestartAppInstance([]string{"my-app", "0"}, reqFactory, appInstancesRepo)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Instance #0 of my-app is crashing"},
+		})
+	})
+
+	It("fails when the app has no instance with the index", func() {
+		ui := callRestartAppInstance([]string{"my-app", "2"}, reqFactory, appInstancesRepo)
+
+		Expect(appInstancesRepo.DeletedInstanceIndexes).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Instance #2 does not exist", "my-app", "2 instances"},
+		})
+	})
+})
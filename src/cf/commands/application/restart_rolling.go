@@ -0,0 +1,104 @@
+package application
+
+import (
+	"cf"
+	"cf/api"
+	"cf/models"
+	"cf/net"
+	"cf/terminal"
+	"fmt"
+	"time"
+)
+
+// rollingRestart replaces the instances of an app a batch at a time, so that
+// the others keep serving requests. It waits for the new instances of a
+// batch to run before moving on to the next one.
+func (cmd *Restart) rollingRestart(app models.Application, batchSize int) {
+	if app.State != "started" {
+		cmd.ui.Failed("App %s is not started, restart it without --rolling", app.Name)
+		return
+	}
+
+	cmd.ui.Say("Restarting app %s in org %s / space %s as %s, %d instances at a time...",
+		terminal.EntityNameColor(app.Name),
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+		batchSize,
+	)
+
+	for first := 0; first < app.InstanceCount; first += batchSize {
+		last := first + batchSize
+		if last > app.InstanceCount {
+			last = app.InstanceCount
+		}
+
+		before, apiResponse := cmd.appInstances.GetInstances(app.Guid)
+		if apiResponse.IsNotSuccessful() {
+			cmd.ui.Failed(apiResponse.Message)
+			return
+		}
+
+		for index := first; index < last; index++ {
+			cmd.ui.Say("Restarting instance #%d...", index)
+			apiResponse = cmd.appInstances.DeleteInstance(app.Guid, index)
+			if apiResponse.IsNotSuccessful() {
+				cmd.ui.Failed(apiResponse.Message)
+				return
+			}
+		}
+
+		_, ok := waitForReplacedInstances(cmd.ui, cmd.appInstances, app, before, first, last, cmd.StartupTimeout, cmd.PingerThrottle)
+		if !ok {
+			return
+		}
+		cmd.ui.Ok()
+	}
+
+	cmd.ui.Say("\nApp %s restarted", terminal.EntityNameColor(app.Name))
+}
+
+// waitForReplacedInstances waits until the instances from first to last run
+// again, and returns the instances it saw last. An instance is only counted
+// once it has been replaced, which shows in the time it has been running since.
+func waitForReplacedInstances(ui terminal.UI, appInstances api.AppInstancesRepository, app models.Application,
+	before []models.AppInstanceFields, first, last int, timeout, throttle time.Duration) (instances []models.AppInstanceFields, ok bool) {
+	startTime := time.Now()
+
+	for {
+		if time.Since(startTime) > timeout {
+			ui.Failed("Timed out waiting for instance #%d to #%d of %s to start\n\nTIP: use '%s' for more information",
+				first, last-1, app.Name, terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name)))
+			return
+		}
+
+		ui.Wait(throttle)
+		var apiResponse net.ApiResponse
+		instances, apiResponse = appInstances.GetInstances(app.Guid)
+		if apiResponse.IsNotSuccessful() {
+			continue
+		}
+
+		runningCount := 0
+		for index := first; index < last && index < len(instances); index++ {
+			instance := instances[index]
+			switch {
+			case instance.State == models.InstanceFlapping:
+				ui.Failed("Instance #%d of %s is crashing\n\nTIP: use '%s' for more information",
+					index, app.Name, terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name)))
+				return
+			case instance.State == models.InstanceRunning && instanceWasReplaced(before, index, instance):
+				runningCount++
+			}
+		}
+
+		if runningCount == last-first {
+			ok = true
+			return
+		}
+	}
+}
+
+func instanceWasReplaced(before []models.AppInstanceFields, index int, instance models.AppInstanceFields) bool {
+	return index >= len(before) || !instance.Since.Equal(before[index].Since)
+}
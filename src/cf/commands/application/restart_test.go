@@ -5,20 +5,44 @@ import (
 	"cf/models"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	testapi "testhelpers/api"
+	testassert "testhelpers/assert"
 	testcmd "testhelpers/commands"
+	testconfig "testhelpers/configuration"
 	testreq "testhelpers/requirements"
 	testterm "testhelpers/terminal"
+	"time"
 )
 
 func callRestart(args []string, reqFactory *testreq.FakeReqFactory, starter ApplicationStarter, stopper ApplicationStopper) (ui *testterm.FakeUI) {
+	return callRollingRestart(args, reqFactory, starter, stopper, &testapi.FakeAppInstancesRepo{})
+}
+
+func callRollingRestart(args []string, reqFactory *testreq.FakeReqFactory, starter ApplicationStarter, stopper ApplicationStopper, appInstancesRepo *testapi.FakeAppInstancesRepo) (ui *testterm.FakeUI) {
 	ui = new(testterm.FakeUI)
 	ctxt := testcmd.NewContext("restart", args)
 
-	cmd := NewRestart(ui, starter, stopper)
+	cmd := NewRestart(ui, testconfig.NewRepositoryWithDefaults(), starter, stopper, appInstancesRepo)
+	cmd.PingerThrottle = 0
 	testcmd.RunCommand(cmd, ctxt, reqFactory)
 	return
 }
 
+func instancesSince(since time.Time, states ...models.InstanceState) (instances []models.AppInstanceFields) {
+	for _, state := range states {
+		instances = append(instances, models.AppInstanceFields{State: state, Since: since})
+	}
+	return
+}
+
+func startedAppWithInstances(count int) (app models.Application) {
+	app.Name = "my-app"
+	app.Guid = "my-app-guid"
+	app.State = "started"
+	app.InstanceCount = count
+	return
+}
+
 var _ = Describe("Testing with ginkgo", func() {
 	It("TestRestartCommandFailsWithUsage", func() {
 		reqFactory := &testreq.FakeReqFactory{}
@@ -63,4 +87,87 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(stopper.AppToStop).To(Equal(app))
 		Expect(starter.AppToStart).To(Equal(app))
 	})
+
+	It("TestRollingRestartReplacesInstancesABatchAtATime", func() {
+		app := startedAppWithInstances(3)
+		reqFactory := &testreq.FakeReqFactory{Application: app, LoginSuccess: true, TargetedSpaceSuccess: true}
+		starter := &testcmd.FakeAppStarter{}
+		stopper := &testcmd.FakeAppStopper{}
+
+		before := time.Unix(1000, 0)
+		after := time.Unix(2000, 0)
+		appInstancesRepo := &testapi.FakeAppInstancesRepo{}
+		appInstancesRepo.GetInstancesResponses = [][]models.AppInstanceFields{
+			instancesSince(before, models.InstanceRunning, models.InstanceRunning, models.InstanceRunning),
+			instancesSince(before, models.InstanceRunning, models.InstanceRunning, models.InstanceRunning),
+			{
+				{State: models.InstanceRunning, Since: after},
+				{State: models.InstanceStarting, Since: after},
+				{State: models.InstanceRunning, Since: before},
+			},
+			{
+				{State: models.InstanceRunning, Since: after},
+				{State: models.InstanceRunning, Since: after},
+				{State: models.InstanceRunning, Since: before},
+			},
+			{
+				{State: models.InstanceRunning, Since: after},
+				{State: models.InstanceRunning, Since: after},
+				{State: models.InstanceRunning, Since: before},
+			},
+			instancesSince(after, models.InstanceRunning, models.InstanceRunning, models.InstanceRunning),
+		}
+
+		ui := callRollingRestart([]string{"--rolling", "--batch", "2", "my-app"}, reqFactory, starter, stopper, appInstancesRepo)
+
+		Expect(stopper.AppToStop.Guid).To(Equal(""))
+		Expect(appInstancesRepo.DeletedInstanceAppGuid).To(Equal("my-app-guid"))
+		Expect(appInstancesRepo.DeletedInstanceIndexes).To(Equal([]int{0, 1, 2}))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Restarting app", "my-app", "my-org", "my-space", "my-user", "2 instances at a time"},
+			{"Restarting instance #0"},
+			{"Restarting instance #1"},
+			{"OK"},
+			{"Restarting instance #2"},
+			{"OK"},
+			{"App", "my-app", "restarted"},
+		})
+	})
+
+	It("TestRollingRestartStopsWhenAnInstanceIsCrashing", func() {
+		app := startedAppWithInstances(2)
+		reqFactory := &testreq.FakeReqFactory{Application: app, LoginSuccess: true, TargetedSpaceSuccess: true}
+
+		before := time.Unix(1000, 0)
+		appInstancesRepo := &testapi.FakeAppInstancesRepo{}
+		appInstancesRepo.GetInstancesResponses = [][]models.AppInstanceFields{
+			instancesSince(before, models.InstanceRunning, models.InstanceRunning),
+			instancesSince(before, models.InstanceStarting, models.InstanceRunning),
+			instancesSince(before, models.InstanceFlapping, models.InstanceRunning),
+		}
+
+		ui := callRollingRestart([]string{"--rolling", "my-app"}, reqFactory, &testcmd.FakeAppStarter{}, &testcmd.FakeAppStopper{}, appInstancesRepo)
+
+		Expect(appInstancesRepo.DeletedInstanceIndexes).To(Equal([]int{0}))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Restarting instance #0"},
+			{"FAILED"},
+			{"Instance #0 of my-app is crashing"},
+		})
+	})
+
+	It("TestRollingRestartOfAStoppedApp", func() {
+		app := startedAppWithInstances(2)
+		app.State = "stopped"
+		reqFactory := &testreq.FakeReqFactory{Application: app, LoginSuccess: true, TargetedSpaceSuccess: true}
+		appInstancesRepo := &testapi.FakeAppInstancesRepo{}
+
+		ui := callRollingRestart([]string{"--rolling", "my-app"}, reqFactory, &testcmd.FakeAppStarter{}, &testcmd.FakeAppStopper{}, appInstancesRepo)
+
+		Expect(appInstancesRepo.DeletedInstanceIndexes).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"App my-app is not started"},
+		})
+	})
 })
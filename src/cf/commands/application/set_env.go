@@ -4,11 +4,14 @@ import (
 	"cf"
 	"cf/api"
 	"cf/configuration"
+	"cf/manifest"
 	"cf/models"
 	"cf/requirements"
 	"cf/terminal"
+	"encoding/json"
 	"errors"
 	"github.com/codegangsta/cli"
+	"io/ioutil"
 )
 
 type SetEnv struct {
@@ -27,7 +30,12 @@ func NewSetEnv(ui terminal.UI, config configuration.Reader, appRepo api.Applicat
 }
 
 func (cmd *SetEnv) GetRequirements(reqFactory requirements.Factory, c *cli.Context) (reqs []requirements.Requirement, err error) {
-	if len(c.Args()) < 3 {
+	requiredArgs := 3
+	if c.String("from-file") != "" {
+		requiredArgs = 1
+	}
+
+	if len(c.Args()) < requiredArgs {
 		err = errors.New("Incorrect Usage")
 		cmd.ui.FailWithUsage(c, "set-env")
 		return
@@ -43,10 +51,24 @@ func (cmd *SetEnv) GetRequirements(reqFactory requirements.Factory, c *cli.Conte
 }
 
 func (cmd *SetEnv) Run(c *cli.Context) {
+	if c.String("from-file") != "" {
+		cmd.setEnvFromFile(c.String("from-file"), c.Bool("dry-run"))
+		return
+	}
+
 	varName := c.Args()[1]
 	varValue := c.Args()[2]
 	app := cmd.appReq.GetApplication()
 
+	if c.Bool("json") {
+		var err error
+		varValue, err = jsonEnvVarValue(varValue)
+		if err != nil {
+			cmd.ui.Failed("Invalid value for env variable '%s': %s", varName, err)
+			return
+		}
+	}
+
 	cmd.ui.Say("Setting env variable '%s' to '%s' for app %s in org %s / space %s as %s...",
 		terminal.EntityNameColor(varName),
 		terminal.EntityNameColor(varValue),
@@ -72,3 +94,46 @@ func (cmd *SetEnv) Run(c *cli.Context) {
 	cmd.ui.Ok()
 	cmd.ui.Say("TIP: Use '%s' to ensure your env variable changes take effect", terminal.CommandColor(cf.Name()+" push"))
 }
+
+func (cmd *SetEnv) setEnvFromFile(path string, dryRun bool) {
+	app := cmd.appReq.GetApplication()
+
+	cmd.ui.Say("Setting env variables from %s for app %s in org %s / space %s as %s...",
+		terminal.EntityNameColor(path),
+		terminal.EntityNameColor(app.Name),
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+	)
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		cmd.ui.Failed("Error reading env file %s\n%s", path, err)
+		return
+	}
+
+	fileVars, err := parseDotenv(string(content))
+	if err != nil {
+		cmd.ui.Failed("Error parsing env file %s\n%s", path, err)
+		return
+	}
+
+	envParams := map[string]string{}
+	for key, value := range app.EnvironmentVars {
+		envParams[key] = value
+	}
+	for key, value := range fileVars {
+		envParams[key] = value
+	}
+
+	applyEnvChanges(cmd.ui, cmd.appRepo, app, envParams, dryRun)
+}
+
+func jsonEnvVarValue(input string) (value string, err error) {
+	var parsed interface{}
+	err = json.Unmarshal([]byte(input), &parsed)
+	if err != nil {
+		return
+	}
+	return manifest.EnvVarValue(parsed)
+}
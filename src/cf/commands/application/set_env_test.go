@@ -6,6 +6,8 @@ import (
 	"cf/models"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
 	testapi "testhelpers/api"
 	testassert "testhelpers/assert"
 	testcmd "testhelpers/commands"
@@ -72,6 +74,41 @@ var _ = Describe("set-env command", func() {
 		}))
 	})
 
+	It("TestSetEnvWithAJSONValue", func() {
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		reqFactory := &testreq.FakeReqFactory{Application: app, LoginSuccess: true, TargetedSpaceSuccess: true}
+		appRepo := &testapi.FakeApplicationRepository{}
+
+		callSetEnv([]string{"--json", "my-app", "PORT", "8080"}, reqFactory, appRepo)
+		Expect(*appRepo.UpdateParams.EnvironmentVars).To(Equal(map[string]string{"PORT": "8080"}))
+
+		callSetEnv([]string{"--json", "my-app", "DATABASE", `{ "port": 5432, "host": "db" }`}, reqFactory, appRepo)
+		Expect((*appRepo.UpdateParams.EnvironmentVars)["DATABASE"]).To(Equal(`{"host":"db","port":5432}`))
+	})
+
+	It("TestSetEnvWithAnInvalidJSONValue", func() {
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		reqFactory := &testreq.FakeReqFactory{Application: app, LoginSuccess: true, TargetedSpaceSuccess: true}
+		appRepo := &testapi.FakeApplicationRepository{}
+
+		ui := callSetEnv([]string{"--json", "my-app", "PORT", "not json"}, reqFactory, appRepo)
+		Expect(appRepo.UpdateAppGuid).To(Equal(""))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid value for env variable 'PORT'"},
+		})
+
+		ui = callSetEnv([]string{"--json", "my-app", "PORT", "null"}, reqFactory, appRepo)
+		Expect(appRepo.UpdateAppGuid).To(Equal(""))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Invalid value for env variable 'PORT'", "should not be null"},
+		})
+	})
+
 	It("TestSetEnvWhenItAlreadyExists", func() {
 
 		app := models.Application{}
@@ -153,6 +190,76 @@ var _ = Describe("set-env command", func() {
 	})
 })
 
+var _ = Describe("set-env --from-file", func() {
+	var (
+		reqFactory *testreq.FakeReqFactory
+		appRepo    *testapi.FakeApplicationRepository
+	)
+
+	BeforeEach(func() {
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		app.EnvironmentVars = map[string]string{"PORT": "9090", "KEEP": "me"}
+		reqFactory = &testreq.FakeReqFactory{Application: app, LoginSuccess: true, TargetedSpaceSuccess: true}
+		appRepo = &testapi.FakeApplicationRepository{}
+	})
+
+	It("sets every variable of the file in a single update", func() {
+		ui := callSetEnv([]string{"--from-file", "../../../fixtures/env/app.env", "my-app"}, reqFactory, appRepo)
+
+		Expect(appRepo.UpdatedAppGuids).To(Equal([]string{"my-app-guid"}))
+		Expect(*appRepo.UpdateParams.EnvironmentVars).To(Equal(map[string]string{
+			"KEEP":         "me",
+			"DATABASE_URL": "postgres://db.example.com/app",
+			"GREETING":     "hello $NAME",
+			"PORT":         "8080",
+			"CERT":         "-----BEGIN CERT-----\nabc\"def\n-----END CERT-----",
+			"ESCAPED":      "one\ntwo",
+			"EMPTY":        "",
+		}))
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Setting env variables from", "app.env", "my-app", "my-org", "my-space", "my-user"},
+			{"+ CERT=****"},
+			{"~ PORT=****"},
+			{"OK"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"8080"},
+			{"KEEP"},
+		})
+	})
+
+	It("only shows the changes in a dry run", func() {
+		ui := callSetEnv([]string{"--from-file", "../../../fixtures/env/app.env", "--dry-run", "my-app"}, reqFactory, appRepo)
+
+		Expect(appRepo.UpdatedAppGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"+ DATABASE_URL=****"},
+			{"~ PORT=****"},
+			{"Dry run", "5 added, 1 changed and 0 removed"},
+		})
+	})
+
+	It("fails when the file cannot be parsed", func() {
+		file, err := ioutil.TempFile("", "bad.env")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(file.Name())
+		file.WriteString("GOOD=1\nCERT=\"never closed\n")
+		file.Close()
+
+		ui := callSetEnv([]string{"--from-file", file.Name(), "my-app"}, reqFactory, appRepo)
+
+		Expect(appRepo.UpdatedAppGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Error parsing env file"},
+			{"line 2: missing closing quote for CERT"},
+		})
+	})
+})
+
 func callSetEnv(args []string, reqFactory *testreq.FakeReqFactory, appRepo api.ApplicationRepository) (ui *testterm.FakeUI) {
 	ui = new(testterm.FakeUI)
 	ctxt := testcmd.NewContext("set-env", args)
@@ -20,6 +20,8 @@ type ShowApp struct {
 	appSummaryRepo   api.AppSummaryRepository
 	appInstancesRepo api.AppInstancesRepository
 	appReq           requirements.ApplicationRequirement
+
+	WatchRefreshes int
 }
 
 type ApplicationDisplayer interface {
@@ -54,6 +56,10 @@ func (cmd *ShowApp) GetRequirements(reqFactory requirements.Factory, c *cli.Cont
 
 func (cmd *ShowApp) Run(c *cli.Context) {
 	app := cmd.appReq.GetApplication()
+	if c.Bool("watch") {
+		cmd.watchApp(app, c)
+		return
+	}
 	cmd.ShowApp(app)
 }
 
@@ -66,20 +72,8 @@ func (cmd *ShowApp) ShowApp(app models.Application) {
 		terminal.EntityNameColor(cmd.config.Username()),
 	)
 
-	appSummary, apiResponse := cmd.appSummaryRepo.GetSummary(app.Guid)
-	appIsStopped := apiResponse.ErrorCode == cf.APP_STOPPED ||
-		apiResponse.ErrorCode == cf.APP_NOT_STAGED ||
-		appSummary.State == "stopped"
-
-	if apiResponse.IsNotSuccessful() && !appIsStopped {
-		cmd.ui.Failed(apiResponse.Message)
-		return
-	}
-
-	var instances []models.AppInstanceFields
-	instances, apiResponse = cmd.appInstancesRepo.GetInstances(app.Guid)
-	if apiResponse.IsNotSuccessful() && !appIsStopped {
-		cmd.ui.Failed(apiResponse.Message)
+	appSummary, instances, appIsStopped, ok := cmd.fetchStatus(app)
+	if !ok {
 		return
 	}
 
@@ -117,3 +111,24 @@ func (cmd *ShowApp) ShowApp(app models.Application) {
 
 	cmd.ui.DisplayTable(table)
 }
+
+func (cmd *ShowApp) fetchStatus(app models.Application) (appSummary models.AppSummary, instances []models.AppInstanceFields, appIsStopped bool, ok bool) {
+	appSummary, apiResponse := cmd.appSummaryRepo.GetSummary(app.Guid)
+	appIsStopped = apiResponse.ErrorCode == cf.APP_STOPPED ||
+		apiResponse.ErrorCode == cf.APP_NOT_STAGED ||
+		appSummary.State == "stopped"
+
+	if apiResponse.IsNotSuccessful() && !appIsStopped {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	instances, apiResponse = cmd.appInstancesRepo.GetInstances(app.Guid)
+	if apiResponse.IsNotSuccessful() && !appIsStopped {
+		cmd.ui.Failed(apiResponse.Message)
+		return
+	}
+
+	ok = true
+	return
+}
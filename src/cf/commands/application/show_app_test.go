@@ -171,6 +171,82 @@ func testDisplayingAppSummaryWithErrorCode(errorCode string) {
 	})
 }
 
+var _ = Describe("app --watch", func() {
+	var (
+		reqFactory       *testreq.FakeReqFactory
+		appSummaryRepo   *testapi.FakeAppSummaryRepo
+		appInstancesRepo *testapi.FakeAppInstancesRepo
+	)
+
+	BeforeEach(func() {
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		reqFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: app}
+
+		appSummary := models.AppSummary{}
+		appSummary.State = "started"
+		appSummary.InstanceCount = 2
+		appSummary.RunningInstances = 2
+		appSummary.Memory = 256
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{GetSummarySummary: appSummary}
+
+		quiet := models.AppInstanceFields{State: models.InstanceStarting, CpuUsage: 0.1, MemUsage: 100 * formatters.MEGABYTE, MemQuota: 256 * formatters.MEGABYTE}
+		busy := models.AppInstanceFields{State: models.InstanceRunning, CpuUsage: 0.8, MemUsage: 250 * formatters.MEGABYTE, MemQuota: 256 * formatters.MEGABYTE}
+		quietRunning := quiet
+		quietRunning.State = models.InstanceRunning
+
+		appInstancesRepo = &testapi.FakeAppInstancesRepo{
+			GetInstancesResponses: [][]models.AppInstanceFields{
+				{quiet, busy},
+				{quietRunning, busy},
+			},
+		}
+	})
+
+	It("appends timestamped rows when not on a terminal", func() {
+		ui := callWatchApp([]string{"--watch", "--interval", "1", "my-app"}, &testterm.FakeUI{}, reqFactory, appSummaryRepo, appInstancesRepo)
+
+		Expect(ui.LiveViews[0].Frames).To(HaveLen(2))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Watching health and status for app", "my-app", "every 1s"},
+			{"#0", "starting", "cpu 10.0%", "memory 100M of 256M"},
+			{"#1", "running", "cpu 80.0%", "memory 250M of 256M"},
+			{"#0", "running", "(was starting)"},
+			{"#1", "running"},
+		})
+	})
+
+	It("redraws the instance table sorted by usage on a terminal", func() {
+		ui := callWatchApp([]string{"--watch", "--sort", "cpu", "--interval", "1", "my-app"}, &testterm.FakeUI{Interactive: true}, reqFactory, appSummaryRepo, appInstancesRepo)
+
+		frames := ui.LiveViews[0].Frames
+		Expect(frames).To(HaveLen(2))
+		testassert.SliceContains(frames[1], testassert.Lines{
+			{"requested state:", "started", "instances:", "2/2"},
+			{"state", "since", "cpu", "memory", "disk", "cpu history"},
+			{"#1", "running", "80.0%", "▇▇"},
+			{"#0", "running", "(was starting)", "10.0%", "▁▁"},
+		})
+	})
+
+	It("fails when given an unknown sort order", func() {
+		ui := callWatchApp([]string{"--watch", "--sort", "disk", "my-app"}, &testterm.FakeUI{}, reqFactory, appSummaryRepo, appInstancesRepo)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid sort param: disk"},
+		})
+	})
+})
+
+func callWatchApp(args []string, ui *testterm.FakeUI, reqFactory *testreq.FakeReqFactory, appSummaryRepo *testapi.FakeAppSummaryRepo, appInstancesRepo *testapi.FakeAppInstancesRepo) *testterm.FakeUI {
+	cmd := NewShowApp(ui, testconfig.NewRepositoryWithDefaults(), appSummaryRepo, appInstancesRepo)
+	cmd.WatchRefreshes = 2
+	testcmd.RunCommand(cmd, testcmd.NewContext("app", args), reqFactory)
+	return ui
+}
+
 func callApp(args []string, reqFactory *testreq.FakeReqFactory, appSummaryRepo *testapi.FakeAppSummaryRepo, appInstancesRepo *testapi.FakeAppInstancesRepo) (ui *testterm.FakeUI) {
 	ui = &testterm.FakeUI{}
 	ctxt := testcmd.NewContext("app", args)
@@ -0,0 +1,218 @@
+package application
+
+import (
+	"cf/formatters"
+	"cf/models"
+	"cf/terminal"
+	"fmt"
+	"github.com/codegangsta/cli"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	watchHistoryLength = 10
+	nearQuotaRatio     = 0.9
+)
+
+var sparklineLevels = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// watchApp refreshes the instances of app until interrupted. On a terminal
+// the instance table is redrawn in place, elsewhere a timestamped row is
+// appended for every instance at each refresh.
+func (cmd *ShowApp) watchApp(app models.Application, c *cli.Context) {
+	sortBy := c.String("sort")
+	if sortBy != "" && sortBy != "cpu" && sortBy != "memory" {
+		cmd.ui.Failed("Invalid sort param: %s\nExpected cpu or memory", sortBy)
+		return
+	}
+
+	interval := time.Duration(c.Int("interval")) * time.Second
+	if interval <= 0 {
+		cmd.ui.Failed("Invalid interval param: %d\nExpected a number of seconds", c.Int("interval"))
+		return
+	}
+
+	cmd.ui.Say("Watching health and status for app %s in org %s / space %s as %s, refreshing every %s...\n",
+		terminal.EntityNameColor(app.Name),
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+		interval,
+	)
+
+	view := cmd.ui.NewLiveView()
+	watch := newAppWatch(sortBy)
+
+	for refresh := 0; cmd.WatchRefreshes == 0 || refresh < cmd.WatchRefreshes; refresh++ {
+		if refresh > 0 {
+			cmd.ui.Wait(interval)
+		}
+
+		appSummary, instances, appIsStopped, ok := cmd.fetchStatus(app)
+		if !ok {
+			return
+		}
+		if appIsStopped {
+			instances = nil
+		}
+
+		watch.record(instances)
+		if view.IsLive() {
+			view.Redraw(watch.frame(appSummary, instances, time.Now()))
+		} else {
+			view.Redraw(watch.rows(instances, time.Now()))
+		}
+	}
+}
+
+type appWatch struct {
+	sortBy         string
+	cpuHistory     map[int][]float64
+	previousStates map[int]models.InstanceState
+	changedStates  map[int]models.InstanceState
+}
+
+func newAppWatch(sortBy string) *appWatch {
+	return &appWatch{
+		sortBy:         sortBy,
+		cpuHistory:     map[int][]float64{},
+		previousStates: map[int]models.InstanceState{},
+	}
+}
+
+func (watch *appWatch) record(instances []models.AppInstanceFields) {
+	watch.changedStates = map[int]models.InstanceState{}
+
+	for index, instance := range instances {
+		previous, seen := watch.previousStates[index]
+		if seen && previous != instance.State {
+			watch.changedStates[index] = previous
+		}
+		watch.previousStates[index] = instance.State
+
+		history := append(watch.cpuHistory[index], instance.CpuUsage)
+		if len(history) > watchHistoryLength {
+			history = history[len(history)-watchHistoryLength:]
+		}
+		watch.cpuHistory[index] = history
+	}
+}
+
+func (watch *appWatch) frame(appSummary models.AppSummary, instances []models.AppInstanceFields, now time.Time) (lines []string) {
+	lines = append(lines,
+		fmt.Sprintf("%s %s", terminal.HeaderColor("refreshed:"), now.Format("2006-01-02 03:04:05 PM")),
+		fmt.Sprintf("%s %s   %s %s   %s %s x %d instances",
+			terminal.HeaderColor("requested state:"), coloredAppState(appSummary.ApplicationFields),
+			terminal.HeaderColor("instances:"), coloredAppInstances(appSummary.ApplicationFields),
+			terminal.HeaderColor("usage:"), formatters.ByteSize(appSummary.Memory*formatters.MEGABYTE), appSummary.InstanceCount),
+		"",
+	)
+
+	if len(instances) == 0 {
+		return append(lines, "There are no running instances of this app.")
+	}
+
+	table := [][]string{
+		[]string{"", "state", "since", "cpu", "memory", "disk", "cpu history"},
+	}
+	for _, index := range watch.sortedIndexes(instances) {
+		instance := instances[index]
+		table = append(table, []string{
+			fmt.Sprintf("#%d", index),
+			watch.instanceState(index, instance),
+			instance.Since.Format("2006-01-02 03:04:05 PM"),
+			fmt.Sprintf("%.1f%%", instance.CpuUsage*100),
+			usageOfQuota(instance.MemUsage, instance.MemQuota),
+			usageOfQuota(instance.DiskUsage, instance.DiskQuota),
+			sparkline(watch.cpuHistory[index]),
+		})
+	}
+	return append(lines, terminal.TableLines(table)...)
+}
+
+func (watch *appWatch) rows(instances []models.AppInstanceFields, now time.Time) (lines []string) {
+	timestamp := now.Format("2006-01-02 15:04:05")
+	if len(instances) == 0 {
+		return []string{fmt.Sprintf("%s  no running instances", timestamp)}
+	}
+
+	for _, index := range watch.sortedIndexes(instances) {
+		instance := instances[index]
+		lines = append(lines, fmt.Sprintf("%s  #%d  %s  cpu %.1f%%  memory %s  disk %s",
+			timestamp,
+			index,
+			watch.instanceState(index, instance),
+			instance.CpuUsage*100,
+			usageOfQuota(instance.MemUsage, instance.MemQuota),
+			usageOfQuota(instance.DiskUsage, instance.DiskQuota),
+		))
+	}
+	return
+}
+
+func (watch *appWatch) instanceState(index int, instance models.AppInstanceFields) string {
+	state := coloredInstanceState(instance)
+	if previous, changed := watch.changedStates[index]; changed {
+		return fmt.Sprintf("%s %s", state, terminal.WarningColor(fmt.Sprintf("(was %s)", previous)))
+	}
+	return state
+}
+
+func (watch *appWatch) sortedIndexes(instances []models.AppInstanceFields) []int {
+	sorter := instancesByUsage{instances: instances, sortBy: watch.sortBy}
+	for index := range instances {
+		sorter.indexes = append(sorter.indexes, index)
+	}
+	if watch.sortBy != "" {
+		sort.Stable(sorter)
+	}
+	return sorter.indexes
+}
+
+// instancesByUsage orders instance indexes by decreasing cpu or memory usage.
+type instancesByUsage struct {
+	instances []models.AppInstanceFields
+	indexes   []int
+	sortBy    string
+}
+
+func (sorter instancesByUsage) Len() int {
+	return len(sorter.indexes)
+}
+
+func (sorter instancesByUsage) Swap(i, j int) {
+	sorter.indexes[i], sorter.indexes[j] = sorter.indexes[j], sorter.indexes[i]
+}
+
+func (sorter instancesByUsage) Less(i, j int) bool {
+	first, second := sorter.instances[sorter.indexes[i]], sorter.instances[sorter.indexes[j]]
+	if sorter.sortBy == "memory" {
+		return first.MemUsage > second.MemUsage
+	}
+	return first.CpuUsage > second.CpuUsage
+}
+
+func usageOfQuota(usage, quota uint64) string {
+	text := fmt.Sprintf("%s of %s", formatters.ByteSize(usage), formatters.ByteSize(quota))
+	if quota > 0 && float64(usage) >= nearQuotaRatio*float64(quota) {
+		return terminal.CrashedColor(text)
+	}
+	return text
+}
+
+func sparkline(values []float64) string {
+	levels := make([]string, len(values))
+	for index, value := range values {
+		level := int(value * float64(len(sparklineLevels)))
+		if level >= len(sparklineLevels) {
+			level = len(sparklineLevels) - 1
+		}
+		if level < 0 {
+			level = 0
+		}
+		levels[index] = sparklineLevels[level]
+	}
+	return strings.Join(levels, "")
+}
@@ -33,6 +33,12 @@ type Start struct {
 	appRepo          api.ApplicationRepository
 	appInstancesRepo api.AppInstancesRepository
 	logRepo          api.LogsRepository
+	appEventsRepo    api.AppEventsRepository
+	routeHealthRepo  api.RouteHealthRepository
+	appSummaryRepo   api.AppSummaryRepository
+
+	instancesThreshold InstancesThreshold
+	healthCheck        HealthCheck
 
 	StartupTimeout time.Duration
 	StagingTimeout time.Duration
@@ -41,10 +47,13 @@ type Start struct {
 
 type ApplicationStarter interface {
 	SetStartTimeoutSeconds(timeout int)
+	SetInstancesThreshold(threshold InstancesThreshold)
+	SetHealthCheck(check HealthCheck)
 	ApplicationStart(app models.Application) (updatedApp models.Application, err error)
+	WithUI(ui terminal.UI) ApplicationStarter
 }
 
-func NewStart(ui terminal.UI, config configuration.Reader, appDisplayer ApplicationDisplayer, appRepo api.ApplicationRepository, appInstancesRepo api.AppInstancesRepository, logRepo api.LogsRepository) (cmd *Start) {
+func NewStart(ui terminal.UI, config configuration.Reader, appDisplayer ApplicationDisplayer, appRepo api.ApplicationRepository, appInstancesRepo api.AppInstancesRepository, logRepo api.LogsRepository, appEventsRepo api.AppEventsRepository, routeHealthRepo api.RouteHealthRepository, appSummaryRepo api.AppSummaryRepository) (cmd *Start) {
 	cmd = new(Start)
 	cmd.ui = ui
 	cmd.config = config
@@ -52,6 +61,10 @@ func NewStart(ui terminal.UI, config configuration.Reader, appDisplayer Applicat
 	cmd.appRepo = appRepo
 	cmd.appInstancesRepo = appInstancesRepo
 	cmd.logRepo = logRepo
+	cmd.appEventsRepo = appEventsRepo
+	cmd.routeHealthRepo = routeHealthRepo
+	cmd.appSummaryRepo = appSummaryRepo
+	cmd.instancesThreshold = DefaultInstancesThreshold
 
 	cmd.PingerThrottle = DefaultPingerThrottle
 
@@ -92,9 +105,31 @@ func (cmd *Start) GetRequirements(reqFactory requirements.Factory, c *cli.Contex
 }
 
 func (cmd *Start) Run(c *cli.Context) {
-	cmd.ApplicationStart(cmd.appReq.GetApplication())
+	if c.String("wait-for") != "" {
+		threshold, err := ParseInstancesThreshold(c.String("wait-for"))
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+			return
+		}
+		cmd.SetInstancesThreshold(threshold)
+	}
+
+	healthCheck, err := NewHealthCheck(c)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+	cmd.SetHealthCheck(healthCheck)
+
+	_, err = cmd.ApplicationStart(cmd.appReq.GetApplication())
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
 }
 
+// ApplicationStart starts app and waits for its instances and health check.
+// It does not fail the command itself, a failure is returned so that the
+// caller can decide what to do about it.
 func (cmd *Start) ApplicationStart(app models.Application) (updatedApp models.Application, err error) {
 	if app.State == "started" {
 		cmd.ui.Say(terminal.WarningColor("App " + app.Name + " is already started"))
@@ -120,21 +155,33 @@ func (cmd *Start) ApplicationStart(app models.Application) (updatedApp models.Ap
 	state := "STARTED"
 	updatedApp, apiResponse := cmd.appRepo.Update(app.Guid, models.AppParams{State: &state})
 	if apiResponse.IsNotSuccessful() {
-		cmd.ui.Failed(apiResponse.Message)
+		err = errors.New(apiResponse.Message)
 		return
 	}
 
 	cmd.ui.Ok()
 
-	cmd.waitForInstancesToStage(updatedApp)
+	err = cmd.waitForInstancesToStage(updatedApp)
+	if err != nil {
+		return
+	}
 	stopLoggingChan <- true
 
 	cmd.ui.Say("")
 
-	cmd.waitForOneRunningInstance(updatedApp)
+	err = cmd.waitForRunningInstances(updatedApp)
+	if err != nil {
+		return
+	}
+
+	healthCheckResults, err := cmd.checkRoutesHealth(updatedApp)
+	if err != nil {
+		return
+	}
 	cmd.ui.Say(terminal.HeaderColor("\nApp started\n"))
 
 	cmd.appDisplayer.ShowApp(updatedApp)
+	cmd.showHealthCheckResults(healthCheckResults)
 	return
 }
 
@@ -142,6 +189,28 @@ func (cmd *Start) SetStartTimeoutSeconds(timeout int) {
 	cmd.StartupTimeout = time.Duration(timeout) * time.Second
 }
 
+func (cmd *Start) SetInstancesThreshold(threshold InstancesThreshold) {
+	cmd.instancesThreshold = threshold
+}
+
+func (cmd *Start) SetHealthCheck(check HealthCheck) {
+	cmd.healthCheck = check
+}
+
+// WithUI returns a copy of the starter that reports to ui, so that apps can
+// be started next to each other. The copy does not show the started app, the
+// apps pushed together are summed up once they are all done.
+func (cmd *Start) WithUI(ui terminal.UI) ApplicationStarter {
+	starter := *cmd
+	starter.ui = ui
+	starter.appDisplayer = hiddenAppDisplayer{}
+	return &starter
+}
+
+type hiddenAppDisplayer struct{}
+
+func (displayer hiddenAppDisplayer) ShowApp(app models.Application) {}
+
 func (cmd Start) tailStagingLogs(app models.Application, startChan chan bool, stopChan chan bool) {
 	logChan := make(chan *logmessage.Message, 1000)
 	go func() {
@@ -171,14 +240,14 @@ func (cmd Start) displayLogMessages(logChan chan *logmessage.Message) {
 	}
 }
 
-func (cmd Start) waitForInstancesToStage(app models.Application) {
+func (cmd Start) waitForInstancesToStage(app models.Application) (err error) {
 	stagingStartTime := time.Now()
 	_, apiResponse := cmd.appInstancesRepo.GetInstances(app.Guid)
 
 	for apiResponse.IsNotSuccessful() && time.Since(stagingStartTime) < cmd.StagingTimeout {
 		if apiResponse.ErrorCode != cf.APP_NOT_STAGED {
 			cmd.ui.Say("")
-			cmd.ui.Failed(fmt.Sprintf("%s\n\nTIP: use '%s' for more information",
+			err = errors.New(fmt.Sprintf("%s\n\nTIP: use '%s' for more information",
 				apiResponse.Message,
 				terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))))
 			return
@@ -189,13 +258,13 @@ func (cmd Start) waitForInstancesToStage(app models.Application) {
 	return
 }
 
-func (cmd Start) waitForOneRunningInstance(app models.Application) {
-	var runningCount, startingCount, flappingCount, downCount int
+func (cmd Start) waitForRunningInstances(app models.Application) (err error) {
+	var runningCount, startingCount, flappingCount, downCount, totalCount int
 	startupStartTime := time.Now()
 
-	for runningCount == 0 {
+	for !cmd.instancesThreshold.IsReached(runningCount, totalCount) {
 		if time.Since(startupStartTime) > cmd.StartupTimeout {
-			cmd.ui.Failed(fmt.Sprintf("Start app timeout\n\nTIP: use '%s' for more information", terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))))
+			err = errors.New(fmt.Sprintf("Start app timeout\n\nTIP: use '%s' for more information", terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))))
 			return
 		}
 
@@ -205,7 +274,7 @@ func (cmd Start) waitForOneRunningInstance(app models.Application) {
 			continue
 		}
 
-		totalCount := len(instances)
+		totalCount = len(instances)
 		runningCount, startingCount, flappingCount, downCount = 0, 0, 0, 0
 
 		for _, inst := range instances {
@@ -224,10 +293,13 @@ func (cmd Start) waitForOneRunningInstance(app models.Application) {
 		cmd.ui.Say(instancesDetails(startingCount, downCount, runningCount, flappingCount, totalCount))
 
 		if flappingCount > 0 {
-			cmd.ui.Failed(fmt.Sprintf("Start unsuccessful\n\nTIP: use '%s' for more information", terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))))
+			err = errors.New(fmt.Sprintf("Start unsuccessful%s\n\nTIP: use '%s' for more information",
+				cmd.crashDetails(app),
+				terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))))
 			return
 		}
 	}
+	return
 }
 
 func instancesDetails(startingCount, downCount, runningCount, flappingCount, totalCount int) string {
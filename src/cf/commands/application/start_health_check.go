@@ -0,0 +1,103 @@
+package application
+
+import (
+	"cf/models"
+	"errors"
+	"fmt"
+	"github.com/codegangsta/cli"
+	"strings"
+	"time"
+)
+
+// HealthCheck is a path that start requests on every route of an app once
+// its instances run, and the status code the app has to answer with.
+type HealthCheck struct {
+	Path           string
+	ExpectedStatus int
+	Timeout        time.Duration
+}
+
+func NewHealthCheck(c *cli.Context) (check HealthCheck, err error) {
+	check.Path = c.String("health-check-path")
+	if check.Path == "" {
+		return
+	}
+	if !strings.HasPrefix(check.Path, "/") {
+		check.Path = "/" + check.Path
+	}
+
+	check.ExpectedStatus = c.Int("expect-status")
+	if check.ExpectedStatus < 100 || check.ExpectedStatus > 599 {
+		err = errors.New(fmt.Sprintf("Invalid expect-status param: %d\nExpected an HTTP status code", check.ExpectedStatus))
+		return
+	}
+
+	if c.Int("health-check-timeout") < 1 {
+		err = errors.New(fmt.Sprintf("Invalid health-check-timeout param: %d\nExpected a number of seconds", c.Int("health-check-timeout")))
+		return
+	}
+	check.Timeout = time.Duration(c.Int("health-check-timeout")) * time.Second
+	return
+}
+
+type healthCheckResult struct {
+	url        string
+	statusCode int
+	latency    time.Duration
+}
+
+// checkRoutesHealth requests the health check path on every route of app
+// until it answers with the expected status, and fails once the health
+// check times out. The routes come from the app summary, as only it has the
+// domains of the routes.
+func (cmd Start) checkRoutesHealth(app models.Application) (results []healthCheckResult, err error) {
+	if cmd.healthCheck.Path == "" {
+		return
+	}
+
+	summary, apiResponse := cmd.appSummaryRepo.GetSummary(app.Guid)
+	if apiResponse.IsNotSuccessful() {
+		err = errors.New(fmt.Sprintf("Could not find the routes of %s to check their health\n%s", app.Name, apiResponse.Message))
+		return
+	}
+	if len(summary.RouteSummaries) == 0 {
+		err = errors.New(fmt.Sprintf("Health check of %s failed: the app has no routes to request %s on", app.Name, cmd.healthCheck.Path))
+		return
+	}
+
+	for _, route := range summary.RouteSummaries {
+		url := fmt.Sprintf("http://%s%s", route.URL(), cmd.healthCheck.Path)
+		cmd.ui.Say("Checking health of %s...", url)
+
+		result := healthCheckResult{url: url}
+		var probeErr error
+		startTime := time.Now()
+
+		for {
+			result.statusCode, result.latency, probeErr = cmd.routeHealthRepo.Probe(url, cmd.healthCheck.Timeout)
+			if probeErr == nil && result.statusCode == cmd.healthCheck.ExpectedStatus {
+				break
+			}
+
+			if time.Since(startTime) > cmd.healthCheck.Timeout {
+				if probeErr != nil {
+					err = errors.New(fmt.Sprintf("Health check of %s failed\n%s", url, probeErr))
+				} else {
+					err = errors.New(fmt.Sprintf("Health check of %s failed: got status %d, expected %d",
+						url, result.statusCode, cmd.healthCheck.ExpectedStatus))
+				}
+				return
+			}
+			cmd.ui.Wait(cmd.PingerThrottle)
+		}
+
+		results = append(results, result)
+	}
+	return
+}
+
+func (cmd Start) showHealthCheckResults(results []healthCheckResult) {
+	for _, result := range results {
+		cmd.ui.Say("health check %s: %d in %dms", result.url, result.statusCode, result.latency/time.Millisecond)
+	}
+}
@@ -62,7 +62,7 @@ var _ = Describe("Testing with ginkgo", func() {
 	})
 
 	It("TestStartCommandDefaultTimeouts", func() {
-		cmd := NewStart(new(testterm.FakeUI), testconfig.NewRepository(), &testcmd.FakeAppDisplayer{}, &testapi.FakeApplicationRepository{}, &testapi.FakeAppInstancesRepo{}, &testapi.FakeLogsRepository{})
+		cmd := NewStart(new(testterm.FakeUI), testconfig.NewRepository(), &testcmd.FakeAppDisplayer{}, &testapi.FakeApplicationRepository{}, &testapi.FakeAppInstancesRepo{}, &testapi.FakeLogsRepository{}, &testapi.FakeAppEventsRepo{}, &testapi.FakeRouteHealthRepo{}, &testapi.FakeAppSummaryRepo{})
 		Expect(cmd.StagingTimeout).To(Equal(15 * time.Minute))
 		Expect(cmd.StartupTimeout).To(Equal(5 * time.Minute))
 	})
@@ -77,7 +77,7 @@ var _ = Describe("Testing with ginkgo", func() {
 
 		os.Setenv("CF_STAGING_TIMEOUT", "6")
 		os.Setenv("CF_STARTUP_TIMEOUT", "3")
-		cmd := NewStart(new(testterm.FakeUI), testconfig.NewRepository(), &testcmd.FakeAppDisplayer{}, &testapi.FakeApplicationRepository{}, &testapi.FakeAppInstancesRepo{}, &testapi.FakeLogsRepository{})
+		cmd := NewStart(new(testterm.FakeUI), testconfig.NewRepository(), &testcmd.FakeAppDisplayer{}, &testapi.FakeApplicationRepository{}, &testapi.FakeAppInstancesRepo{}, &testapi.FakeLogsRepository{}, &testapi.FakeAppEventsRepo{}, &testapi.FakeRouteHealthRepo{}, &testapi.FakeAppSummaryRepo{})
 		Expect(cmd.StagingTimeout).To(Equal(6 * time.Minute))
 		Expect(cmd.StartupTimeout).To(Equal(3 * time.Minute))
 	})
@@ -259,6 +259,180 @@ var _ = Describe("Testing with ginkgo", func() {
 		})
 	})
 
+	It("TestStartApplicationWhenOneInstanceFlapsShowsCrashesAndLogs", func() {
+		flapping := models.AppInstanceFields{State: models.InstanceFlapping}
+		appInstancesRepo := &testapi.FakeAppInstancesRepo{
+			GetInstancesResponses: [][]models.AppInstanceFields{
+				{flapping, flapping},
+				{flapping, flapping},
+			},
+			GetInstancesErrorCodes: defaultInstanceErrorCodes,
+		}
+		logRepo := &testapi.FakeLogsRepository{
+			RecentLogs: []*logmessage.Message{
+				NewLogMessage("Booting", defaultAppForStart.Guid, "App", time.Now()),
+				NewLogMessage("Missing DATABASE_URL", defaultAppForStart.Guid, "App", time.Now()),
+			},
+		}
+		eventsRepo := &testapi.FakeAppEventsRepo{
+			Events: []models.EventFields{
+				{Name: "app.crash", Description: "index: 1, reason: CRASHED, exit_status: 4"},
+				{Name: "app.crash", Description: "index: 0, reason: CRASHED, exit_status: 3"},
+				{Name: "app.crash", Description: "index: 1, reason: CRASHED, exit_status: 2"},
+				{Name: "app.crash", Description: "index: 0, reason: CRASHED, exit_status: 1"},
+			},
+		}
+
+		ui := new(testterm.FakeUI)
+		cmd := NewStart(ui, testconfig.NewRepositoryWithDefaults(), &testcmd.FakeAppDisplayer{}, &testapi.FakeApplicationRepository{UpdateAppResult: defaultAppForStart}, appInstancesRepo, logRepo, eventsRepo, &testapi.FakeRouteHealthRepo{}, &testapi.FakeAppSummaryRepo{})
+		cmd.PingerThrottle = 0
+		testcmd.RunCommand(cmd, testcmd.NewContext("start", []string{"my-app"}), &testreq.FakeReqFactory{Application: defaultAppForStart})
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Start unsuccessful"},
+			{"Recent crashes"},
+			{"index: 1, reason: CRASHED, exit_status: 2"},
+			{"index: 0, reason: CRASHED, exit_status: 3"},
+			{"index: 1, reason: CRASHED, exit_status: 4"},
+			{"Last log lines"},
+			{"Booting"},
+			{"Missing DATABASE_URL"},
+			{"TIP", "logs my-app --recent"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"exit_status: 1"},
+		})
+		Expect(eventsRepo.Filters).To(Equal([]api.EventFilter{
+			{Type: api.APP_CRASH_EVENT_TYPE, Descending: true, PageSize: 3},
+		}))
+	})
+
+	It("TestStartApplicationWaitsForAllInstances", func() {
+		starting := models.AppInstanceFields{State: models.InstanceStarting}
+		running := models.AppInstanceFields{State: models.InstanceRunning}
+		appInstancesRepo := &testapi.FakeAppInstancesRepo{
+			GetInstancesResponses: [][]models.AppInstanceFields{
+				{starting, starting},
+				{running, starting},
+				{running, running},
+			},
+			GetInstancesErrorCodes: []string{"", "", ""},
+		}
+
+		ui := callStart([]string{"--wait-for", "all", "my-app"}, testconfig.NewRepositoryWithDefaults(), &testreq.FakeReqFactory{Application: defaultAppForStart},
+			&testcmd.FakeAppDisplayer{}, &testapi.FakeApplicationRepository{UpdateAppResult: defaultAppForStart}, appInstancesRepo, &testapi.FakeLogsRepository{})
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"1 of 2 instances running", "1 starting"},
+			{"2 of 2 instances running"},
+			{"App started"},
+		})
+	})
+
+	It("TestStartApplicationWaitsForAPercentageOfInstances", func() {
+		starting := models.AppInstanceFields{State: models.InstanceStarting}
+		running := models.AppInstanceFields{State: models.InstanceRunning}
+		appInstancesRepo := &testapi.FakeAppInstancesRepo{
+			GetInstancesResponses: [][]models.AppInstanceFields{
+				{starting, starting, starting, starting},
+				{running, starting, starting, starting},
+				{running, running, running, starting},
+			},
+			GetInstancesErrorCodes: []string{"", "", ""},
+		}
+
+		ui := callStart([]string{"--wait-for", "75%", "my-app"}, testconfig.NewRepositoryWithDefaults(), &testreq.FakeReqFactory{Application: defaultAppForStart},
+			&testcmd.FakeAppDisplayer{}, &testapi.FakeApplicationRepository{UpdateAppResult: defaultAppForStart}, appInstancesRepo, &testapi.FakeLogsRepository{})
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"1 of 4 instances running", "3 starting"},
+			{"3 of 4 instances running", "1 starting"},
+			{"App started"},
+		})
+	})
+
+	It("TestStartApplicationWithAnInvalidWaitFor", func() {
+		appRepo := &testapi.FakeApplicationRepository{UpdateAppResult: defaultAppForStart}
+
+		ui := callStart([]string{"--wait-for", "0", "my-app"}, testconfig.NewRepositoryWithDefaults(), &testreq.FakeReqFactory{Application: defaultAppForStart},
+			&testcmd.FakeAppDisplayer{}, appRepo, &testapi.FakeAppInstancesRepo{}, &testapi.FakeLogsRepository{})
+
+		Expect(appRepo.UpdateAppGuid).To(Equal(""))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Invalid wait-for param: 0"},
+		})
+	})
+
+	It("TestStartApplicationChecksTheHealthOfItsRoutes", func() {
+		routeHealthRepo := &testapi.FakeRouteHealthRepo{
+			StatusCodes: []int{502, 200},
+			Latency:     35 * time.Millisecond,
+		}
+
+		ui := callStartWithHealthCheck([]string{"--health-check-path", "health", "my-app"}, defaultAppForStart, defaultInstanceReponses, routeHealthRepo)
+
+		Expect(routeHealthRepo.ProbedUrls).To(Equal([]string{"http://my-app.example.com/health", "http://my-app.example.com/health"}))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Checking health of http://my-app.example.com/health"},
+			{"App started"},
+			{"health check http://my-app.example.com/health: 200 in 35ms"},
+		})
+	})
+
+	It("TestStartApplicationFailsWhenTheHealthCheckTimesOut", func() {
+		routeHealthRepo := &testapi.FakeRouteHealthRepo{StatusCodes: []int{500}}
+
+		ui := callStartWithHealthCheck([]string{"--health-check-path", "/health", "--expect-status", "204", "--health-check-timeout", "1", "my-app"}, defaultAppForStart, defaultInstanceReponses, routeHealthRepo)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Health check of http://my-app.example.com/health failed", "got status 500, expected 204"},
+		})
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"App started"},
+		})
+	})
+
+	It("TestStartApplicationFailsTheHealthCheckWithoutRoutes", func() {
+		routeHealthRepo := &testapi.FakeRouteHealthRepo{}
+		app := defaultAppForStart
+		app.Routes = []models.RouteSummary{}
+
+		ui := callStartWithHealthCheck([]string{"--health-check-path", "/health", "my-app"}, app, defaultInstanceReponses, routeHealthRepo)
+
+		Expect(routeHealthRepo.ProbedUrls).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"Health check of my-app failed", "no routes"},
+		})
+	})
+
+	It("TestApplicationStartReturnsTheFailureInsteadOfFailing", func() {
+		flapping := models.AppInstanceFields{State: models.InstanceFlapping}
+		appInstancesRepo := &testapi.FakeAppInstancesRepo{
+			GetInstancesResponses: [][]models.AppInstanceFields{
+				{flapping, flapping},
+				{flapping, flapping},
+			},
+			GetInstancesErrorCodes: defaultInstanceErrorCodes,
+		}
+
+		ui := new(testterm.FakeUI)
+		cmd := NewStart(ui, testconfig.NewRepositoryWithDefaults(), &testcmd.FakeAppDisplayer{}, &testapi.FakeApplicationRepository{UpdateAppResult: defaultAppForStart}, appInstancesRepo, &testapi.FakeLogsRepository{}, &testapi.FakeAppEventsRepo{}, &testapi.FakeRouteHealthRepo{}, &testapi.FakeAppSummaryRepo{})
+		cmd.StartupTimeout = 50 * time.Millisecond
+		cmd.PingerThrottle = 0
+
+		_, err := cmd.ApplicationStart(defaultAppForStart)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Start unsuccessful"))
+		testassert.SliceDoesNotContain(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+		})
+	})
+
 	It("TestStartApplicationWhenStartTimesOut", func() {
 		displayApp := &testcmd.FakeAppDisplayer{}
 		appInstance := models.AppInstanceFields{}
@@ -367,7 +541,7 @@ func callStart(args []string, config configuration.Reader, reqFactory *testreq.F
 	ui = new(testterm.FakeUI)
 	ctxt := testcmd.NewContext("start", args)
 
-	cmd := NewStart(ui, config, displayApp, appRepo, appInstancesRepo, logRepo)
+	cmd := NewStart(ui, config, displayApp, appRepo, appInstancesRepo, logRepo, &testapi.FakeAppEventsRepo{}, &testapi.FakeRouteHealthRepo{}, &testapi.FakeAppSummaryRepo{})
 	cmd.StagingTimeout = 50 * time.Millisecond
 	cmd.StartupTimeout = 50 * time.Millisecond
 	cmd.PingerThrottle = 50 * time.Millisecond
@@ -376,6 +550,30 @@ func callStart(args []string, config configuration.Reader, reqFactory *testreq.F
 	return
 }
 
+func callStartWithHealthCheck(args []string, app models.Application, instances [][]models.AppInstanceFields, routeHealthRepo api.RouteHealthRepository) (ui *testterm.FakeUI) {
+	ui = new(testterm.FakeUI)
+
+	// like the cloud controller, updating the app does not return the domains of its routes
+	updatedApp := app
+	updatedApp.Routes = []models.RouteSummary{}
+	for _, route := range app.Routes {
+		route.Domain = models.DomainFields{}
+		updatedApp.Routes = append(updatedApp.Routes, route)
+	}
+	appRepo := &testapi.FakeApplicationRepository{UpdateAppResult: updatedApp}
+	appInstancesRepo := &testapi.FakeAppInstancesRepo{GetInstancesResponses: instances}
+
+	appSummaryRepo := &testapi.FakeAppSummaryRepo{}
+	appSummaryRepo.GetSummarySummary.RouteSummaries = app.Routes
+
+	cmd := NewStart(ui, testconfig.NewRepositoryWithDefaults(), &testcmd.FakeAppDisplayer{}, appRepo, appInstancesRepo, &testapi.FakeLogsRepository{}, &testapi.FakeAppEventsRepo{}, routeHealthRepo, appSummaryRepo)
+	cmd.StartupTimeout = 50 * time.Millisecond
+	cmd.PingerThrottle = 50 * time.Millisecond
+
+	testcmd.RunCommand(cmd, testcmd.NewContext("start", args), &testreq.FakeReqFactory{Application: app})
+	return
+}
+
 func startAppWithInstancesAndErrors(displayApp ApplicationDisplayer, app models.Application, instances [][]models.AppInstanceFields, errorCodes []string, startTimeout time.Duration) (ui *testterm.FakeUI, appRepo *testapi.FakeApplicationRepository, appInstancesRepo *testapi.FakeAppInstancesRepo, reqFactory *testreq.FakeReqFactory) {
 	configRepo := testconfig.NewRepositoryWithDefaults()
 	appRepo = &testapi.FakeApplicationRepository{
@@ -0,0 +1,100 @@
+package application
+
+import (
+	"cf/api"
+	"cf/models"
+	"errors"
+	"fmt"
+	"github.com/cloudfoundry/loggregatorlib/logmessage"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxCrashEventsShown = 3
+	maxLogLinesShown    = 10
+)
+
+// InstancesThreshold is how many instances of an app have to run before
+// start considers the app started: all of them, a number or a percentage.
+type InstancesThreshold struct {
+	All     bool
+	Count   int
+	Percent int
+}
+
+var DefaultInstancesThreshold = InstancesThreshold{Count: 1}
+
+func ParseInstancesThreshold(value string) (threshold InstancesThreshold, err error) {
+	if value == "all" {
+		threshold.All = true
+		return
+	}
+
+	if strings.HasSuffix(value, "%") {
+		threshold.Percent, err = strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || threshold.Percent < 1 || threshold.Percent > 100 {
+			err = errors.New(fmt.Sprintf("Invalid wait-for param: %s\nExpected a percentage between 1%% and 100%%", value))
+		}
+		return
+	}
+
+	threshold.Count, err = strconv.Atoi(value)
+	if err != nil || threshold.Count < 1 {
+		err = errors.New(fmt.Sprintf("Invalid wait-for param: %s\nExpected 'all', a number of instances or a percentage such as 50%%", value))
+	}
+	return
+}
+
+func (threshold InstancesThreshold) IsReached(runningCount, totalCount int) bool {
+	if runningCount == 0 {
+		return false
+	}
+
+	switch {
+	case threshold.All:
+		return runningCount >= totalCount
+	case threshold.Percent > 0:
+		return runningCount*100 >= threshold.Percent*totalCount
+	default:
+		return runningCount >= threshold.Count || runningCount >= totalCount
+	}
+}
+
+// crashDetails describes the last crash events and log lines of an app, so
+// that a failed start shows why the instances are crashing. Whatever cannot
+// be fetched is left out.
+func (cmd Start) crashDetails(app models.Application) (details string) {
+	// the crashes come newest first, so only the ones shown are fetched
+	crashes := []string{}
+	filter := api.EventFilter{Type: api.APP_CRASH_EVENT_TYPE, Descending: true, PageSize: maxCrashEventsShown}
+	apiResponse := cmd.appEventsRepo.ListEventsWithFilter(app.Guid, filter, func(event models.EventFields) bool {
+		line := fmt.Sprintf("   %s %s", event.Timestamp.Local().Format(TIMESTAMP_FORMAT), event.Description)
+		crashes = append([]string{line}, crashes...)
+		return len(crashes) < maxCrashEventsShown
+	})
+	if apiResponse.IsSuccessful() && len(crashes) > 0 {
+		details += "\n\nRecent crashes:\n" + strings.Join(crashes, "\n")
+	}
+
+	logLines := []string{}
+	logChan := make(chan *logmessage.Message, 1000)
+	go func() {
+		defer close(logChan)
+		cmd.logRepo.RecentLogsFor(app.Guid, func() {}, logChan)
+	}()
+	for msg := range logChan {
+		logLines = append(logLines, "   "+LogMessageOutput(msg))
+	}
+	if len(logLines) > 0 {
+		details += "\n\nLast log lines:\n" + strings.Join(lastLines(logLines, maxLogLinesShown), "\n")
+	}
+	return
+}
+
+func lastLines(lines []string, count int) []string {
+	if len(lines) > count {
+		return lines[len(lines)-count:]
+	}
+	return lines
+}
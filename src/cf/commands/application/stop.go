@@ -12,6 +12,7 @@ import (
 
 type ApplicationStopper interface {
 	ApplicationStop(app models.Application) (updatedApp models.Application, err error)
+	WithUI(ui terminal.UI) ApplicationStopper
 }
 
 type Stop struct {
@@ -61,7 +62,6 @@ func (cmd *Stop) ApplicationStop(app models.Application) (updatedApp models.Appl
 	updatedApp, apiResponse := cmd.appRepo.Update(app.Guid, models.AppParams{State: &state})
 	if apiResponse.IsNotSuccessful() {
 		err = errors.New(apiResponse.Message)
-		cmd.ui.Failed(apiResponse.Message)
 		return
 	}
 
@@ -69,7 +69,17 @@ func (cmd *Stop) ApplicationStop(app models.Application) (updatedApp models.Appl
 	return
 }
 
+// WithUI returns a copy of the stopper that reports to ui.
+func (cmd *Stop) WithUI(ui terminal.UI) ApplicationStopper {
+	stopper := *cmd
+	stopper.ui = ui
+	return &stopper
+}
+
 func (cmd *Stop) Run(c *cli.Context) {
 	app := cmd.appReq.GetApplication()
-	cmd.ApplicationStop(app)
+	_, err := cmd.ApplicationStop(app)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
 }
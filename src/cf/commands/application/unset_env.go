@@ -9,6 +9,7 @@ import (
 	"cf/terminal"
 	"errors"
 	"github.com/codegangsta/cli"
+	"strings"
 )
 
 type UnsetEnv struct {
@@ -27,7 +28,12 @@ func NewUnsetEnv(ui terminal.UI, config configuration.Reader, appRepo api.Applic
 }
 
 func (cmd *UnsetEnv) GetRequirements(reqFactory requirements.Factory, c *cli.Context) (reqs []requirements.Requirement, err error) {
-	if len(c.Args()) < 2 {
+	requiredArgs := 2
+	if c.Bool("all") || c.String("keys") != "" {
+		requiredArgs = 1
+	}
+
+	if len(c.Args()) < requiredArgs {
 		err = errors.New("Incorrect Usage")
 		cmd.ui.FailWithUsage(c, "unset-env")
 		return
@@ -43,6 +49,11 @@ func (cmd *UnsetEnv) GetRequirements(reqFactory requirements.Factory, c *cli.Con
 }
 
 func (cmd *UnsetEnv) Run(c *cli.Context) {
+	if c.Bool("all") || c.String("keys") != "" {
+		cmd.unsetEnvVars(c)
+		return
+	}
+
 	varName := c.Args()[1]
 	app := cmd.appReq.GetApplication()
 
@@ -73,3 +84,41 @@ func (cmd *UnsetEnv) Run(c *cli.Context) {
 	cmd.ui.Ok()
 	cmd.ui.Say("TIP: Use '%s' to ensure your env variable changes take effect", terminal.CommandColor(cf.Name()+" push"))
 }
+
+func (cmd *UnsetEnv) unsetEnvVars(c *cli.Context) {
+	app := cmd.appReq.GetApplication()
+
+	description := "all env variables"
+	keys := []string{}
+	if !c.Bool("all") {
+		for _, key := range strings.Split(c.String("keys"), ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		description = "env variables " + strings.Join(keys, ", ")
+	}
+
+	cmd.ui.Say("Removing %s from app %s in org %s / space %s as %s...",
+		terminal.EntityNameColor(description),
+		terminal.EntityNameColor(app.Name),
+		terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+		terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		terminal.EntityNameColor(cmd.config.Username()),
+	)
+
+	envParams := map[string]string{}
+	if !c.Bool("all") {
+		for key, value := range app.EnvironmentVars {
+			envParams[key] = value
+		}
+		for _, key := range keys {
+			if _, ok := envParams[key]; !ok {
+				cmd.ui.Warn("Env variable %s was not set.", key)
+			}
+			delete(envParams, key)
+		}
+	}
+
+	applyEnvChanges(cmd.ui, cmd.appRepo, app, envParams, c.Bool("dry-run"))
+}
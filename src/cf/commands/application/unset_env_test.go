@@ -119,6 +119,54 @@ var _ = Describe("Testing with ginkgo", func() {
 	})
 })
 
+var _ = Describe("unset-env with several variables", func() {
+	var (
+		reqFactory *testreq.FakeReqFactory
+		appRepo    *testapi.FakeApplicationRepository
+	)
+
+	BeforeEach(func() {
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		app.EnvironmentVars = map[string]string{"FOO": "1", "BAR": "2", "BAZ": "3"}
+		reqFactory = &testreq.FakeReqFactory{Application: app, LoginSuccess: true, TargetedSpaceSuccess: true}
+		appRepo = &testapi.FakeApplicationRepository{}
+	})
+
+	It("removes the given keys in a single update", func() {
+		ui := callUnsetEnv([]string{"--keys", "FOO,BAZ,MISSING", "my-app"}, reqFactory, appRepo)
+
+		Expect(appRepo.UpdatedAppGuids).To(Equal([]string{"my-app-guid"}))
+		Expect(*appRepo.UpdateParams.EnvironmentVars).To(Equal(map[string]string{"BAR": "2"}))
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Removing env variables FOO, BAZ, MISSING from app", "my-app"},
+			{"MISSING was not set"},
+			{"- BAZ"},
+			{"- FOO"},
+			{"OK"},
+		})
+	})
+
+	It("removes every variable", func() {
+		callUnsetEnv([]string{"--all", "my-app"}, reqFactory, appRepo)
+
+		Expect(*appRepo.UpdateParams.EnvironmentVars).To(BeEmpty())
+	})
+
+	It("only shows the removed variables in a dry run", func() {
+		ui := callUnsetEnv([]string{"--all", "--dry-run", "my-app"}, reqFactory, appRepo)
+
+		Expect(appRepo.UpdatedAppGuids).To(BeEmpty())
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"- BAR"},
+			{"- BAZ"},
+			{"- FOO"},
+			{"Dry run", "3 removed"},
+		})
+	})
+})
+
 func callUnsetEnv(args []string, reqFactory *testreq.FakeReqFactory, appRepo api.ApplicationRepository) (ui *testterm.FakeUI) {
 	ui = new(testterm.FakeUI)
 	ctxt := testcmd.NewContext("unset-env", args)
@@ -0,0 +1,66 @@
+package application
+
+import (
+	"cf/manifest"
+	"cf/requirements"
+	"cf/terminal"
+	"github.com/codegangsta/cli"
+	"os"
+	"strings"
+)
+
+type ValidateManifest struct {
+	ui           terminal.UI
+	manifestRepo manifest.ManifestRepository
+}
+
+func NewValidateManifest(ui terminal.UI, manifestRepo manifest.ManifestRepository) (cmd *ValidateManifest) {
+	cmd = new(ValidateManifest)
+	cmd.ui = ui
+	cmd.manifestRepo = manifestRepo
+	return
+}
+
+func (cmd *ValidateManifest) GetRequirements(reqFactory requirements.Factory, c *cli.Context) (reqs []requirements.Requirement, err error) {
+	return
+}
+
+func (cmd *ValidateManifest) Run(c *cli.Context) {
+	paths := c.StringSlice("f")
+	if len(paths) == 0 {
+		cwd, err := os.Getwd()
+		if err != nil {
+			cmd.ui.Failed("Could not determine the current working directory!", err)
+			return
+		}
+		paths = []string{cwd}
+	}
+
+	vars, err := manifestVarsFromContext(c)
+	if err != nil {
+		cmd.ui.Failed("Error: %s", err)
+		return
+	}
+
+	m, manifestPath, errs := cmd.manifestRepo.ReadManifest(paths, vars)
+	if manifestPath == "" {
+		manifestPath = strings.Join(paths, ", ")
+	}
+	cmd.ui.Say("Validating manifest file %s...", terminal.EntityNameColor(manifestPath))
+
+	if !errs.Empty() {
+		cmd.ui.Failed("%s", errs)
+		return
+	}
+
+	for _, warning := range m.Warnings {
+		cmd.ui.Warn("%s", warning)
+	}
+	if len(m.Warnings) > 0 && c.Bool("strict") {
+		cmd.ui.Failed("Found %d unknown keys", len(m.Warnings))
+		return
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("Manifest is valid, it describes %d apps", len(m.Applications))
+}
@@ -0,0 +1,94 @@
+package application_test
+
+import (
+	. "cf/commands/application"
+	"cf/manifest"
+	"cf/models"
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	testassert "testhelpers/assert"
+	testcmd "testhelpers/commands"
+	testmanifest "testhelpers/manifest"
+	testreq "testhelpers/requirements"
+	testterm "testhelpers/terminal"
+)
+
+var _ = Describe("validate-manifest command", func() {
+	var manifestRepo *testmanifest.FakeManifestRepository
+
+	BeforeEach(func() {
+		manifestRepo = &testmanifest.FakeManifestRepository{}
+		manifestRepo.ReadManifestReturns.Path = "/my-app/manifest.yml"
+	})
+
+	It("does not need a login or a target", func() {
+		callValidateManifest([]string{}, manifestRepo)
+		Expect(testcmd.CommandDidPassRequirements).To(BeTrue())
+	})
+
+	It("reads the manifest given with -f along with its vars", func() {
+		callValidateManifest([]string{"-f", "/my-app/manifest.yml", "--var", "env=prod"}, manifestRepo)
+
+		Expect(manifestRepo.ReadManifestArgs.Paths).To(Equal([]string{"/my-app/manifest.yml"}))
+		Expect(manifestRepo.ReadManifestArgs.Vars.Values).To(Equal(map[string]string{"env": "prod"}))
+	})
+
+	It("says how many apps a valid manifest describes", func() {
+		manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+			Applications: []models.AppParams{{}, {}},
+		}
+
+		ui := callValidateManifest([]string{}, manifestRepo)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"Validating manifest file", "/my-app/manifest.yml"},
+			{"OK"},
+			{"Manifest is valid", "2 apps"},
+		})
+	})
+
+	It("fails with the errors found in the manifest", func() {
+		manifestRepo.ReadManifestReturns.Errors = manifest.ManifestErrors{
+			errors.New(`manifest.yml:6: app "web": memory must be a string value`),
+		}
+
+		ui := callValidateManifest([]string{}, manifestRepo)
+
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"FAILED"},
+			{"manifest.yml:6", "web", "memory must be a string value"},
+		})
+	})
+
+	It("warns about unknown keys and only fails on them with --strict", func() {
+		manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+			Applications: []models.AppParams{{}},
+			Warnings: manifest.ManifestErrors{
+				errors.New(`manifest.yml:7: app "web": Unknown key 'instance', did you mean 'instances'?`),
+			},
+		}
+
+		ui := callValidateManifest([]string{}, manifestRepo)
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"manifest.yml:7", "Unknown key 'instance'", "did you mean 'instances'?"},
+			{"OK"},
+		})
+
+		ui = callValidateManifest([]string{"--strict"}, manifestRepo)
+		testassert.SliceContains(ui.Outputs, testassert.Lines{
+			{"manifest.yml:7", "Unknown key 'instance'"},
+			{"FAILED"},
+			{"Found 1 unknown keys"},
+		})
+	})
+})
+
+func callValidateManifest(args []string, manifestRepo *testmanifest.FakeManifestRepository) (ui *testterm.FakeUI) {
+	ui = new(testterm.FakeUI)
+	ctxt := testcmd.NewContext("validate-manifest", args)
+
+	cmd := NewValidateManifest(ui, manifestRepo)
+	testcmd.RunCommand(cmd, ctxt, &testreq.FakeReqFactory{})
+	return
+}
@@ -7,16 +7,20 @@ import (
 )
 
 func DefaultFilePath() string {
-	var configDir string
+	return filepath.Join(defaultConfigDir(), "config.json")
+}
+
+func DefaultAppBitsCacheFilePath() string {
+	return filepath.Join(defaultConfigDir(), "app_bits_cache.json")
+}
 
+func defaultConfigDir() string {
 	if os.Getenv("CF_HOME") != "" {
 		cfHome := os.Getenv("CF_HOME")
-		configDir = filepath.Join(cfHome, ".cf")
-	} else {
-		configDir = filepath.Join(userHomeDir(), ".cf")
+		return filepath.Join(cfHome, ".cf")
 	}
 
-	return filepath.Join(configDir, "config.json")
+	return filepath.Join(userHomeDir(), ".cf")
 }
 
 // See: http://stackoverflow.com/questions/7922270/obtain-users-home-directory
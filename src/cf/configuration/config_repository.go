@@ -2,6 +2,8 @@ package configuration
 
 import (
 	"cf/models"
+	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -29,6 +31,7 @@ func NewRepositoryFromPersistor(persistor Persistor, errorHandler func(error)) R
 type Reader interface {
 	ApiEndpoint() string
 	ApiVersion() string
+	IsMinApiVersion(version string) bool
 	AuthorizationEndpoint() string
 	LoggregatorEndpoint() string
 	AccessToken() string
@@ -203,6 +206,40 @@ func (c *configRepository) HasSpace() (hasSpace bool) {
 	return
 }
 
+// IsMinApiVersion tells whether the targeted API is at least version. An API
+// version that is unknown is taken to be the latest.
+func (c *configRepository) IsMinApiVersion(version string) bool {
+	current, ok := parseApiVersion(c.ApiVersion())
+	if !ok {
+		return true
+	}
+	minimum, _ := parseApiVersion(version)
+
+	for index := range minimum {
+		if current[index] != minimum[index] {
+			return current[index] > minimum[index]
+		}
+	}
+	return true
+}
+
+func parseApiVersion(version string) (parts [3]int, ok bool) {
+	fields := strings.Split(version, ".")
+	if len(fields) > len(parts) {
+		return
+	}
+
+	for index, field := range fields {
+		number, err := strconv.Atoi(field)
+		if err != nil {
+			return
+		}
+		parts[index] = number
+	}
+	ok = true
+	return
+}
+
 // SETTERS
 
 func (c *configRepository) ClearSession() {
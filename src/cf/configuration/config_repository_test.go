@@ -90,4 +90,23 @@ var _ = Describe("ConfigurationRepository", func() {
 		Expect(config.UserGuid()).To(BeEmpty())
 		Expect(config.UserEmail()).To(BeEmpty())
 	})
+
+	It("compares the API version with a minimum version", func() {
+		config.SetApiVersion("2.1.0")
+		Expect(config.IsMinApiVersion("2.0.0")).To(BeTrue())
+		Expect(config.IsMinApiVersion("2.1.0")).To(BeTrue())
+		Expect(config.IsMinApiVersion("2.10.0")).To(BeFalse())
+		Expect(config.IsMinApiVersion("3.0.0")).To(BeFalse())
+
+		config.SetApiVersion("2.10.1")
+		Expect(config.IsMinApiVersion("2.2.0")).To(BeTrue())
+	})
+
+	It("takes an unknown API version to be the latest", func() {
+		config.SetApiVersion("")
+		Expect(config.IsMinApiVersion("2.1.0")).To(BeTrue())
+
+		config.SetApiVersion("not-a-version")
+		Expect(config.IsMinApiVersion("2.1.0")).To(BeTrue())
+	})
 })
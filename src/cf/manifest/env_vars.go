@@ -0,0 +1,73 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"generic"
+	"strconv"
+)
+
+// EnvVarValue converts the value of an env var to the string stored by the
+// cloud controller. Numbers and booleans are written the usual way, maps and
+// lists are serialised to JSON.
+func EnvVarValue(value interface{}) (result string, err error) {
+	switch value := value.(type) {
+	case string:
+		result = value
+	case bool:
+		result = strconv.FormatBool(value)
+	case int:
+		result = strconv.Itoa(value)
+	case int64:
+		result = strconv.FormatInt(value, 10)
+	case uint64:
+		result = strconv.FormatUint(value, 10)
+	case float64:
+		result = strconv.FormatFloat(value, 'f', -1, 64)
+	case nil:
+		err = errors.New("should not be null")
+	default:
+		var jsonValue interface{}
+		jsonValue, err = toJSONValue(value)
+		if err != nil {
+			return
+		}
+
+		var bytes []byte
+		bytes, err = json.Marshal(jsonValue)
+		result = string(bytes)
+	}
+	return
+}
+
+func toJSONValue(value interface{}) (result interface{}, err error) {
+	if generic.IsMappable(value) {
+		jsonMap := map[string]interface{}{}
+		generic.Each(generic.NewMap(value), func(key, nestedValue interface{}) {
+			if err != nil {
+				return
+			}
+			jsonMap[fmt.Sprint(key)], err = toJSONValue(nestedValue)
+		})
+		result = jsonMap
+		return
+	}
+
+	switch value := value.(type) {
+	case []interface{}:
+		jsonList := make([]interface{}, len(value))
+		for index, nestedValue := range value {
+			jsonList[index], err = toJSONValue(nestedValue)
+			if err != nil {
+				return
+			}
+		}
+		result = jsonList
+	case nil, string, bool, int, int64, uint64, float64:
+		result = value
+	default:
+		err = errors.New(fmt.Sprintf("has an unsupported value of type %T", value))
+	}
+	return
+}
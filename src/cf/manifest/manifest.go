@@ -13,6 +13,8 @@ import (
 
 type Manifest struct {
 	Applications []models.AppParams
+	Document     generic.Map
+	Warnings     ManifestErrors
 }
 
 func NewEmptyManifest() (m *Manifest) {
@@ -21,13 +23,18 @@ func NewEmptyManifest() (m *Manifest) {
 }
 
 func NewManifest(basePath string, data generic.Map) (m *Manifest, errs ManifestErrors) {
+	return newManifest(basePath, data, sourcePositions{})
+}
+
+func newManifest(basePath string, data generic.Map, positions sourcePositions) (m *Manifest, errs ManifestErrors) {
 	errs = walkManifestLookingForProperties(data)
 	if !errs.Empty() {
 		return
 	}
 
 	m = &Manifest{}
-	m.Applications, errs = mapToAppSet(basePath, data)
+	m.Warnings = unknownKeyWarnings(data, positions)
+	m.Applications, errs = mapToAppSet(basePath, data, positions)
 	return
 }
 
@@ -61,20 +68,27 @@ func walkMapLookingForProperties(value interface{}) (errs ManifestErrors) {
 	return
 }
 
-func mapToAppSet(basePath string, data generic.Map) (appSet []models.AppParams, errs ManifestErrors) {
+func mapToAppSet(basePath string, data generic.Map, positions sourcePositions) (appSet []models.AppParams, errs ManifestErrors) {
 	if data.Has("applications") {
 		appMaps, ok := data.Get("applications").([]interface{})
 		if !ok {
-			errs = append(errs, errors.New("Expected applications to be a list"))
+			errs = append(errs, locatedError{
+				position: positions.find("applications"),
+				err:      errors.New("Expected applications to be a list"),
+			})
 			return
 		}
 
 		// we delete applications so that we may merge top level app params into each app
 		data.Delete("applications")
 
-		for _, appData := range appMaps {
+		for index, appData := range appMaps {
+			appPath := fmt.Sprintf("applications[%d]", index)
 			if !generic.IsMappable(appData) {
-				errs = append(errs, errors.New("Expected application to be a dictionary"))
+				errs = append(errs, locatedError{
+					position: positions.find(appPath),
+					err:      errors.New("Expected application to be a dictionary"),
+				})
 				continue
 			}
 
@@ -82,7 +96,9 @@ func mapToAppSet(basePath string, data generic.Map) (appSet []models.AppParams,
 
 			appParams, appErrs := mapToAppParams(basePath, appMap)
 			if !appErrs.Empty() {
-				errs = append(errs, appErrs)
+				for _, err := range appErrs {
+					errs = append(errs, locateAppError(err, appPath, appMap, positions))
+				}
 				continue
 			}
 
@@ -93,6 +109,19 @@ func mapToAppSet(basePath string, data generic.Map) (appSet []models.AppParams,
 	return
 }
 
+// locateAppError points at the key the error is about, which is either in
+// the app itself or at the top level of the manifest, where apps inherit it.
+func locateAppError(err error, appPath string, appMap generic.Map, positions sourcePositions) error {
+	located := locatedError{err: err, position: positions.find(appPath)}
+	if name, ok := appMap.Get("name").(string); ok {
+		located.appName = name
+	}
+	if fieldErr, ok := err.(fieldError); ok {
+		located.position = positions.find(appPath+"."+fieldErr.key, fieldErr.key, appPath)
+	}
+	return located
+}
+
 func mapToAppParams(basePath string, yamlMap generic.Map) (appParams models.AppParams, errs ManifestErrors) {
 	errs = checkForNulls(yamlMap)
 	if !errs.Empty() {
@@ -111,9 +140,23 @@ func mapToAppParams(basePath string, yamlMap generic.Map) (appParams models.AppP
 	appParams.InstanceCount = intVal(yamlMap, "instances", &errs)
 	appParams.HealthCheckTimeout = intVal(yamlMap, "timeout", &errs)
 	appParams.NoRoute = boolVal(yamlMap, "no-route", &errs)
-	appParams.Services = sliceOrEmptyVal(yamlMap, "services", &errs)
+	appParams.RandomRoute = boolVal(yamlMap, "random-route", &errs)
+	appParams.Services, appParams.ServiceInstances = servicesVal(yamlMap, "services", &errs)
 	appParams.EnvironmentVars = envVarOrEmptyMap(yamlMap, &errs)
 
+	if yamlMap.Has("depends_on") {
+		appParams.DependsOn = sliceOrEmptyVal(yamlMap, "depends_on", &errs)
+	}
+	if yamlMap.Has("hosts") {
+		appParams.Hosts = sliceOrEmptyVal(yamlMap, "hosts", &errs)
+	}
+	if yamlMap.Has("domains") {
+		appParams.Domains = sliceOrEmptyVal(yamlMap, "domains", &errs)
+	}
+	if yamlMap.Has("routes") {
+		appParams.Routes = routesVal(yamlMap, "routes", &errs)
+	}
+
 	if appParams.Path != nil {
 		path := *appParams.Path
 		if filepath.IsAbs(path) {
@@ -133,7 +176,7 @@ func checkForNulls(yamlMap generic.Map) (errs ManifestErrors) {
 			return
 		}
 		if value == nil {
-			errs = append(errs, errors.New(fmt.Sprintf("%s should not be null", key)))
+			errs = append(errs, newFieldError(fmt.Sprint(key), "%s should not be null", key))
 		}
 	})
 
@@ -147,7 +190,7 @@ func stringVal(yamlMap generic.Map, key string, errs *ManifestErrors) *string {
 	}
 	result, ok := val.(string)
 	if !ok {
-		*errs = append(*errs, errors.New(fmt.Sprintf("%s must be a string value", key)))
+		*errs = append(*errs, newFieldError(key, "%s must be a string value", key))
 		return nil
 	}
 	return &result
@@ -164,7 +207,7 @@ func stringOrNullVal(yamlMap generic.Map, key string, errs *ManifestErrors) *str
 		empty := ""
 		return &empty
 	default:
-		*errs = append(*errs, errors.New(fmt.Sprintf("%s must be a string or null value", key)))
+		*errs = append(*errs, newFieldError(key, "%s must be a string or null value", key))
 		return nil
 	}
 }
@@ -174,9 +217,14 @@ func bytesVal(yamlMap generic.Map, key string, errs *ManifestErrors) *uint64 {
 	if yamlVal == nil {
 		return nil
 	}
-	value, err := formatters.ToMegabytes(yamlVal.(string))
+	stringVal, ok := yamlVal.(string)
+	if !ok {
+		*errs = append(*errs, newFieldError(key, "%s must be a string value", key))
+		return nil
+	}
+	value, err := formatters.ToMegabytes(stringVal)
 	if err != nil {
-		*errs = append(*errs, errors.New(fmt.Sprintf("Unexpected value for %s :\n%s", key, err.Error())))
+		*errs = append(*errs, newFieldError(key, "Unexpected value for %s :\n%s", key, err.Error()))
 		return nil
 	}
 	return &value
@@ -196,11 +244,11 @@ func intVal(yamlMap generic.Map, key string, errs *ManifestErrors) *int {
 	case nil:
 		return nil
 	default:
-		err = errors.New(fmt.Sprintf("Expected %s to be a number.", key))
+		err = errors.New("not a number")
 	}
 
 	if err != nil {
-		*errs = append(*errs, err)
+		*errs = append(*errs, newFieldError(key, "Expected %s to be a number.", key))
 		return nil
 	}
 
@@ -217,7 +265,7 @@ func boolVal(yamlMap generic.Map, key string, errs *ManifestErrors) *bool {
 		boolVal := val == "true"
 		return &boolVal
 	default:
-		*errs = append(*errs, errors.New(fmt.Sprintf("Expected %s to be a boolean.", key)))
+		*errs = append(*errs, newFieldError(key, "Expected %s to be a boolean.", key))
 		return nil
 	}
 }
@@ -232,20 +280,18 @@ func sliceOrEmptyVal(yamlMap generic.Map, key string, errs *ManifestErrors) *[]s
 		err         error
 	)
 
-	errMsg := fmt.Sprintf("Expected %s to be a list of strings.", key)
-
 	switch input := yamlMap.Get(key).(type) {
 	case []interface{}:
 		for _, value := range input {
 			stringValue, ok := value.(string)
 			if !ok {
-				err = errors.New(errMsg)
+				err = newFieldError(key, "Expected %s to be a list of strings.", key)
 				break
 			}
 			stringSlice = append(stringSlice, stringValue)
 		}
 	default:
-		err = errors.New(errMsg)
+		err = newFieldError(key, "Expected %s to be a list of strings.", key)
 	}
 
 	if err != nil {
@@ -256,6 +302,125 @@ func sliceOrEmptyVal(yamlMap generic.Map, key string, errs *ManifestErrors) *[]s
 	return &stringSlice
 }
 
+// servicesVal reads the names of the services to bind. A service can also be
+// a map declaring the instance to create when it is missing, with a label and
+// a plan, or with the credentials of a user-provided service.
+func servicesVal(yamlMap generic.Map, key string, errs *ManifestErrors) (names *[]string, instances *[]models.ServiceInstanceParams) {
+	if !yamlMap.Has(key) {
+		return new([]string), nil
+	}
+
+	items, ok := yamlMap.Get(key).([]interface{})
+	if !ok {
+		*errs = append(*errs, newFieldError(key, "Expected %s to be a list of names or maps with a name.", key))
+		return
+	}
+
+	serviceNames := []string{}
+	serviceInstances := []models.ServiceInstanceParams{}
+	for index, item := range items {
+		itemKey := fmt.Sprintf("%s[%d]", key, index)
+
+		if name, ok := item.(string); ok {
+			serviceNames = append(serviceNames, name)
+			continue
+		}
+
+		if !generic.IsMappable(item) {
+			*errs = append(*errs, newFieldError(key, "Expected %s to be a list of names or maps with a name.", key))
+			return nil, nil
+		}
+
+		instance, err := serviceInstanceParams(generic.NewMap(item), itemKey)
+		if err != nil {
+			*errs = append(*errs, err)
+			return nil, nil
+		}
+		serviceNames = append(serviceNames, instance.Name)
+		serviceInstances = append(serviceInstances, instance)
+	}
+
+	names = &serviceNames
+	if len(serviceInstances) > 0 {
+		instances = &serviceInstances
+	}
+	return
+}
+
+func serviceInstanceParams(serviceMap generic.Map, key string) (instance models.ServiceInstanceParams, err error) {
+	fields := []string{"name", "label", "provider", "plan"}
+	values := []*string{&instance.Name, &instance.Label, &instance.Provider, &instance.Plan}
+	for index, field := range fields {
+		if !serviceMap.Has(field) {
+			continue
+		}
+		stringValue, ok := serviceMap.Get(field).(string)
+		if !ok {
+			err = newFieldError(key+"."+field, "Expected %s of service to be a string.", field)
+			return
+		}
+		*values[index] = stringValue
+	}
+
+	if instance.Name == "" {
+		err = newFieldError(key, "Expected each of the services to have a name.")
+		return
+	}
+
+	if serviceMap.Has("credentials") {
+		credentials := serviceMap.Get("credentials")
+		if !generic.IsMappable(credentials) {
+			err = newFieldError(key+".credentials", "Expected credentials of service %s to be a set of key => value.", instance.Name)
+			return
+		}
+
+		instance.Credentials = map[string]string{}
+		generic.Each(generic.NewMap(credentials), func(name, value interface{}) {
+			if err != nil {
+				return
+			}
+			var credential string
+			credential, err = EnvVarValue(value)
+			if err != nil {
+				err = newFieldError(key+".credentials", "credential '%s' of service %s %s", name, instance.Name, err)
+				return
+			}
+			instance.Credentials[fmt.Sprint(name)] = credential
+		})
+		return
+	}
+
+	if instance.Label == "" || instance.Plan == "" {
+		err = newFieldError(key, "Expected service %s to have a label and a plan, or credentials.", instance.Name)
+	}
+	return
+}
+
+// routesVal reads a list of routes, written either as the URL itself or as
+// a map with the URL under route.
+func routesVal(yamlMap generic.Map, key string, errs *ManifestErrors) *[]string {
+	items, ok := yamlMap.Get(key).([]interface{})
+	if !ok {
+		*errs = append(*errs, newFieldError(key, "Expected %s to be a list of routes.", key))
+		return nil
+	}
+
+	routes := []string{}
+	for index, item := range items {
+		if generic.IsMappable(item) {
+			item = generic.NewMap(item).Get("route")
+		}
+
+		route, ok := item.(string)
+		if !ok || route == "" {
+			*errs = append(*errs, newFieldError(fmt.Sprintf("%s[%d]", key, index), "Expected each of the %s to be a URL or a map with a route.", key))
+			return nil
+		}
+		routes = append(routes, route)
+	}
+	return &routes
+}
+
 func envVarOrEmptyMap(yamlMap generic.Map, errs *ManifestErrors) *map[string]string {
 	key := "env"
 	switch envVars := yamlMap.Get(key).(type) {
@@ -268,17 +433,23 @@ func envVarOrEmptyMap(yamlMap generic.Map, errs *ManifestErrors) *map[string]str
 	case generic.Map:
 		merrs := validateEnvVars(envVars)
 		if merrs != nil {
-			*errs = append(*errs, merrs)
+			*errs = append(*errs, merrs...)
 			return nil
 		}
 
 		result := make(map[string]string, envVars.Count())
 		generic.Each(envVars, func(key, value interface{}) {
-			result[key.(string)] = value.(string)
+			name := fmt.Sprint(key)
+			envValue, err := EnvVarValue(value)
+			if err != nil {
+				*errs = append(*errs, newFieldError("env."+name, "env var '%s' %s", name, err))
+				return
+			}
+			result[name] = envValue
 		})
 		return &result
 	default:
-		*errs = append(*errs, errors.New(fmt.Sprintf("Expected %s to be a set of key => value.", key)))
+		*errs = append(*errs, newFieldError(key, "Expected %s to be a set of key => value.", key))
 		return nil
 	}
 }
@@ -286,7 +457,7 @@ func envVarOrEmptyMap(yamlMap generic.Map, errs *ManifestErrors) *map[string]str
 func validateEnvVars(input generic.Map) (errs ManifestErrors) {
 	generic.Each(input, func(key, value interface{}) {
 		if value == nil {
-			errs = append(errs, errors.New(fmt.Sprintf("env var '%s' should not be null", key)))
+			errs = append(errs, newFieldError(fmt.Sprintf("env.%s", key), "env var '%s' should not be null", key))
 		}
 	})
 	return
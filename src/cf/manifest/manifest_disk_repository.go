@@ -2,16 +2,18 @@ package manifest
 
 import (
 	"errors"
+	"fmt"
 	"generic"
 	"github.com/cloudfoundry/gamble"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type ManifestRepository interface {
-	ReadManifest(string) (manifest *Manifest, path string, errors ManifestErrors)
+	ReadManifest(inputPaths []string, vars ManifestVars) (manifest *Manifest, path string, errors ManifestErrors)
 }
 
 type ManifestDiskRepository struct{}
@@ -20,39 +22,74 @@ func NewManifestDiskRepository() (repo ManifestRepository) {
 	return ManifestDiskRepository{}
 }
 
-func (repo ManifestDiskRepository) ReadManifest(inputPath string) (m *Manifest, manifestPath string, errs ManifestErrors) {
+// ReadManifest merges the manifests at inputPaths in order, so that each of
+// them overlays the ones before it.
+func (repo ManifestDiskRepository) ReadManifest(inputPaths []string, vars ManifestVars) (m *Manifest, manifestPath string, errs ManifestErrors) {
 	m = NewEmptyManifest()
 
-	basePath, fileName, err := repo.manifestPath(inputPath)
-	if err != nil {
-		errs = append(errs, errors.New("Error finding manifest: "+err.Error()))
-		return
+	var (
+		basePath      string
+		manifestPaths []string
+		mapp          = generic.NewMap()
+		positions     = sourcePositions{}
+	)
+	for _, inputPath := range inputPaths {
+		dir, fileName, err := repo.manifestPath(inputPath)
+		if err != nil {
+			errs = append(errs, errors.New("Error finding manifest: "+err.Error()))
+			return
+		}
+
+		path := filepath.Join(dir, fileName)
+		if basePath == "" {
+			basePath = dir
+		}
+		manifestPaths = append(manifestPaths, path)
+
+		fileMap, filePositions, err := repo.readAllYAMLFiles(path)
+		if err != nil {
+			manifestPath = strings.Join(manifestPaths, ", ")
+			errs = append(errs, err)
+			return
+		}
+
+		// the lists of a single manifest are kept as they are
+		if len(manifestPaths) == 1 {
+			mapp, positions = fileMap, filePositions
+			continue
+		}
+		mapp, positions = mergeManifestMaps(mapp, positions, fileMap, filePositions)
 	}
+	manifestPath = strings.Join(manifestPaths, ", ")
 
-	manifestPath = filepath.Join(basePath, fileName)
+	values, errs := vars.resolve()
+	if !errs.Empty() {
+		return
+	}
 
-	mapp, err := repo.readAllYAMLFiles(manifestPath)
-	if err != nil {
-		errs = append(errs, err)
+	document, errs := interpolateVars(mapp, values)
+	if !errs.Empty() {
 		return
 	}
 
-	m, errs = NewManifest(basePath, mapp)
+	// NewManifest consumes the map it is given, so it gets its own copy
+	m, errs = newManifest(basePath, copyDocument(document), positions)
 	if !errs.Empty() {
 		return
 	}
 
+	m.Document = document
 	return
 }
 
-func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap generic.Map, err error) {
+func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap generic.Map, positions sourcePositions, err error) {
 	file, err := os.Open(filepath.Clean(path))
 	if err != nil {
 		return
 	}
 	defer file.Close()
 
-	mapp, err := parseManifest(file)
+	mapp, positions, err := parseManifest(path, file)
 	if err != nil {
 		return
 	}
@@ -64,7 +101,7 @@ func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap gene
 
 	inheritedPath, ok := mapp.Get("inherit").(string)
 	if !ok {
-		err = errors.New("invalid inherit path in manifest")
+		err = errors.New(fmt.Sprintf("%s: invalid inherit path in manifest", positions.find("inherit")))
 		return
 	}
 
@@ -72,16 +109,26 @@ func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap gene
 		inheritedPath = filepath.Join(filepath.Dir(path), inheritedPath)
 	}
 
-	inheritedMap, err := repo.readAllYAMLFiles(inheritedPath)
+	inheritedMap, inheritedPositions, err := repo.readAllYAMLFiles(inheritedPath)
 	if err != nil {
 		return
 	}
 
+	// unlike the manifests given with -f, the lists of an inherited manifest
+	// are appended to
 	mergedMap = generic.DeepMerge(inheritedMap, mapp)
+	positions = mergeSourcePositions(mergedMap, inheritedPositions, mapp, positions, appendedListIndex)
+	return
+}
+
+// mergeManifestMaps merges the apps of the manifests by name
+func mergeManifestMaps(base generic.Map, basePositions sourcePositions, overlay generic.Map, overlayPositions sourcePositions) (merged generic.Map, positions sourcePositions) {
+	merged = generic.DeepMergeByKey("name", base, overlay)
+	positions = mergeSourcePositions(merged, basePositions, overlay, overlayPositions, listIndexByName)
 	return
 }
 
-func parseManifest(file io.Reader) (yamlMap generic.Map, err error) {
+func parseManifest(path string, file io.Reader) (yamlMap generic.Map, positions sourcePositions, err error) {
 	yamlBytes, err := ioutil.ReadAll(file)
 	if err != nil {
 		return
@@ -89,15 +136,17 @@ func parseManifest(file io.Reader) (yamlMap generic.Map, err error) {
 
 	document, err := gamble.Parse(string(yamlBytes))
 	if err != nil {
+		err = errors.New(fmt.Sprintf("%s: %s", path, err))
 		return
 	}
 
 	if !generic.IsMappable(document) {
-		err = errors.New("Invalid manifest. Expected a map")
+		err = errors.New(fmt.Sprintf("%s: Invalid manifest. Expected a map", path))
 		return
 	}
 
 	yamlMap = generic.NewMap(document)
+	positions = scanSourcePositions(path, string(yamlBytes))
 	return
 }
 
@@ -17,7 +17,7 @@ var _ = Describe("ManifestDiskRepository", func() {
 
 	Describe("given a directory containing a file called 'manifest.yml", func() {
 		It("reads that file", func() {
-			m, path, errs := repo.ReadManifest("../../fixtures/manifests")
+			m, path, errs := repo.ReadManifest([]string{"../../fixtures/manifests"}, ManifestVars{})
 
 			Expect(errs).To(BeEmpty())
 			Expect(path).To(Equal(filepath.Clean("../../fixtures/manifests/manifest.yml")))
@@ -27,7 +27,7 @@ var _ = Describe("ManifestDiskRepository", func() {
 
 	Describe("given a directory that doesn't contain a file called 'manifest.yml", func() {
 		It("returns an error", func() {
-			_, path, errs := repo.ReadManifest("../../fixtures")
+			_, path, errs := repo.ReadManifest([]string{"../../fixtures"}, ManifestVars{})
 
 			Expect(errs).NotTo(BeEmpty())
 			Expect(path).To(BeEmpty())
@@ -36,7 +36,7 @@ var _ = Describe("ManifestDiskRepository", func() {
 
 	Describe("given a path to a file", func() {
 		It("reads the file at that path", func() {
-			m, path, errs := repo.ReadManifest("../../fixtures/manifests/different-manifest.yml")
+			m, path, errs := repo.ReadManifest([]string{"../../fixtures/manifests/different-manifest.yml"}, ManifestVars{})
 
 			Expect(errs).To(BeEmpty())
 			Expect(path).To(Equal(filepath.Clean("../../fixtures/manifests/different-manifest.yml")))
@@ -44,7 +44,7 @@ var _ = Describe("ManifestDiskRepository", func() {
 		})
 
 		It("passes the base directory to the manifest file", func() {
-			m, _, errs := repo.ReadManifest("../../fixtures/manifests/different-manifest.yml")
+			m, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/different-manifest.yml"}, ManifestVars{})
 
 			Expect(errs).To(BeEmpty())
 			Expect(len(m.Applications)).To(Equal(1))
@@ -60,31 +60,31 @@ var _ = Describe("ManifestDiskRepository", func() {
 
 	Describe("given a path to a file that doesn't exist", func() {
 		It("returns an error", func() {
-			_, _, errs := repo.ReadManifest("some/path/that/doesnt/exist/manifest.yml")
+			_, _, errs := repo.ReadManifest([]string{"some/path/that/doesnt/exist/manifest.yml"}, ManifestVars{})
 			Expect(errs).NotTo(BeEmpty())
 		})
 
 		It("returns empty string for the manifest path", func() {
-			_, path, _ := repo.ReadManifest("some/path/that/doesnt/exist/manifest.yml")
+			_, path, _ := repo.ReadManifest([]string{"some/path/that/doesnt/exist/manifest.yml"}, ManifestVars{})
 			Expect(path).To(Equal(""))
 		})
 	})
 
 	Describe("when the manifest is not valid", func() {
 		It("returns an error", func() {
-			_, _, errs := repo.ReadManifest("../../fixtures/manifests/empty-manifest.yml")
+			_, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/empty-manifest.yml"}, ManifestVars{})
 			Expect(errs).NotTo(BeEmpty())
 		})
 
 		It("returns the path to the manifest", func() {
 			inputPath := filepath.Clean("../../fixtures/manifests/empty-manifest.yml")
-			_, path, _ := repo.ReadManifest(inputPath)
+			_, path, _ := repo.ReadManifest([]string{inputPath}, ManifestVars{})
 			Expect(path).To(Equal(inputPath))
 		})
 	})
 
 	It("converts nested maps to generic maps", func() {
-		m, _, errs := repo.ReadManifest("../../fixtures/manifests/different-manifest.yml")
+		m, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/different-manifest.yml"}, ManifestVars{})
 
 		Expect(errs).To(BeEmpty())
 		Expect(*m.Applications[0].EnvironmentVars).To(Equal(map[string]string{
@@ -93,7 +93,7 @@ var _ = Describe("ManifestDiskRepository", func() {
 	})
 
 	It("merges manifests with their 'inherited' manifests", func() {
-		m, _, errs := repo.ReadManifest("../../fixtures/manifests/inherited-manifest.yml")
+		m, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/inherited-manifest.yml"}, ManifestVars{})
 		Expect(errs).To(BeEmpty())
 		Expect(*m.Applications[0].Name).To(Equal("base-app"))
 		Expect(*m.Applications[0].Services).To(Equal([]string{"base-service"}))
@@ -111,4 +111,165 @@ var _ = Describe("ManifestDiskRepository", func() {
 		services := *m.Applications[1].Services
 		Expect(services).To(Equal([]string{"base-service", "foo-service"}))
 	})
+
+	It("appends the lists of an inherited manifest instead of merging them by name", func() {
+		m, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/inherit/child.yml"}, ManifestVars{})
+
+		Expect(errs).To(BeEmpty())
+		Expect(len(m.Applications)).To(Equal(2))
+		Expect(*m.Applications[0].Name).To(Equal("web"))
+		Expect(*m.Applications[0].Memory).To(Equal(uint64(256)))
+		Expect(*m.Applications[1].Name).To(Equal("web"))
+		Expect(*m.Applications[1].Memory).To(Equal(uint64(512)))
+		Expect(*m.Applications[1].Services).To(Equal([]string{"db", "db", "cache"}))
+	})
+
+	It("reports errors at the line of the inheriting manifest they come from", func() {
+		_, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/inherit/bad-child.yml"}, ManifestVars{})
+
+		Expect(errs.Error()).To(Equal(
+			`../../fixtures/manifests/inherit/bad-child.yml:5: app "worker": Expected timeout to be a number.
+`))
+	})
+})
+
+var _ = Describe("reading several manifests", func() {
+	var repo ManifestRepository
+
+	BeforeEach(func() {
+		repo = NewManifestDiskRepository()
+	})
+
+	It("merges them in order, matching apps by name", func() {
+		m, path, errs := repo.ReadManifest([]string{
+			"../../fixtures/manifests/overlays/base.yml",
+			"../../fixtures/manifests/overlays/prod.yml",
+		}, ManifestVars{})
+
+		Expect(errs).To(BeEmpty())
+		Expect(path).To(Equal(filepath.Clean("../../fixtures/manifests/overlays/base.yml") + ", " +
+			filepath.Clean("../../fixtures/manifests/overlays/prod.yml")))
+		Expect(len(m.Applications)).To(Equal(2))
+
+		web := m.Applications[0]
+		Expect(*web.Name).To(Equal("web"))
+		Expect(*web.Memory).To(Equal(uint64(1024)))
+		Expect(*web.InstanceCount).To(Equal(4))
+		Expect(*web.Services).To(Equal([]string{"db", "cache"}))
+		Expect(*web.EnvironmentVars).To(Equal(map[string]string{"RAILS_ENV": "production"}))
+
+		Expect(*m.Applications[1].Name).To(Equal("admin"))
+		Expect(*m.Applications[1].Memory).To(Equal(uint64(1024)))
+	})
+
+	It("keeps the merged manifest as its document", func() {
+		m, _, errs := repo.ReadManifest([]string{
+			"../../fixtures/manifests/overlays/base.yml",
+			"../../fixtures/manifests/overlays/prod.yml",
+		}, ManifestVars{})
+
+		Expect(errs).To(BeEmpty())
+		Expect(RenderYAML(m.Document)).NotTo(ContainSubstring("prune"))
+		Expect(RenderYAML(m.Document)).NotTo(ContainSubstring("worker"))
+	})
+
+	It("reports errors at the line of the overlay they come from", func() {
+		_, _, errs := repo.ReadManifest([]string{
+			"../../fixtures/manifests/overlays/base.yml",
+			"../../fixtures/manifests/overlays/prod.yml",
+			"../../fixtures/manifests/overlays/bad-overlay.yml",
+		}, ManifestVars{})
+
+		Expect(errs.Error()).To(Equal(
+			`../../fixtures/manifests/overlays/bad-overlay.yml:4: app "admin": Expected timeout to be a number.
+`))
+	})
+})
+
+var _ = Describe("reading a manifest with ((variables))", func() {
+	var repo ManifestRepository
+
+	BeforeEach(func() {
+		repo = NewManifestDiskRepository()
+	})
+
+	It("fills in values from vars files and vars given directly", func() {
+		m, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/manifest-with-vars.yml"}, ManifestVars{
+			Files:  []string{"../../fixtures/manifests/vars.yml"},
+			Values: map[string]string{"env": "production", "log_level": "debug"},
+		})
+
+		Expect(errs).To(BeEmpty())
+		Expect(*m.Applications[0].Name).To(Equal("my-app"))
+		Expect(*m.Applications[0].Memory).To(Equal(uint64(256)))
+		Expect(*m.Applications[0].Host).To(Equal("my-app-production"))
+		Expect(*m.Applications[0].EnvironmentVars).To(Equal(map[string]string{
+			"DATABASE_URL": "postgres://db.example.com/my-app",
+			"LOG_LEVEL":    "debug",
+		}))
+	})
+
+	It("reports every unresolved variable with its path", func() {
+		_, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/manifest-with-vars.yml"}, ManifestVars{
+			Values: map[string]string{"app_name": "my-app", "memory": "256M"},
+		})
+
+		Expect(len(errs)).To(Equal(3))
+		Expect(errs.Error()).To(ContainSubstring("Unresolved variable ((database_url)) at applications[0].env.DATABASE_URL"))
+		Expect(errs.Error()).To(ContainSubstring("Unresolved variable ((log_level)) at applications[0].env.LOG_LEVEL"))
+		Expect(errs.Error()).To(ContainSubstring("Unresolved variable ((env)) at applications[0].host"))
+	})
+
+	It("returns an error when a vars file cannot be read", func() {
+		_, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/manifest-with-vars.yml"}, ManifestVars{
+			Files: []string{"../../fixtures/manifests/no-such-vars.yml"},
+		})
+
+		Expect(errs).NotTo(BeEmpty())
+		Expect(errs.Error()).To(ContainSubstring("Error reading vars file"))
+	})
+
+	It("keeps the interpolated document so it can be printed", func() {
+		m, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/manifest-with-vars.yml"}, ManifestVars{
+			Files:  []string{"../../fixtures/manifests/vars.yml"},
+			Values: map[string]string{"log_level": "debug"},
+		})
+
+		Expect(errs).To(BeEmpty())
+		Expect(RenderYAML(m.Document)).To(Equal(`---
+applications:
+- env:
+    DATABASE_URL: "postgres://db.example.com/my-app"
+    LOG_LEVEL: debug
+  host: my-app-staging
+  memory: 256M
+  name: my-app
+`))
+	})
+})
+
+var _ = Describe("reading an invalid manifest", func() {
+	It("says which file, line and app every error comes from", func() {
+		repo := NewManifestDiskRepository()
+		_, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/invalid-manifest.yml"}, ManifestVars{})
+
+		Expect(errs.Error()).To(Equal(
+			`../../fixtures/manifests/invalid-manifest.yml:6: app "web": Unexpected value for memory :
+Could not parse byte quantity 'lots'
+../../fixtures/manifests/invalid-manifest.yml:13: app "worker": Expected timeout to be a number.
+../../fixtures/manifests/invalid-manifest.yml:14: app "worker": Expected services to be a list of names or maps with a name.
+`))
+	})
+
+	It("warns about unknown keys and suggests the key that was probably meant", func() {
+		repo := NewManifestDiskRepository()
+		m, _, errs := repo.ReadManifest([]string{"../../fixtures/manifests/manifest-with-unknown-keys.yml"}, ManifestVars{})
+
+		Expect(errs).To(BeEmpty())
+		Expect(m.Warnings.Error()).To(Equal(
+			`../../fixtures/manifests/manifest-with-unknown-keys.yml:2: Unknown key 'buildpacks', did you mean 'buildpack'?
+../../fixtures/manifests/manifest-with-unknown-keys.yml:8: app "my-app": Unknown key 'instance', did you mean 'instances'?
+../../fixtures/manifests/manifest-with-unknown-keys.yml:9: app "my-app": Unknown key 'wibble'
+`))
+	})
 })
@@ -20,3 +20,34 @@ func (errs ManifestErrors) Error() (errorMessage string) {
 func (errs ManifestErrors) String() string {
 	return errs.Error()
 }
+
+// fieldError is an error about the value of a single key of an app, so that
+// it can be reported with the line that key is on.
+type fieldError struct {
+	key     string
+	message string
+}
+
+func newFieldError(key, message string, args ...interface{}) error {
+	return fieldError{key: key, message: fmt.Sprintf(message, args...)}
+}
+
+func (err fieldError) Error() string {
+	return err.message
+}
+
+type locatedError struct {
+	position SourcePosition
+	appName  string
+	err      error
+}
+
+func (err locatedError) Error() (message string) {
+	if err.position.IsKnown() {
+		message = err.position.String() + ": "
+	}
+	if err.appName != "" {
+		message += fmt.Sprintf("app \"%s\": ", err.appName)
+	}
+	return message + err.err.Error()
+}
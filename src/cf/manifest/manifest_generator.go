@@ -0,0 +1,86 @@
+package manifest
+
+import (
+	"cf/models"
+	"fmt"
+	"generic"
+)
+
+// GenerateManifest describes apps with the keys push reads them from, so
+// that pushing the manifest back does not change them.
+func GenerateManifest(apps []models.AppParams) (document generic.Map) {
+	appMaps := []interface{}{}
+	for _, app := range apps {
+		appMaps = append(appMaps, appParamsToMap(app))
+	}
+
+	document = generic.NewMap()
+	document.Set("applications", appMaps)
+	return
+}
+
+func appParamsToMap(app models.AppParams) (appMap generic.Map) {
+	appMap = generic.NewMap()
+
+	setString := func(key string, value *string) {
+		if value != nil && *value != "" {
+			appMap.Set(key, *value)
+		}
+	}
+	setMegabytes := func(key string, value *uint64) {
+		if value != nil && *value != 0 {
+			appMap.Set(key, fmt.Sprintf("%dM", *value))
+		}
+	}
+	setList := func(key string, values *[]string) {
+		if values != nil && len(*values) > 0 {
+			items := []interface{}{}
+			for _, value := range *values {
+				items = append(items, value)
+			}
+			appMap.Set(key, items)
+		}
+	}
+
+	setString("name", app.Name)
+	setMegabytes("memory", app.Memory)
+	setMegabytes("disk_quota", app.DiskQuota)
+	if app.InstanceCount != nil && *app.InstanceCount != 0 {
+		appMap.Set("instances", *app.InstanceCount)
+	}
+	setString("buildpack", app.BuildpackUrl)
+	setString("command", app.Command)
+	setString("stack", app.StackName)
+	if app.HealthCheckTimeout != nil && *app.HealthCheckTimeout != 0 {
+		appMap.Set("timeout", *app.HealthCheckTimeout)
+	}
+
+	setString("host", app.Host)
+	setString("domain", app.Domain)
+	setList("hosts", app.Hosts)
+	setList("domains", app.Domains)
+	if app.Routes != nil && len(*app.Routes) > 0 {
+		routes := []interface{}{}
+		for _, route := range *app.Routes {
+			routes = append(routes, map[string]interface{}{"route": route})
+		}
+		appMap.Set("routes", routes)
+	}
+	if app.NoRoute != nil && *app.NoRoute {
+		appMap.Set("no-route", true)
+	}
+	if app.RandomRoute != nil && *app.RandomRoute {
+		appMap.Set("random-route", true)
+	}
+
+	setList("services", app.Services)
+
+	if app.EnvironmentVars != nil && len(*app.EnvironmentVars) > 0 {
+		env := generic.NewMap()
+		for key, value := range *app.EnvironmentVars {
+			env.Set(key, value)
+		}
+		appMap.Set("env", env)
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package manifest_test
+
+import (
+	. "cf/manifest"
+	"cf/models"
+	"fileutils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"path/filepath"
+)
+
+var _ = Describe("generating a manifest", func() {
+	It("writes a manifest that reads back as the same app", func() {
+		name := "my-app"
+		memory := uint64(256)
+		diskQuota := uint64(1024)
+		instances := 3
+		buildpack := "https://github.com/cloudfoundry/ruby-buildpack.git"
+		command := `bundle exec rackup -p $PORT`
+		stack := "lucid64"
+		host := "my-app-host"
+		domain := "example.com"
+		services := []string{"my-db", "my-queue"}
+		env := map[string]string{"RAILS_ENV": "production", "WORKERS": "4", "DEBUG": "false"}
+
+		app := models.AppParams{
+			Name:            &name,
+			Memory:          &memory,
+			DiskQuota:       &diskQuota,
+			InstanceCount:   &instances,
+			BuildpackUrl:    &buildpack,
+			Command:         &command,
+			StackName:       &stack,
+			Host:            &host,
+			Domain:          &domain,
+			Services:        &services,
+			EnvironmentVars: &env,
+		}
+
+		yaml := RenderYAML(GenerateManifest([]models.AppParams{app}))
+		Expect(yaml).To(Equal(`---
+applications:
+- buildpack: "https://github.com/cloudfoundry/ruby-buildpack.git"
+  command: "bundle exec rackup -p $PORT"
+  disk_quota: 1024M
+  domain: example.com
+  env:
+    DEBUG: "false"
+    RAILS_ENV: production
+    WORKERS: "4"
+  host: my-app-host
+  instances: 3
+  memory: 256M
+  name: my-app
+  services:
+  - my-db
+  - my-queue
+  stack: lucid64
+`))
+
+		fileutils.TempDir("manifest_generator_test", func(dir string, err error) {
+			Expect(err).NotTo(HaveOccurred())
+
+			path := filepath.Join(dir, "manifest.yml")
+			err = ioutil.WriteFile(path, []byte(yaml), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			m, _, errs := NewManifestDiskRepository().ReadManifest([]string{path}, ManifestVars{})
+			Expect(errs).To(BeEmpty())
+			Expect(m.Warnings).To(BeEmpty())
+			Expect(m.Applications).To(HaveLen(1))
+
+			readApp := m.Applications[0]
+			Expect(*readApp.Name).To(Equal(name))
+			Expect(*readApp.Memory).To(Equal(memory))
+			Expect(*readApp.DiskQuota).To(Equal(diskQuota))
+			Expect(*readApp.InstanceCount).To(Equal(instances))
+			Expect(*readApp.BuildpackUrl).To(Equal(buildpack))
+			Expect(*readApp.Command).To(Equal(command))
+			Expect(*readApp.StackName).To(Equal(stack))
+			Expect(*readApp.Host).To(Equal(host))
+			Expect(*readApp.Domain).To(Equal(domain))
+			Expect(*readApp.Services).To(Equal(services))
+			Expect(*readApp.EnvironmentVars).To(Equal(env))
+		})
+	})
+
+	It("marks apps without routes as workers", func() {
+		name := "my-worker"
+		noRoute := true
+		services := []string{}
+
+		yaml := RenderYAML(GenerateManifest([]models.AppParams{{Name: &name, NoRoute: &noRoute, Services: &services}}))
+		Expect(yaml).To(Equal("---\napplications:\n- name: my-worker\n  no-route: true\n"))
+	})
+})
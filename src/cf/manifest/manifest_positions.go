@@ -0,0 +1,215 @@
+package manifest
+
+import (
+	"fmt"
+	"generic"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type SourcePosition struct {
+	File string
+	Line int
+}
+
+func (pos SourcePosition) IsKnown() bool {
+	return pos.Line > 0
+}
+
+func (pos SourcePosition) String() string {
+	return fmt.Sprintf("%s:%d", pos.File, pos.Line)
+}
+
+// sourcePositions maps paths in a manifest document, written the way
+// interpolateVars reports them (e.g. applications[0].env.FOO), to the line
+// they come from.
+type sourcePositions map[string]SourcePosition
+
+func (positions sourcePositions) find(paths ...string) SourcePosition {
+	for _, path := range paths {
+		if pos, found := positions[path]; found {
+			return pos
+		}
+	}
+	return SourcePosition{}
+}
+
+const (
+	yamlKeyFrame = iota
+	yamlListFrame
+	yamlItemFrame
+	yamlBlockScalarFrame
+)
+
+type yamlFrame struct {
+	kind   int
+	indent int
+	path   string
+	items  int
+}
+
+func (frame yamlFrame) contains(indent int, isListItem bool) bool {
+	switch frame.kind {
+	case yamlKeyFrame:
+		return indent > frame.indent || (indent == frame.indent && isListItem)
+	case yamlListFrame:
+		return indent == frame.indent && isListItem
+	case yamlItemFrame:
+		return indent >= frame.indent
+	default:
+		return indent > frame.indent
+	}
+}
+
+var yamlKeyRegex = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s#'"\-\[{][^:#]*?)\s*:(\s+(.*))?$`)
+
+// scanSourcePositions finds the line of every key and list item in a block
+// style YAML document. gamble only gives us the values, so the source is
+// scanned separately; flow style collections are not looked into.
+func scanSourcePositions(file, source string) (positions sourcePositions) {
+	positions = sourcePositions{}
+	root := yamlFrame{kind: yamlItemFrame, indent: 0}
+	stack := []yamlFrame{root}
+
+	for index, line := range strings.Split(source, "\n") {
+		content := strings.TrimLeft(line, " ")
+		indent := len(line) - len(content)
+		content = strings.TrimRight(content, " \t\r")
+
+		if content == "" || strings.HasPrefix(content, "#") || content == "---" || content == "..." {
+			continue
+		}
+
+		pos := SourcePosition{File: file, Line: index + 1}
+		for content != "" {
+			isListItem := content == "-" || strings.HasPrefix(content, "- ")
+			for len(stack) > 1 && !stack[len(stack)-1].contains(indent, isListItem) {
+				stack = stack[:len(stack)-1]
+			}
+			top := &stack[len(stack)-1]
+
+			if top.kind == yamlBlockScalarFrame {
+				break
+			}
+
+			if isListItem {
+				if top.kind != yamlListFrame || top.indent != indent {
+					stack = append(stack, yamlFrame{kind: yamlListFrame, indent: indent, path: top.path})
+					top = &stack[len(stack)-1]
+				}
+				path := fmt.Sprintf("%s[%d]", top.path, top.items)
+				top.items++
+				positions[path] = pos
+
+				rest := strings.TrimPrefix(content, "-")
+				itemContent := strings.TrimLeft(rest, " ")
+				indent += 1 + len(rest) - len(itemContent)
+				stack = append(stack, yamlFrame{kind: yamlItemFrame, indent: indent, path: path})
+				content = itemContent
+				continue
+			}
+
+			match := yamlKeyRegex.FindStringSubmatch(content)
+			if match == nil {
+				break
+			}
+
+			key := match[1]
+			if unquoted, err := strconv.Unquote(key); err == nil {
+				key = unquoted
+			} else if strings.HasPrefix(key, "'") {
+				key = strings.Trim(key, "'")
+			}
+
+			path := key
+			if top.path != "" {
+				path = top.path + "." + key
+			}
+			positions[path] = pos
+
+			value := match[3]
+			switch {
+			case value == "" || strings.HasPrefix(value, "#"):
+				stack = append(stack, yamlFrame{kind: yamlKeyFrame, indent: indent, path: path})
+			case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+				stack = append(stack, yamlFrame{kind: yamlBlockScalarFrame, indent: indent, path: path})
+			}
+			break
+		}
+	}
+	return
+}
+
+var sourcePathRegex = regexp.MustCompile(`\[(\d+)\]|[^.\[]+`)
+
+// mergedListIndex finds where the item at index of an overlay list ended up
+// in the merged list, or returns -1 when it was dropped.
+type mergedListIndex func(mergedList, overlayList []interface{}, index int) int
+
+// listIndexByName follows generic.DeepMergeByKey, which merges list items
+// with the item of the same name.
+func listIndexByName(mergedList, overlayList []interface{}, index int) int {
+	return generic.IndexByKey("name", mergedList, overlayList[index])
+}
+
+// appendedListIndex follows generic.DeepMerge, which appends overlay lists.
+func appendedListIndex(mergedList, overlayList []interface{}, index int) int {
+	return len(mergedList) - len(overlayList) + index
+}
+
+// mergeSourcePositions gives the positions of overlay precedence over the
+// ones of base, at the indexes their list items were merged into.
+func mergeSourcePositions(merged generic.Map, basePositions sourcePositions, overlay generic.Map, overlayPositions sourcePositions, listIndex mergedListIndex) (positions sourcePositions) {
+	positions = sourcePositions{}
+	for path, pos := range basePositions {
+		positions[path] = pos
+	}
+
+	for path, pos := range overlayPositions {
+		mergedPath, found := mergedSourcePath(path, overlay, merged, listIndex)
+		if found {
+			positions[mergedPath] = pos
+		}
+	}
+	return
+}
+
+func mergedSourcePath(path string, overlay, merged interface{}, listIndex mergedListIndex) (mergedPath string, found bool) {
+	for _, segment := range sourcePathRegex.FindAllStringSubmatch(path, -1) {
+		if segment[1] == "" {
+			overlay = mapValue(overlay, segment[0])
+			merged = mapValue(merged, segment[0])
+			if mergedPath != "" {
+				mergedPath += "."
+			}
+			mergedPath += segment[0]
+			continue
+		}
+
+		index, _ := strconv.Atoi(segment[1])
+		overlayList, _ := overlay.([]interface{})
+		mergedList, _ := merged.([]interface{})
+		if index >= len(overlayList) {
+			return
+		}
+
+		overlay = overlayList[index]
+		mergedIndex := listIndex(mergedList, overlayList, index)
+		if mergedIndex < 0 || mergedIndex >= len(mergedList) {
+			return
+		}
+		merged = mergedList[mergedIndex]
+		mergedPath += fmt.Sprintf("[%d]", mergedIndex)
+	}
+
+	found = true
+	return
+}
+
+func mapValue(value interface{}, key string) interface{} {
+	if !generic.IsMappable(value) {
+		return nil
+	}
+	return generic.NewMap(value).Get(key)
+}
@@ -2,6 +2,7 @@ package manifest_test
 
 import (
 	"cf/manifest"
+	"cf/models"
 	"generic"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -57,6 +58,21 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(*apps[0].NoRoute).To(BeTrue())
 	})
 
+	It("TestManifestWithRandomRoute", func() {
+		m, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
+			"applications": []interface{}{
+				map[string]interface{}{
+					"name":         "bitcoin-miner",
+					"random-route": true,
+				},
+			},
+		}))
+
+		Expect(errs).To(BeEmpty())
+		Expect(m.Warnings).To(BeEmpty())
+		Expect(*m.Applications[0].RandomRoute).To(BeTrue())
+	})
+
 	It("TestManifestWithInvalidMemory", func() {
 		_, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
 			"instances": "3",
@@ -102,6 +118,95 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(errs.Error()).To(ContainSubstring("env var 'bar' should not be null"))
 	})
 
+	It("TestManifestWithNonStringEnvVars", func() {
+		m, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
+			"applications": []interface{}{
+				map[string]interface{}{
+					"name": "structured-env",
+					"env": map[string]interface{}{
+						"PORT":    8080,
+						"DEBUG":   true,
+						"RATIO":   0.75,
+						"BIG":     float64(12345678901),
+						"SERVERS": []interface{}{"one", 2},
+						"DATABASE": map[interface{}]interface{}{
+							"host": "db.example.com",
+							"port": 5432,
+						},
+					},
+				},
+			},
+		}))
+
+		Expect(errs).To(BeEmpty())
+		Expect(*m.Applications[0].EnvironmentVars).To(Equal(map[string]string{
+			"PORT":     "8080",
+			"DEBUG":    "true",
+			"RATIO":    "0.75",
+			"BIG":      "12345678901",
+			"SERVERS":  `["one",2]`,
+			"DATABASE": `{"host":"db.example.com","port":5432}`,
+		}))
+	})
+
+	It("TestManifestWithAnUnsupportedEnvVarNamesTheKey", func() {
+		_, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
+			"applications": []interface{}{
+				map[string]interface{}{
+					"name": "bad-env",
+					"env": map[string]interface{}{
+						"GOOD": "value",
+						"BAD":  map[string]interface{}{"nested": make(chan int)},
+					},
+				},
+			},
+		}))
+
+		Expect(errs).NotTo(BeEmpty())
+		Expect(errs.Error()).To(ContainSubstring("env var 'BAD' has an unsupported value of type chan int"))
+	})
+
+	It("TestManifestWithDeclaredServices", func() {
+		m, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
+			"applications": []interface{}{
+				map[string]interface{}{
+					"name": "app-with-services",
+					"services": []interface{}{
+						"existing-service",
+						map[interface{}]interface{}{"name": "db", "label": "postgres", "plan": "small", "provider": "core"},
+						map[interface{}]interface{}{
+							"name":        "creds",
+							"credentials": map[interface{}]interface{}{"username": "admin", "port": 5432},
+						},
+					},
+				},
+			},
+		}))
+
+		Expect(errs).To(BeEmpty())
+		Expect(*m.Applications[0].Services).To(Equal([]string{"existing-service", "db", "creds"}))
+		Expect(*m.Applications[0].ServiceInstances).To(Equal([]models.ServiceInstanceParams{
+			{Name: "db", Label: "postgres", Plan: "small", Provider: "core"},
+			{Name: "creds", Credentials: map[string]string{"username": "admin", "port": "5432"}},
+		}))
+	})
+
+	It("TestManifestWithAnIncompleteServiceDeclaration", func() {
+		_, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
+			"applications": []interface{}{
+				map[string]interface{}{
+					"name": "app-with-services",
+					"services": []interface{}{
+						map[interface{}]interface{}{"name": "db", "label": "postgres"},
+					},
+				},
+			},
+		}))
+
+		Expect(errs).NotTo(BeEmpty())
+		Expect(errs.Error()).To(ContainSubstring("Expected service db to have a label and a plan, or credentials."))
+	})
+
 	It("returns an empty map when no env was present in the manifest", func() {
 		m, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
 			"applications": []interface{}{
@@ -206,4 +311,60 @@ var _ = Describe("Testing with ginkgo", func() {
 		Expect(errs).To(BeEmpty())
 		Expect(m.Applications[0].Command).To(BeNil())
 	})
+
+	It("parses the apps an app depends on", func() {
+		m, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
+			"applications": []interface{}{
+				map[string]interface{}{"name": "api"},
+				map[string]interface{}{
+					"name":       "worker",
+					"depends_on": []interface{}{"api"},
+				},
+			},
+		}))
+
+		Expect(errs).To(BeEmpty())
+		Expect(m.Applications[0].DependsOn).To(BeNil())
+		Expect(*m.Applications[1].DependsOn).To(Equal([]string{"api"}))
+	})
+
+	It("parses the routes, hosts and domains of an app", func() {
+		m, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
+			"applications": []interface{}{
+				map[string]interface{}{
+					"name":    "api",
+					"hosts":   []interface{}{"api", "api-v2"},
+					"domains": []interface{}{"example.com", "internal.example.com"},
+					"routes": []interface{}{
+						"status.example.com",
+						map[string]interface{}{"route": "example.com"},
+					},
+				},
+				map[string]interface{}{"name": "worker"},
+			},
+		}))
+
+		Expect(errs).To(BeEmpty())
+		Expect(*m.Applications[0].Hosts).To(Equal([]string{"api", "api-v2"}))
+		Expect(*m.Applications[0].Domains).To(Equal([]string{"example.com", "internal.example.com"}))
+		Expect(*m.Applications[0].Routes).To(Equal([]string{"status.example.com", "example.com"}))
+
+		Expect(m.Applications[1].Hosts).To(BeNil())
+		Expect(m.Applications[1].Domains).To(BeNil())
+		Expect(m.Applications[1].Routes).To(BeNil())
+	})
+
+	It("returns an error for routes without a URL", func() {
+		_, errs := manifest.NewManifest("/some/path", generic.NewMap(map[string]interface{}{
+			"applications": []interface{}{
+				map[string]interface{}{
+					"name":   "api",
+					"routes": []interface{}{map[string]interface{}{"host": "api"}},
+				},
+			},
+		}))
+
+		Expect(errs).NotTo(BeEmpty())
+		Expect(errs.Error()).To(ContainSubstring("Expected each of the routes to be a URL or a map with a route."))
+	})
 })
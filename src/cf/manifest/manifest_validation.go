@@ -0,0 +1,126 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"generic"
+)
+
+var knownAppKeys = []string{
+	"buildpack",
+	"command",
+	"depends_on",
+	"disk_quota",
+	"domain",
+	"domains",
+	"env",
+	"host",
+	"hosts",
+	"instances",
+	"memory",
+	"name",
+	"no-route",
+	"path",
+	"random-route",
+	"routes",
+	"services",
+	"stack",
+	"timeout",
+}
+
+// keys at the top level of a manifest are also merged into every app
+var knownTopLevelKeys = append([]string{"applications", "inherit"}, knownAppKeys...)
+
+// unknownKeyWarnings reports the keys that are not used by push. They are
+// usually typos that would otherwise be silently ignored.
+func unknownKeyWarnings(data generic.Map, positions sourcePositions) (warnings ManifestErrors) {
+	for _, key := range sortedKeys(data) {
+		name := fmt.Sprint(key)
+		if !isKnownKey(name, knownTopLevelKeys) {
+			warnings = append(warnings, locatedError{
+				position: positions.find(name),
+				err:      unknownKeyError(name, knownTopLevelKeys),
+			})
+		}
+	}
+
+	appMaps, _ := data.Get("applications").([]interface{})
+	for index, appData := range appMaps {
+		if !generic.IsMappable(appData) {
+			continue
+		}
+
+		appMap := generic.NewMap(appData)
+		appName, _ := appMap.Get("name").(string)
+
+		for _, key := range sortedKeys(appMap) {
+			name := fmt.Sprint(key)
+			if !isKnownKey(name, knownAppKeys) {
+				warnings = append(warnings, locatedError{
+					position: positions.find(fmt.Sprintf("applications[%d].%s", index, name)),
+					appName:  appName,
+					err:      unknownKeyError(name, knownAppKeys),
+				})
+			}
+		}
+	}
+	return
+}
+
+func isKnownKey(key string, knownKeys []string) bool {
+	for _, knownKey := range knownKeys {
+		if key == knownKey {
+			return true
+		}
+	}
+	return false
+}
+
+func unknownKeyError(key string, knownKeys []string) error {
+	suggestion := ""
+	bestDistance := 3
+	for _, knownKey := range knownKeys {
+		distance := editDistance(key, knownKey)
+		if distance < bestDistance {
+			suggestion = knownKey
+			bestDistance = distance
+		}
+	}
+
+	if suggestion == "" {
+		return errors.New(fmt.Sprintf("Unknown key '%s'", key))
+	}
+	return errors.New(fmt.Sprintf("Unknown key '%s', did you mean '%s'?", key, suggestion))
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) (result int) {
+	result = values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return
+}
@@ -0,0 +1,142 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"generic"
+	"github.com/cloudfoundry/gamble"
+	"io/ioutil"
+	"regexp"
+	"sort"
+)
+
+// ManifestVars are the values substituted for ((name)) in a manifest. Values
+// given directly take precedence over the ones read from the files.
+type ManifestVars struct {
+	Files  []string
+	Values map[string]string
+}
+
+var manifestVarRegex = regexp.MustCompile(`\(\(([-\w.]+)\)\)`)
+
+func (vars ManifestVars) resolve() (values map[string]string, errs ManifestErrors) {
+	values = map[string]string{}
+
+	for _, path := range vars.Files {
+		fileValues, err := readVarsFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for name, value := range fileValues {
+			values[name] = value
+		}
+	}
+
+	for name, value := range vars.Values {
+		values[name] = value
+	}
+	return
+}
+
+func readVarsFile(path string) (values map[string]string, err error) {
+	yamlBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("Error reading vars file: %s", err))
+		return
+	}
+
+	document, err := gamble.Parse(string(yamlBytes))
+	if err != nil {
+		err = errors.New(fmt.Sprintf("Error parsing vars file %s: %s", path, err))
+		return
+	}
+
+	values = map[string]string{}
+	if document == nil {
+		return
+	}
+
+	if !generic.IsMappable(document) {
+		err = errors.New(fmt.Sprintf("Invalid vars file %s. Expected a map", path))
+		return
+	}
+
+	generic.Each(generic.NewMap(document), func(key, value interface{}) {
+		stringValue, ok := value.(string)
+		if !ok {
+			err = errors.New(fmt.Sprintf("Invalid vars file %s. Expected %s to be a string value", path, key))
+			return
+		}
+		values[fmt.Sprint(key)] = stringValue
+	})
+	return
+}
+
+// interpolateVars replaces every ((name)) in the string values of data. All
+// the variables that have no value are reported, along with where they are.
+func interpolateVars(data generic.Map, vars map[string]string) (result generic.Map, errs ManifestErrors) {
+	result = transformStrings(data, "", func(value, path string) string {
+		return manifestVarRegex.ReplaceAllStringFunc(value, func(match string) string {
+			name := manifestVarRegex.FindStringSubmatch(match)[1]
+			replacement, found := vars[name]
+			if !found {
+				errs = append(errs, errors.New(fmt.Sprintf("Unresolved variable %s at %s", match, path)))
+				return match
+			}
+			return replacement
+		})
+	}).(generic.Map)
+	return
+}
+
+func copyDocument(data generic.Map) generic.Map {
+	return transformStrings(data, "", func(value, path string) string {
+		return value
+	}).(generic.Map)
+}
+
+// transformStrings returns a copy of value where every string has been
+// passed through transform along with its path in the document.
+func transformStrings(value interface{}, path string, transform func(value, path string) string) interface{} {
+	switch value := value.(type) {
+	case string:
+		return transform(value, path)
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for index, item := range value {
+			result[index] = transformStrings(item, fmt.Sprintf("%s[%d]", path, index), transform)
+		}
+		return result
+	case map[string]interface{}:
+		return transformStrings(generic.NewMap(value), path, transform)
+	case generic.Map:
+		result := generic.NewMap()
+		for _, key := range sortedKeys(value) {
+			keyPath := fmt.Sprint(key)
+			if path != "" {
+				keyPath = path + "." + keyPath
+			}
+			result.Set(key, transformStrings(value.Get(key), keyPath, transform))
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+func sortedKeys(data generic.Map) (keys []interface{}) {
+	names := []string{}
+	keysByName := map[string]interface{}{}
+	for _, key := range data.Keys() {
+		name := fmt.Sprint(key)
+		names = append(names, name)
+		keysByName[name] = key
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		keys = append(keys, keysByName[name])
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"generic"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RenderYAML writes a manifest document back out as YAML, with map keys in
+// alphabetical order.
+func RenderYAML(data generic.Map) string {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("---\n")
+	renderMap(buffer, data, 0)
+	return buffer.String()
+}
+
+func renderMap(buffer *bytes.Buffer, data generic.Map, indent int) {
+	for _, key := range sortedKeys(data) {
+		prefix := strings.Repeat(" ", indent) + renderScalar(fmt.Sprint(key)) + ":"
+		renderEntry(buffer, prefix, data.Get(key), indent)
+	}
+}
+
+func renderList(buffer *bytes.Buffer, items []interface{}, indent int) {
+	for _, item := range items {
+		if nestedItems, ok := item.([]interface{}); ok && len(nestedItems) > 0 {
+			buffer.WriteString(strings.Repeat(" ", indent) + "-\n")
+			renderList(buffer, nestedItems, indent+2)
+			continue
+		}
+
+		itemBuffer := &bytes.Buffer{}
+		renderEntry(itemBuffer, strings.Repeat(" ", indent)+"-", item, indent)
+
+		// the first key of a map in a list goes on the same line as the dash
+		rendered := itemBuffer.String()
+		nested := strings.Repeat(" ", indent) + "-\n" + strings.Repeat(" ", indent+2)
+		if strings.HasPrefix(rendered, nested) {
+			rendered = strings.Repeat(" ", indent) + "- " + rendered[len(nested):]
+		}
+		buffer.WriteString(rendered)
+	}
+}
+
+func renderEntry(buffer *bytes.Buffer, prefix string, value interface{}, indent int) {
+	switch value := value.(type) {
+	case nil:
+		buffer.WriteString(prefix + " null\n")
+	case map[string]interface{}:
+		renderEntry(buffer, prefix, generic.NewMap(value), indent)
+	case generic.Map:
+		if value.IsEmpty() {
+			buffer.WriteString(prefix + " {}\n")
+			return
+		}
+		buffer.WriteString(prefix + "\n")
+		renderMap(buffer, value, indent+2)
+	case []interface{}:
+		if len(value) == 0 {
+			buffer.WriteString(prefix + " []\n")
+			return
+		}
+		buffer.WriteString(prefix + "\n")
+		renderList(buffer, value, indent)
+	case []string:
+		items := make([]interface{}, len(value))
+		for index, item := range value {
+			items[index] = item
+		}
+		renderEntry(buffer, prefix, items, indent)
+	case string:
+		buffer.WriteString(prefix + " " + renderScalar(value) + "\n")
+	default:
+		buffer.WriteString(prefix + " " + fmt.Sprint(value) + "\n")
+	}
+}
+
+var (
+	plainYAMLScalarRegex = regexp.MustCompile(`^[\w/.][\w/.\- ]*$`)
+	yamlBoolOrNullRegex  = regexp.MustCompile(`^(?i:y|n|yes|no|true|false|on|off|null)$`)
+	yamlNumberRegex      = regexp.MustCompile(`^[-+]?([0-9][0-9_]*)?\.?[0-9]+([eE][-+]?[0-9]+)?$`)
+)
+
+// renderScalar quotes the strings that would not be read back as the same
+// string, including the ones that look like a number or a boolean.
+func renderScalar(value string) string {
+	if plainYAMLScalarRegex.MatchString(value) &&
+		!strings.HasSuffix(value, " ") &&
+		!yamlBoolOrNullRegex.MatchString(value) &&
+		!yamlNumberRegex.MatchString(value) {
+		return value
+	}
+	return strconv.Quote(value)
+}
@@ -3,8 +3,9 @@ package models
 import "time"
 
 type EventFields struct {
-	Guid        string
-	Name        string
-	Timestamp   time.Time
-	Description string
+	Guid          string
+	Name          string
+	Timestamp     time.Time
+	Description   string
+	InstanceIndex *int // only set for events about a single instance
 }
@@ -1,7 +1,10 @@
 package models
 
+import "os"
+
 type AppFileFields struct {
 	Path string
 	Sha1 string
 	Size int64
+	Mode os.FileMode
 }
@@ -33,6 +33,7 @@ func (model Application) ToParams() (params AppParams) {
 type AppSummary struct {
 	ApplicationFields
 	RouteSummaries []RouteSummary
+	ServiceNames   []string
 }
 
 type ApplicationFields struct {
@@ -52,18 +53,24 @@ type ApplicationFields struct {
 type AppParams struct {
 	BuildpackUrl       *string
 	Command            *string
+	DependsOn          *[]string
 	DiskQuota          *uint64
 	Domain             *string
+	Domains            *[]string
 	EnvironmentVars    *map[string]string
 	Guid               *string
 	HealthCheckTimeout *int
 	Host               *string
+	Hosts              *[]string
 	InstanceCount      *int
 	Memory             *uint64
 	Name               *string
 	NoRoute            *bool
 	Path               *string
+	RandomRoute        *bool
+	Routes             *[]string
 	RunningInstances   *int
+	ServiceInstances   *[]ServiceInstanceParams
 	Services           *[]string
 	SpaceGuid          *string
 	StackGuid          *string
@@ -78,12 +85,18 @@ func (app *AppParams) Merge(other *AppParams) {
 	if other.Command != nil {
 		app.Command = other.Command
 	}
+	if other.DependsOn != nil {
+		app.DependsOn = other.DependsOn
+	}
 	if other.DiskQuota != nil {
 		app.DiskQuota = other.DiskQuota
 	}
 	if other.Domain != nil {
 		app.Domain = other.Domain
 	}
+	if other.Domains != nil {
+		app.Domains = other.Domains
+	}
 	if other.EnvironmentVars != nil {
 		app.EnvironmentVars = other.EnvironmentVars
 	}
@@ -96,6 +109,9 @@ func (app *AppParams) Merge(other *AppParams) {
 	if other.Host != nil {
 		app.Host = other.Host
 	}
+	if other.Hosts != nil {
+		app.Hosts = other.Hosts
+	}
 	if other.InstanceCount != nil {
 		app.InstanceCount = other.InstanceCount
 	}
@@ -111,9 +127,18 @@ func (app *AppParams) Merge(other *AppParams) {
 	if other.Path != nil {
 		app.Path = other.Path
 	}
+	if other.RandomRoute != nil {
+		app.RandomRoute = other.RandomRoute
+	}
+	if other.Routes != nil {
+		app.Routes = other.Routes
+	}
 	if other.RunningInstances != nil {
 		app.RunningInstances = other.RunningInstances
 	}
+	if other.ServiceInstances != nil {
+		app.ServiceInstances = other.ServiceInstances
+	}
 	if other.Services != nil {
 		app.Services = other.Services
 	}
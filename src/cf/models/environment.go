@@ -0,0 +1,12 @@
+package models
+
+// Environment is every variable an app gets: the ones the system provides,
+// like VCAP_SERVICES and VCAP_APPLICATION, the ones set by the user and the
+// running and staging environment variable groups.
+type Environment struct {
+	System      map[string]interface{}
+	Application map[string]interface{}
+	User        map[string]interface{}
+	Running     map[string]interface{}
+	Staging     map[string]interface{}
+}
@@ -18,3 +18,17 @@ type ServiceInstance struct {
 func (inst ServiceInstance) IsUserProvided() bool {
 	return inst.ServicePlan.Guid == ""
 }
+
+// ServiceInstanceParams describes a service instance that push creates when
+// it does not exist yet. Instances with credentials are user-provided.
+type ServiceInstanceParams struct {
+	Name        string
+	Label       string
+	Provider    string
+	Plan        string
+	Credentials map[string]string
+}
+
+func (params ServiceInstanceParams) IsUserProvided() bool {
+	return params.Credentials != nil
+}
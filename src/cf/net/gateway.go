@@ -53,6 +53,8 @@ type tokenRefresher interface {
 type Request struct {
 	HttpReq      *http.Request
 	SeekableBody io.ReadSeeker
+
+	streamResponse bool
 }
 
 type Gateway struct {
@@ -179,6 +181,8 @@ func (gateway Gateway) NewRequest(method, path, accessToken string, body io.Read
 				break
 			}
 			request.ContentLength = fileStats.Size()
+		case *ProgressReader:
+			request.ContentLength = v.Size()
 		}
 	}
 
@@ -195,6 +199,14 @@ func (gateway Gateway) PerformRequestForResponse(request *Request) (rawResponse
 	return gateway.doRequestHandlingAuth(request)
 }
 
+// PerformRequestForStreamingResponse leaves the body of the response to the
+// caller. Unlike PerformRequestForResponse, only the headers of the response
+// are traced, so that a large download is never read into memory.
+func (gateway Gateway) PerformRequestForStreamingResponse(request *Request) (rawResponse *http.Response, apiResponse ApiResponse) {
+	request.streamResponse = true
+	return gateway.doRequestHandlingAuth(request)
+}
+
 func (gateway Gateway) PerformRequestForResponseBytes(request *Request) (bytes []byte, headers http.Header, apiResponse ApiResponse) {
 	rawResponse, apiResponse := gateway.doRequestHandlingAuth(request)
 	if apiResponse.IsNotSuccessful() {
@@ -344,7 +356,12 @@ func (gateway Gateway) doRequestHandlingAuth(request *Request) (rawResponse *htt
 }
 
 func (gateway Gateway) doRequestAndHandlerError(request *Request) (rawResponse *http.Response, apiResponse ApiResponse) {
-	rawResponse, err := doRequest(request.HttpReq)
+	var err error
+	if request.streamResponse {
+		rawResponse, err = doStreamingRequest(request.HttpReq)
+	} else {
+		rawResponse, err = doRequest(request.HttpReq)
+	}
 	if err != nil {
 		apiResponse = NewApiResponseWithError("Error performing request", err)
 		return
@@ -1,10 +1,12 @@
 package net_test
 
 import (
+	"bytes"
 	"cf"
 	"cf/api"
 	"cf/configuration"
 	. "cf/net"
+	"cf/trace"
 	"fmt"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -165,6 +167,36 @@ var _ = Describe("Testing with ginkgo", func() {
 		})
 	})
 
+	It("TestStreamingResponseIsReadByTheCallerAndNotTraced", func() {
+		apiServer := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			fmt.Fprint(writer, "the content of a large file")
+		}))
+		defer apiServer.Close()
+
+		traceOutput := new(bytes.Buffer)
+		trace.SetStdout(traceOutput)
+		trace.EnableTrace()
+		defer func() {
+			trace.DisableTrace()
+			trace.SetStdout(os.Stdout)
+		}()
+
+		request, apiResponse := ccGateway.NewRequest("GET", apiServer.URL+"/v2/file", "BEARER my-access-token", nil)
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+
+		rawResponse, apiResponse := ccGateway.PerformRequestForStreamingResponse(request)
+		Expect(apiResponse.IsSuccessful()).To(BeTrue())
+		defer rawResponse.Body.Close()
+
+		Expect(traceOutput.String()).To(ContainSubstring("RESPONSE:"))
+		Expect(traceOutput.String()).To(ContainSubstring("[STREAMED CONTENT HIDDEN]"))
+		Expect(traceOutput.String()).NotTo(ContainSubstring("the content of a large file"))
+
+		body, err := ioutil.ReadAll(rawResponse.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal("the content of a large file"))
+	})
+
 	Describe("when uploading a file", func() {
 		var err error
 		var request *Request
@@ -61,6 +61,16 @@ func Sanitize(input string) (sanitized string) {
 }
 
 func doRequest(request *http.Request) (response *http.Response, err error) {
+	return doRequestAndDumpResponse(request, true)
+}
+
+// doStreamingRequest does not read the body of the response, it is only
+// dumped without it.
+func doStreamingRequest(request *http.Request) (response *http.Response, err error) {
+	return doRequestAndDumpResponse(request, false)
+}
+
+func doRequestAndDumpResponse(request *http.Request, dumpBody bool) (response *http.Response, err error) {
 	httpClient := newHttpClient()
 
 	dumpRequest(request)
@@ -70,7 +80,7 @@ func doRequest(request *http.Request) (response *http.Response, err error) {
 		return
 	}
 
-	dumpResponse(response)
+	dumpResponse(response, dumpBody)
 	return
 }
 
@@ -87,11 +97,14 @@ func dumpRequest(req *http.Request) {
 	}
 }
 
-func dumpResponse(res *http.Response) {
-	dumpedResponse, err := httputil.DumpResponse(res, true)
+func dumpResponse(res *http.Response, dumpBody bool) {
+	dumpedResponse, err := httputil.DumpResponse(res, dumpBody)
 	if err != nil {
 		trace.Logger.Printf("Error dumping response\n%s\n", err)
 	} else {
 		trace.Logger.Printf("\n%s [%s]\n%s\n", terminal.HeaderColor("RESPONSE:"), time.Now().Format(time.RFC3339), Sanitize(string(dumpedResponse)))
+		if !dumpBody {
+			trace.Logger.Println("[STREAMED CONTENT HIDDEN]")
+		}
 	}
 }
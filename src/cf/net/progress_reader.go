@@ -0,0 +1,44 @@
+package net
+
+import (
+	"io"
+)
+
+// ProgressReader counts the bytes read from a request body and reports them
+// to onProgress, so that callers can show how far an upload has come.
+type ProgressReader struct {
+	body       io.ReadSeeker
+	total      int64
+	read       int64
+	onProgress func(read, total int64)
+}
+
+func NewProgressReader(body io.ReadSeeker, total int64, onProgress func(read, total int64)) *ProgressReader {
+	return &ProgressReader{
+		body:       body,
+		total:      total,
+		onProgress: onProgress,
+	}
+}
+
+func (reader *ProgressReader) Read(p []byte) (n int, err error) {
+	n, err = reader.body.Read(p)
+	if n > 0 {
+		reader.read += int64(n)
+		reader.onProgress(reader.read, reader.total)
+	}
+	return
+}
+
+func (reader *ProgressReader) Seek(offset int64, whence int) (position int64, err error) {
+	position, err = reader.body.Seek(offset, whence)
+	if err == nil {
+		// the body is rewound when a request is retried after a token refresh
+		reader.read = position
+	}
+	return
+}
+
+func (reader *ProgressReader) Size() int64 {
+	return reader.total
+}
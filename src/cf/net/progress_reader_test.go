@@ -0,0 +1,46 @@
+package net_test
+
+import (
+	. "cf/net"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+var _ = Describe("ProgressReader", func() {
+	var (
+		reported []int64
+		reader   *ProgressReader
+	)
+
+	BeforeEach(func() {
+		reported = []int64{}
+		reader = NewProgressReader(strings.NewReader("hello world"), 11, func(read, total int64) {
+			Expect(total).To(Equal(int64(11)))
+			reported = append(reported, read)
+		})
+	})
+
+	It("reports the bytes read so far", func() {
+		buffer := make([]byte, 5)
+		reader.Read(buffer)
+		reader.Read(buffer)
+
+		Expect(reported).To(Equal([]int64{5, 10}))
+		Expect(reader.Size()).To(Equal(int64(11)))
+	})
+
+	It("starts counting again when the body is rewound", func() {
+		_, err := ioutil.ReadAll(reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = reader.Seek(0, os.SEEK_SET)
+		Expect(err).NotTo(HaveOccurred())
+
+		buffer := make([]byte, 3)
+		reader.Read(buffer)
+		Expect(reported[len(reported)-1]).To(Equal(int64(3)))
+	})
+})
@@ -0,0 +1,56 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+)
+
+// LiveView shows output that is refreshed over time. On a terminal it is
+// redrawn in place, elsewhere every refresh is appended to the output.
+type LiveView interface {
+	Redraw(lines []string)
+	IsLive() bool
+}
+
+type redrawingView struct {
+	writer    io.Writer
+	lineCount int
+}
+
+func NewRedrawingView(writer io.Writer) LiveView {
+	return &redrawingView{writer: writer}
+}
+
+func (view *redrawingView) Redraw(lines []string) {
+	if view.lineCount > 0 {
+		// move the cursor back up to the first line drawn and clear down from there
+		fmt.Fprintf(view.writer, "\033[%dA\033[J", view.lineCount)
+	}
+
+	for _, line := range lines {
+		fmt.Fprintln(view.writer, line)
+	}
+	view.lineCount = len(lines)
+}
+
+func (view *redrawingView) IsLive() bool {
+	return true
+}
+
+type appendingView struct {
+	ui UI
+}
+
+func NewAppendingView(ui UI) LiveView {
+	return appendingView{ui: ui}
+}
+
+func (view appendingView) Redraw(lines []string) {
+	for _, line := range lines {
+		view.ui.Say("%s", line)
+	}
+}
+
+func (view appendingView) IsLive() bool {
+	return false
+}
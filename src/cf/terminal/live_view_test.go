@@ -0,0 +1,23 @@
+package terminal_test
+
+import (
+	"bytes"
+	. "cf/terminal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("redrawing view", func() {
+	It("draws over the lines it drew before", func() {
+		output := &bytes.Buffer{}
+		view := NewRedrawingView(output)
+
+		view.Redraw([]string{"first", "second"})
+		Expect(output.String()).To(Equal("first\nsecond\n"))
+
+		output.Reset()
+		view.Redraw([]string{"third"})
+		Expect(output.String()).To(Equal("\033[2A\033[Jthird\n"))
+		Expect(view.IsLive()).To(BeTrue())
+	})
+})
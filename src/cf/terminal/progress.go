@@ -0,0 +1,173 @@
+package terminal
+
+import (
+	"cf/formatters"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	progressRedrawInterval = 200 * time.Millisecond
+	phaseTickInterval      = 500 * time.Millisecond
+)
+
+type ProgressBar interface {
+	Update(current, total int64)
+	Finish()
+}
+
+type PhaseIndicator interface {
+	Stop()
+}
+
+// IsTerminal reports whether file is attached to a terminal rather than to a
+// pipe or a regular file.
+func IsTerminal(file *os.File) bool {
+	stat, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+type byteProgressBar struct {
+	writer    io.Writer
+	now       func() time.Time
+	startTime time.Time
+	lastDraw  time.Time
+	lineWidth int
+	finished  bool
+	mutex     *sync.Mutex
+}
+
+func NewByteProgressBar(writer io.Writer, now func() time.Time) ProgressBar {
+	return &byteProgressBar{
+		writer:    writer,
+		now:       now,
+		startTime: now(),
+		mutex:     new(sync.Mutex),
+	}
+}
+
+func (bar *byteProgressBar) Update(current, total int64) {
+	bar.mutex.Lock()
+	defer bar.mutex.Unlock()
+
+	if bar.finished {
+		return
+	}
+
+	now := bar.now()
+	if current < total && now.Sub(bar.lastDraw) < progressRedrawInterval {
+		return
+	}
+	bar.lastDraw = now
+
+	bar.draw(progressLine(current, total, now.Sub(bar.startTime)))
+}
+
+func (bar *byteProgressBar) Finish() {
+	bar.mutex.Lock()
+	defer bar.mutex.Unlock()
+
+	if bar.finished {
+		return
+	}
+	bar.finished = true
+
+	if bar.lineWidth > 0 {
+		fmt.Fprint(bar.writer, "\n")
+	}
+}
+
+func (bar *byteProgressBar) draw(line string) {
+	padding := ""
+	if len(line) < bar.lineWidth {
+		padding = strings.Repeat(" ", bar.lineWidth-len(line))
+	}
+	bar.lineWidth = len(line)
+
+	fmt.Fprintf(bar.writer, "\r%s%s", line, padding)
+}
+
+func progressLine(current, total int64, elapsed time.Duration) string {
+	if total <= 0 {
+		return formatters.ByteSize(uint64(current))
+	}
+	if current > total {
+		current = total
+	}
+
+	percent := current * 100 / total
+	line := fmt.Sprintf("%s / %s  %3d%%", formatters.ByteSize(uint64(current)), formatters.ByteSize(uint64(total)), percent)
+
+	seconds := elapsed.Seconds()
+	if seconds <= 0 || current == 0 {
+		return line
+	}
+
+	rate := float64(current) / seconds
+	line = fmt.Sprintf("%s  %s/s", line, formatters.ByteSize(uint64(rate)))
+
+	if current < total {
+		eta := time.Duration(float64(total-current)/rate) * time.Second
+		line = fmt.Sprintf("%s  ETA %s", line, eta)
+	}
+	return line
+}
+
+type tickingPhaseIndicator struct {
+	stop chan bool
+	done chan bool
+}
+
+func startTickingPhaseIndicator(writer io.Writer, message string) *tickingPhaseIndicator {
+	phase := &tickingPhaseIndicator{
+		stop: make(chan bool),
+		done: make(chan bool),
+	}
+
+	fmt.Fprint(writer, message)
+
+	go func() {
+		defer close(phase.done)
+		ticker := time.NewTicker(phaseTickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				fmt.Fprint(writer, ".")
+			case <-phase.stop:
+				fmt.Fprint(writer, "\n")
+				return
+			}
+		}
+	}()
+
+	return phase
+}
+
+func (phase *tickingPhaseIndicator) Stop() {
+	select {
+	case <-phase.done:
+		return
+	default:
+	}
+
+	close(phase.stop)
+	<-phase.done
+}
+
+type noopProgressBar struct{}
+
+func (bar noopProgressBar) Update(current, total int64) {}
+func (bar noopProgressBar) Finish()                     {}
+
+type noopPhaseIndicator struct{}
+
+func (phase noopPhaseIndicator) Stop() {}
@@ -0,0 +1,52 @@
+package terminal_test
+
+import (
+	"bytes"
+	. "cf/terminal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("byte progress bar", func() {
+	var (
+		output *bytes.Buffer
+		now    time.Time
+		bar    ProgressBar
+	)
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		now = time.Date(2014, time.January, 1, 0, 0, 0, 0, time.UTC)
+		bar = NewByteProgressBar(output, func() time.Time { return now })
+	})
+
+	It("shows the bytes sent, percentage, throughput and ETA", func() {
+		now = now.Add(2 * time.Second)
+		bar.Update(2*1024*1024, 8*1024*1024)
+
+		Expect(output.String()).To(Equal("\r2M / 8M   25%  1M/s  ETA 6s"))
+	})
+
+	It("does not redraw more often than needed", func() {
+		now = now.Add(time.Second)
+		bar.Update(1024, 4096)
+		bar.Update(2048, 4096)
+
+		Expect(output.String()).NotTo(ContainSubstring("2K / 4K"))
+
+		now = now.Add(time.Second)
+		bar.Update(3072, 4096)
+		Expect(output.String()).To(ContainSubstring("3K / 4K"))
+	})
+
+	It("always draws the completed upload and ends the line when finished", func() {
+		now = now.Add(time.Second)
+		bar.Update(1024, 4096)
+		bar.Update(4096, 4096)
+		bar.Finish()
+		bar.Update(4096, 4096)
+
+		Expect(output.String()).To(HaveSuffix("\r4K / 4K  100%  4K/s        \n"))
+	})
+})
@@ -34,6 +34,9 @@ type UI interface {
 	Wait(duration time.Duration)
 	DisplayTable(table [][]string)
 	Table(headers []string) Table
+	NewProgressBar() ProgressBar
+	StartPhase(message string, args ...interface{}) PhaseIndicator
+	NewLiveView() LiveView
 }
 
 type terminalUI struct {
@@ -154,8 +157,36 @@ func (ui terminalUI) Table(headers []string) Table {
 	return NewTable(ui, headers)
 }
 
+func (ui terminalUI) NewProgressBar() ProgressBar {
+	if !IsTerminal(os.Stdout) {
+		return noopProgressBar{}
+	}
+	return NewByteProgressBar(os.Stdout, time.Now)
+}
+
+func (ui terminalUI) StartPhase(message string, args ...interface{}) PhaseIndicator {
+	if !IsTerminal(os.Stdout) {
+		return noopPhaseIndicator{}
+	}
+	return startTickingPhaseIndicator(os.Stdout, fmt.Sprintf(message, args...))
+}
+
+func (ui terminalUI) NewLiveView() LiveView {
+	if !IsTerminal(os.Stdout) {
+		return NewAppendingView(ui)
+	}
+	return NewRedrawingView(os.Stdout)
+}
+
 func (ui terminalUI) DisplayTable(table [][]string) {
+	for _, line := range TableLines(table) {
+		fmt.Println(line)
+	}
+}
 
+// TableLines lays table out in padded columns, coloring its header row and
+// first column.
+func TableLines(table [][]string) (lines []string) {
 	columnCount := len(table[0])
 	maxSizes := make([]int, columnCount)
 
@@ -169,13 +200,15 @@ func (ui terminalUI) DisplayTable(table [][]string) {
 	}
 
 	for row, line := range table {
+		output := ""
 		for col, value := range line {
 			padding := strings.Repeat(" ", maxSizes[col]-len(decolorize(value)))
 			value = tableColoringFunc(value, row, col)
-			fmt.Printf("%s%s   ", value, padding)
+			output += fmt.Sprintf("%s%s   ", value, padding)
 		}
-		fmt.Print("\n")
+		lines = append(lines, output)
 	}
+	return
 }
 
 func tableColoringFunc(value string, row int, col int) string {
@@ -6,6 +6,8 @@ import (
 	"fileutils"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 )
 
 type Zipper interface {
@@ -16,6 +18,10 @@ type ApplicationZipper struct{}
 
 var doNotZipExtensions = []string{".zip", ".war", ".jar"}
 
+// zipModTime is stamped on every zip entry so that zipping the same files
+// always produces the same bytes, regardless of when they were touched.
+var zipModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
 func (zipper ApplicationZipper) Zip(dirOrZipFile string, targetFile *os.File) (err error) {
 	if shouldNotZip(filepath.Ext(dirOrZipFile)) {
 		err = fileutils.CopyPathToWriter(dirOrZipFile, targetFile)
@@ -45,25 +51,49 @@ func writeZipFile(dir string, targetFile *os.File) (err error) {
 		return
 	}
 
+	fileNames := []string{}
+	fullPaths := map[string]string{}
+	err = WalkAppFiles(dir, func(fileName string, fullPath string) (err error) {
+		fileName = filepath.ToSlash(fileName)
+		fileNames = append(fileNames, fileName)
+		fullPaths[fileName] = fullPath
+		return
+	})
+	if err != nil {
+		return
+	}
+	sort.Strings(fileNames)
+
 	writer := zip.NewWriter(targetFile)
 	defer writer.Close()
 
-	err = WalkAppFiles(dir, func(fileName string, fullPath string) (err error) {
-		fileInfo, err := os.Stat(fullPath)
+	for _, fileName := range fileNames {
+		err = writeZipEntry(writer, fileName, fullPaths[fileName])
 		if err != nil {
-			return err
+			return
 		}
+	}
 
-		header, err := zip.FileInfoHeader(fileInfo)
-		header.Name = filepath.ToSlash(fileName)
-		if err != nil {
-			return err
-		}
+	return
+}
 
-		zipFilePart, err := writer.CreateHeader(header)
-		err = fileutils.CopyPathToWriter(fullPath, zipFilePart)
+func writeZipEntry(writer *zip.Writer, fileName, fullPath string) (err error) {
+	fileInfo, err := os.Stat(fullPath)
+	if err != nil {
 		return
-	})
+	}
 
-	return
+	header, err := zip.FileInfoHeader(fileInfo)
+	if err != nil {
+		return
+	}
+	header.Name = fileName
+	header.SetModTime(zipModTime)
+
+	zipFilePart, err := writer.CreateHeader(header)
+	if err != nil {
+		return
+	}
+
+	return fileutils.CopyPathToWriter(fullPath, zipFilePart)
 }
@@ -8,8 +8,10 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 func fileToString(file *os.File) string {
@@ -72,6 +74,37 @@ var _ = Describe("Testing with ginkgo", func() {
 		})
 	})
 
+	It("TestZipIsDeterministic", func() {
+		fileutils.TempDir("zip_test", func(dir string, err error) {
+			Expect(err).NotTo(HaveOccurred())
+
+			err = os.MkdirAll(filepath.Join(dir, "subDir"), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+			err = ioutil.WriteFile(filepath.Join(dir, "foo.txt"), []byte("This is a file in the app"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			err = ioutil.WriteFile(filepath.Join(dir, "subDir", "bar.txt"), []byte("This is a nested file"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			zipDir := func() (contents string) {
+				fileutils.TempFile("zip_test", func(zipFile *os.File, err error) {
+					Expect(err).NotTo(HaveOccurred())
+
+					err = ApplicationZipper{}.Zip(dir, zipFile)
+					Expect(err).NotTo(HaveOccurred())
+
+					contents = fileToString(zipFile)
+				})
+				return
+			}
+
+			firstZip := zipDir()
+			later := time.Now().Add(time.Hour)
+			err = os.Chtimes(filepath.Join(dir, "foo.txt"), later, later)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zipDir()).To(Equal(firstZip))
+		})
+	})
+
 	It("TestZipWithZipFile", func() {
 		fileutils.TempFile("zip_test", func(zipFile *os.File, err error) {
 			dir, err := os.Getwd()
@@ -49,3 +49,63 @@ func init() {
 		})
 	})
 }
+
+var _ = Describe("deep merging by key", func() {
+	It("merges the maps in lists that have the same key", func() {
+		base := NewMap(map[interface{}]interface{}{
+			"applications": []interface{}{
+				map[interface{}]interface{}{"name": "web", "memory": "256M", "services": []interface{}{"db"}},
+				map[interface{}]interface{}{"name": "worker", "memory": "128M"},
+			},
+		})
+		overlay := NewMap(map[interface{}]interface{}{
+			"applications": []interface{}{
+				map[interface{}]interface{}{"name": "web", "memory": "1G", "services": []interface{}{"db", "cache"}},
+				map[interface{}]interface{}{"name": "admin"},
+			},
+		})
+
+		merged := DeepMergeByKey("name", base, overlay)
+		apps := merged.Get("applications").([]interface{})
+
+		Expect(len(apps)).To(Equal(3))
+		Expect(NewMap(apps[0]).Get("memory")).To(Equal("1G"))
+		Expect(NewMap(apps[0]).Get("services")).To(Equal([]interface{}{"db", "cache"}))
+		Expect(NewMap(apps[1]).Get("memory")).To(Equal("128M"))
+		Expect(NewMap(apps[2]).Get("name")).To(Equal("admin"))
+	})
+
+	It("prunes keys and list items", func() {
+		base := NewMap(map[interface{}]interface{}{
+			"memory":   "256M",
+			"services": []interface{}{"db", "cache"},
+			"applications": []interface{}{
+				map[interface{}]interface{}{"name": "web"},
+				map[interface{}]interface{}{"name": "debugger"},
+			},
+		})
+		overlay := NewMap(map[interface{}]interface{}{
+			"memory":       "(( prune ))",
+			"services":     []interface{}{"(( prune cache ))"},
+			"applications": []interface{}{"(( prune debugger ))"},
+			"env": map[interface{}]interface{}{
+				"DEBUG": "(( prune ))",
+			},
+		})
+
+		merged := DeepMergeByKey("name", base, overlay)
+
+		Expect(merged.Has("memory")).To(BeFalse())
+		Expect(merged.Get("services")).To(Equal([]interface{}{"db"}))
+		Expect(len(merged.Get("applications").([]interface{}))).To(Equal(1))
+		Expect(NewMap(merged.Get("env")).Has("DEBUG")).To(BeFalse())
+	})
+
+	It("finds list items by key or by value", func() {
+		list := []interface{}{"db", map[interface{}]interface{}{"name": "web"}}
+
+		Expect(IndexByKey("name", list, "db")).To(Equal(0))
+		Expect(IndexByKey("name", list, map[string]interface{}{"name": "web", "memory": "1G"})).To(Equal(1))
+		Expect(IndexByKey("name", list, "cache")).To(Equal(-1))
+	})
+})
@@ -0,0 +1,142 @@
+package generic
+
+import "regexp"
+
+var (
+	pruneMarkerRegex = regexp.MustCompile(`^\(\(\s*prune\s*\)\)$`)
+	pruneItemRegex   = regexp.MustCompile(`^\(\(\s*prune\s+(.+?)\s*\)\)$`)
+)
+
+// DeepMergeByKey works like DeepMerge, except for lists. The maps in a list
+// are merged with the map of the same key in the earlier list, and values
+// already in a list are not added again.
+//
+// A later map can remove values: "(( prune ))" removes the key it is set to,
+// and a list item "(( prune NAME ))" removes NAME, or the map whose key is
+// NAME, from the list.
+func DeepMergeByKey(key interface{}, maps ...Map) Map {
+	merged := NewMap()
+	for _, mapp := range maps {
+		merged = mergeMapsByKey(key, merged, mapp)
+	}
+	return merged
+}
+
+// IndexByKey finds item in list: the map with the same key, or an equal value.
+func IndexByKey(key interface{}, list []interface{}, item interface{}) int {
+	if IsMappable(item) {
+		value := NewMap(item).Get(key)
+		if value == nil || IsMappable(value) || IsSliceable(value) {
+			return -1
+		}
+		return indexOfMapWithKey(key, list, value)
+	}
+
+	if IsSliceable(item) {
+		return -1
+	}
+
+	for index, listItem := range list {
+		if !IsMappable(listItem) && !IsSliceable(listItem) && listItem == item {
+			return index
+		}
+	}
+	return -1
+}
+
+func indexOfMapWithKey(key interface{}, list []interface{}, value interface{}) int {
+	for index, listItem := range list {
+		if IsMappable(listItem) && NewMap(listItem).Get(key) == value {
+			return index
+		}
+	}
+	return -1
+}
+
+func mergeMapsByKey(key interface{}, base, overlay Map) Map {
+	merged := NewMap()
+	Each(base, func(mapKey, value interface{}) {
+		merged.Set(mapKey, value)
+	})
+
+	Each(overlay, func(mapKey, value interface{}) {
+		baseValue := merged.Get(mapKey)
+		switch {
+		case isPruneMarker(value):
+			merged.Delete(mapKey)
+		case IsMappable(value) && IsMappable(baseValue):
+			merged.Set(mapKey, mergeMapsByKey(key, NewMap(baseValue), NewMap(value)))
+		case isList(value) && isList(baseValue):
+			merged.Set(mapKey, mergeListsByKey(key, baseValue.([]interface{}), value.([]interface{})))
+		default:
+			merged.Set(mapKey, withoutPruneMarkers(key, value))
+		}
+	})
+	return merged
+}
+
+func mergeListsByKey(key interface{}, base, overlay []interface{}) []interface{} {
+	merged := append([]interface{}{}, base...)
+	for _, item := range overlay {
+		if name, ok := pruneItemName(item); ok {
+			merged = removeFromList(key, merged, name)
+			continue
+		}
+
+		index := IndexByKey(key, merged, item)
+		switch {
+		case index < 0:
+			merged = append(merged, withoutPruneMarkers(key, item))
+		case IsMappable(item):
+			merged[index] = mergeMapsByKey(key, NewMap(merged[index]), NewMap(item))
+		}
+	}
+	return merged
+}
+
+func removeFromList(key interface{}, list []interface{}, name string) []interface{} {
+	result := []interface{}{}
+	for _, item := range list {
+		if item == name || (IsMappable(item) && NewMap(item).Get(key) == name) {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// withoutPruneMarkers drops the markers of a value that has nothing to
+// remove them from
+func withoutPruneMarkers(key interface{}, value interface{}) interface{} {
+	switch {
+	case IsMappable(value):
+		return mergeMapsByKey(key, NewMap(), NewMap(value))
+	case isList(value):
+		return mergeListsByKey(key, []interface{}{}, value.([]interface{}))
+	default:
+		return value
+	}
+}
+
+func isList(value interface{}) bool {
+	_, ok := value.([]interface{})
+	return ok
+}
+
+func isPruneMarker(value interface{}) bool {
+	stringValue, ok := value.(string)
+	return ok && pruneMarkerRegex.MatchString(stringValue)
+}
+
+func pruneItemName(value interface{}) (name string, ok bool) {
+	stringValue, ok := value.(string)
+	if !ok {
+		return
+	}
+
+	match := pruneItemRegex.FindStringSubmatch(stringValue)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
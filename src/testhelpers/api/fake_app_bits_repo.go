@@ -1,29 +1,68 @@
 package api
 
 import (
+	"cf/api"
 	"cf/net"
+	"sync"
 )
 
 type FakeApplicationBitsRepository struct {
 	UploadedAppGuid string
 	UploadedDir     string
 	UploadAppErr    bool
+	ForceUpload     bool
+	BitsUnchanged   bool
 
 	CallbackPath      string
 	CallbackZipSize   uint64
 	CallbackFileCount uint64
+
+	PlannedAppGuid string
+	PlannedDir     string
+	UploadPlan     api.UploadPlan
+
+	mutex sync.Mutex
 }
 
-func (repo *FakeApplicationBitsRepository) UploadApp(appGuid, dir string, cb func(path string, zipSize, fileCount uint64)) (apiResponse net.ApiResponse) {
+func (repo *FakeApplicationBitsRepository) UploadApp(appGuid, dir string, forceUpload bool, cb func(path string, zipSize, fileCount uint64), progress api.UploadProgress) (uploaded bool, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
 	repo.UploadedDir = dir
 	repo.UploadedAppGuid = appGuid
+	repo.ForceUpload = forceUpload
+	repo.mutex.Unlock()
 
 	if repo.UploadAppErr {
 		apiResponse = net.NewApiResponseWithMessage("Error uploading app")
 		return
 	}
 
+	if repo.BitsUnchanged && !forceUpload {
+		return
+	}
+
 	cb(repo.CallbackPath, repo.CallbackZipSize, repo.CallbackFileCount)
+	progress.Sent(int64(repo.CallbackZipSize), int64(repo.CallbackZipSize))
+	progress.Processing()
+	uploaded = true
+
+	return
+}
 
+func (repo *FakeApplicationBitsRepository) PlanUpload(appGuid, dir string, forceUpload bool) (plan api.UploadPlan, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	repo.PlannedAppGuid = appGuid
+	repo.PlannedDir = dir
+
+	if repo.UploadAppErr {
+		apiResponse = net.NewApiResponseWithMessage("Error matching app files")
+		return
+	}
+
+	plan = repo.UploadPlan
+	if repo.BitsUnchanged && !forceUpload {
+		plan.Unchanged = true
+	}
 	return
 }
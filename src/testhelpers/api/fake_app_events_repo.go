@@ -1,6 +1,7 @@
 package api
 
 import (
+	"cf/api"
 	"cf/models"
 	"cf/net"
 )
@@ -9,12 +10,33 @@ type FakeAppEventsRepo struct {
 	AppGuid     string
 	Events      []models.EventFields
 	ApiResponse net.ApiResponse
+
+	// the events returned by each listing, for following new events; the
+	// last one stays
+	EventsByListing [][]models.EventFields
+	Filters         []api.EventFilter
+}
+
+func (repo *FakeAppEventsRepo) ListEvents(appGuid string, cb func(models.EventFields) bool) net.ApiResponse {
+	return repo.ListEventsWithFilter(appGuid, api.EventFilter{}, cb)
 }
 
-func (repo FakeAppEventsRepo) ListEvents(appGuid string, cb func(models.EventFields) bool) net.ApiResponse {
+func (repo *FakeAppEventsRepo) ListEventsWithFilter(appGuid string, filter api.EventFilter, cb func(models.EventFields) bool) net.ApiResponse {
 	repo.AppGuid = appGuid
-	for _, e := range repo.Events {
-		cb(e)
+	repo.Filters = append(repo.Filters, filter)
+
+	events := repo.Events
+	if len(repo.EventsByListing) > 0 {
+		events = repo.EventsByListing[0]
+	}
+	if len(repo.EventsByListing) > 1 {
+		repo.EventsByListing = repo.EventsByListing[1:]
+	}
+
+	for _, e := range events {
+		if !cb(e) {
+			break
+		}
 	}
 	return repo.ApiResponse
 }
@@ -2,12 +2,27 @@ package api
 
 import (
 	"cf/net"
+	"io"
+	"sync"
 )
 
 type FakeAppFilesRepo struct {
 	AppGuid  string
 	Path     string
 	FileList string
+
+	// listings and contents by path, for walking the files of an instance
+	Listings      map[string]string
+	FileContents  map[string]string
+	Instance      int
+	ListedPaths   []string
+	DownloadPaths []string
+
+	// the file as it is at each read from an offset; the last one stays
+	FileVersions []string
+	ReadOffsets  []int64
+
+	mutex sync.Mutex
 }
 
 func (repo *FakeAppFilesRepo) ListFiles(appGuid, path string) (files string, apiResponse net.ApiResponse) {
@@ -18,3 +33,70 @@ func (repo *FakeAppFilesRepo) ListFiles(appGuid, path string) (files string, api
 
 	return
 }
+
+func (repo *FakeAppFilesRepo) ListInstanceFiles(appGuid string, instance int, path string) (files string, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	repo.AppGuid = appGuid
+	repo.Instance = instance
+	repo.Path = path
+	repo.ListedPaths = append(repo.ListedPaths, path)
+
+	if repo.Listings == nil {
+		files = repo.FileList
+		return
+	}
+
+	files, found := repo.Listings[path]
+	if !found {
+		apiResponse = net.NewNotFoundApiResponse("%s %s not found", "Path", path)
+	}
+	return
+}
+
+func (repo *FakeAppFilesRepo) DownloadFile(appGuid string, instance int, path string, destination io.Writer) (apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	repo.DownloadPaths = append(repo.DownloadPaths, path)
+	content, found := repo.FileContents[path]
+	repo.mutex.Unlock()
+
+	if !found {
+		apiResponse = net.NewApiResponseWithMessage("Error downloading %s", path)
+		return
+	}
+
+	_, err := io.WriteString(destination, content)
+	if err != nil {
+		apiResponse = net.NewApiResponseWithError("Error writing file", err)
+	}
+	return
+}
+
+func (repo *FakeAppFilesRepo) ReadFileFrom(appGuid string, instance int, path string, offset int64) (content []byte, fileSize int64, apiResponse net.ApiResponse) {
+	repo.AppGuid = appGuid
+	repo.Instance = instance
+	repo.Path = path
+
+	version := ""
+	if len(repo.FileVersions) > 0 {
+		version = repo.FileVersions[0]
+	}
+	if len(repo.FileVersions) > 1 {
+		repo.FileVersions = repo.FileVersions[1:]
+	}
+	repo.ReadOffsets = append(repo.ReadOffsets, offset)
+
+	fileSize = int64(len(version))
+	start := offset
+	if offset < 0 {
+		start = fileSize + offset
+		if start < 0 {
+			start = 0
+		}
+	}
+	if start < fileSize {
+		content = []byte(version[start:])
+	}
+	return
+}
@@ -4,6 +4,7 @@ import (
 	"cf/models"
 	"cf/net"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -11,9 +12,18 @@ type FakeAppInstancesRepo struct {
 	GetInstancesAppGuid    string
 	GetInstancesResponses  [][]models.AppInstanceFields
 	GetInstancesErrorCodes []string
+
+	DeletedInstanceAppGuid string
+	DeletedInstanceIndexes []int
+	DeleteInstanceResponse net.ApiResponse
+
+	mutex sync.Mutex
 }
 
 func (repo *FakeAppInstancesRepo) GetInstances(appGuid string) (instances []models.AppInstanceFields, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.GetInstancesAppGuid = appGuid
 	time.Sleep(1 * time.Millisecond) //needed for Windows only, otherwise it thinks error codes are not assigned
 
@@ -32,3 +42,12 @@ func (repo *FakeAppInstancesRepo) GetInstances(appGuid string) (instances []mode
 
 	return
 }
+
+func (repo *FakeAppInstancesRepo) DeleteInstance(appGuid string, index int) (apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	repo.DeletedInstanceAppGuid = appGuid
+	repo.DeletedInstanceIndexes = append(repo.DeletedInstanceIndexes, index)
+	return repo.DeleteInstanceResponse
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"cf/models"
 	"cf/net"
+	"sync"
 )
 
 type FakeApplicationRepository struct {
@@ -14,19 +15,47 @@ type FakeApplicationRepository struct {
 	ReadAuthErr  bool
 	ReadNotFound bool
 
+	// when set, each Read returns the next of them
+	ReadAppResponses []models.Application
+
+	// when set, Read and ReadFromSpace look apps up by their name
+	ReadAppsByName     map[string]models.Application
+	ReadFromSpaceGuids []string
+
 	CreateAppParams []models.AppParams
 
 	UpdateParams    models.AppParams
 	UpdateAppGuid   string
 	UpdateAppResult models.Application
 	UpdateErr       bool
+	UpdatedAppGuids []string
+	UpdatedParams   []models.AppParams
+
+	DeletedAppGuid  string
+	DeletedAppGuids []string
 
-	DeletedAppGuid string
+	ReadEnvAppGuid  string
+	ReadEnvResult   models.Environment
+	ReadEnvNotFound bool
+	ReadEnvErr      bool
+
+	mutex sync.Mutex
 }
 
 func (repo *FakeApplicationRepository) Read(name string) (app models.Application, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	return repo.read(name)
+}
+
+func (repo *FakeApplicationRepository) read(name string) (app models.Application, apiResponse net.ApiResponse) {
 	repo.ReadName = name
 	app = repo.ReadApp
+	if len(repo.ReadAppResponses) > 0 {
+		app = repo.ReadAppResponses[0]
+		repo.ReadAppResponses = repo.ReadAppResponses[1:]
+	}
 
 	if repo.ReadErr {
 		apiResponse = net.NewApiResponseWithMessage("Error finding app by name.")
@@ -38,9 +67,25 @@ func (repo *FakeApplicationRepository) Read(name string) (app models.Application
 		apiResponse = net.NewNotFoundApiResponse("%s %s not found", "App", name)
 	}
 
+	if repo.ReadAppsByName != nil {
+		var found bool
+		app, found = repo.ReadAppsByName[name]
+		if !found {
+			apiResponse = net.NewNotFoundApiResponse("%s %s not found", "App", name)
+		}
+	}
+
 	return
 }
 
+func (repo *FakeApplicationRepository) ReadFromSpace(name, spaceGuid string) (app models.Application, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	repo.ReadFromSpaceGuids = append(repo.ReadFromSpaceGuids, spaceGuid)
+	return repo.read(name)
+}
+
 func (repo *FakeApplicationRepository) CreatedAppParams() (params models.AppParams) {
 	if len(repo.CreateAppParams) > 0 {
 		params = repo.CreateAppParams[0]
@@ -49,6 +94,9 @@ func (repo *FakeApplicationRepository) CreatedAppParams() (params models.AppPara
 }
 
 func (repo *FakeApplicationRepository) Create(params models.AppParams) (resultApp models.Application, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	if repo.CreateAppParams == nil {
 		repo.CreateAppParams = []models.AppParams{}
 	}
@@ -86,8 +134,13 @@ func (repo *FakeApplicationRepository) Create(params models.AppParams) (resultAp
 }
 
 func (repo *FakeApplicationRepository) Update(appGuid string, params models.AppParams) (updatedApp models.Application, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.UpdateAppGuid = appGuid
 	repo.UpdateParams = params
+	repo.UpdatedAppGuids = append(repo.UpdatedAppGuids, appGuid)
+	repo.UpdatedParams = append(repo.UpdatedParams, params)
 	updatedApp = repo.UpdateAppResult
 	if repo.UpdateErr {
 		apiResponse = net.NewApiResponseWithMessage("Error updating app.")
@@ -96,6 +149,25 @@ func (repo *FakeApplicationRepository) Update(appGuid string, params models.AppP
 }
 
 func (repo *FakeApplicationRepository) Delete(appGuid string) (apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.DeletedAppGuid = appGuid
+	repo.DeletedAppGuids = append(repo.DeletedAppGuids, appGuid)
+	return
+}
+
+func (repo *FakeApplicationRepository) ReadEnv(appGuid string) (env models.Environment, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	repo.ReadEnvAppGuid = appGuid
+	env = repo.ReadEnvResult
+
+	if repo.ReadEnvNotFound {
+		apiResponse = net.NewNotFoundApiResponse("%s %s not found", "Env for app", appGuid)
+	} else if repo.ReadEnvErr {
+		apiResponse = net.NewApiResponseWithMessage("Error reading env.")
+	}
 	return
 }
@@ -21,6 +21,9 @@ type FakeDomainRepository struct {
 	FindByNameInOrgDomain      models.DomainFields
 	FindByNameInOrgApiResponse net.ApiResponse
 
+	// when set, FindByNameInOrg looks domains up by their name
+	DomainsByName map[string]models.DomainFields
+
 	FindByNameName     string
 	FindByNameDomain   models.DomainFields
 	FindByNameNotFound bool
@@ -79,6 +82,14 @@ func (repo *FakeDomainRepository) FindByNameInOrg(name string, owningOrgGuid str
 	repo.FindByNameInOrgGuid = owningOrgGuid
 	domain = repo.FindByNameInOrgDomain
 	apiResponse = repo.FindByNameInOrgApiResponse
+
+	if repo.DomainsByName != nil {
+		var found bool
+		domain, found = repo.DomainsByName[name]
+		if !found {
+			apiResponse = net.NewNotFoundApiResponse("%s %s not found", "Domain", name)
+		}
+	}
 	return
 }
 
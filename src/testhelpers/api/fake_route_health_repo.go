@@ -0,0 +1,33 @@
+package api
+
+import (
+	"time"
+)
+
+type FakeRouteHealthRepo struct {
+	ProbedUrls  []string
+	StatusCodes []int
+	ProbeErrors []error
+	Latency     time.Duration
+}
+
+func (repo *FakeRouteHealthRepo) Probe(url string, timeout time.Duration) (statusCode int, latency time.Duration, err error) {
+	repo.ProbedUrls = append(repo.ProbedUrls, url)
+	latency = repo.Latency
+
+	if len(repo.ProbeErrors) > 0 {
+		err = repo.ProbeErrors[0]
+		repo.ProbeErrors = repo.ProbeErrors[1:]
+		if err != nil {
+			return
+		}
+	}
+
+	if len(repo.StatusCodes) > 0 {
+		statusCode = repo.StatusCodes[0]
+		if len(repo.StatusCodes) > 1 {
+			repo.StatusCodes = repo.StatusCodes[1:]
+		}
+	}
+	return
+}
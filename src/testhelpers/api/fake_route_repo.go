@@ -3,6 +3,7 @@ package api
 import (
 	"cf/models"
 	"cf/net"
+	"sync"
 )
 
 type FakeRouteRepository struct {
@@ -16,8 +17,12 @@ type FakeRouteRepository struct {
 	FindByHostAndDomainErr      bool
 	FindByHostAndDomainNotFound bool
 
+	// when set, FindByHostAndDomain looks routes up by their URL
+	RoutesByURL map[string]models.Route
+
 	CreatedHost       string
 	CreatedDomainGuid string
+	CreatedHosts      []string
 
 	CreateInSpaceHost         string
 	CreateInSpaceDomainGuid   string
@@ -25,19 +30,26 @@ type FakeRouteRepository struct {
 	CreateInSpaceCreatedRoute models.Route
 	CreateInSpaceErr          bool
 
-	BoundRouteGuid string
-	BoundAppGuid   string
+	BoundRouteGuid  string
+	BoundAppGuid    string
+	BoundRouteGuids []string
 
-	UnboundRouteGuid string
-	UnboundAppGuid   string
+	UnboundRouteGuid  string
+	UnboundAppGuid    string
+	UnboundRouteGuids []string
 
 	ListErr bool
 	Routes  []models.Route
 
 	DeleteRouteGuid string
+
+	mutex sync.Mutex
 }
 
 func (repo *FakeRouteRepository) ListRoutes(cb func(models.Route) bool) (apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	if repo.ListErr {
 		return net.NewApiResponseWithMessage("WHOOPSIE")
 	}
@@ -51,6 +63,9 @@ func (repo *FakeRouteRepository) ListRoutes(cb func(models.Route) bool) (apiResp
 }
 
 func (repo *FakeRouteRepository) FindByHost(host string) (route models.Route, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.FindByHostHost = host
 
 	if repo.FindByHostErr {
@@ -62,6 +77,9 @@ func (repo *FakeRouteRepository) FindByHost(host string) (route models.Route, ap
 }
 
 func (repo *FakeRouteRepository) FindByHostAndDomain(host, domain string) (route models.Route, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.FindByHostAndDomainHost = host
 	repo.FindByHostAndDomainDomain = domain
 
@@ -74,12 +92,29 @@ func (repo *FakeRouteRepository) FindByHostAndDomain(host, domain string) (route
 	}
 
 	route = repo.FindByHostAndDomainRoute
+
+	if repo.RoutesByURL != nil {
+		url := domain
+		if host != "" {
+			url = host + "." + domain
+		}
+
+		var found bool
+		route, found = repo.RoutesByURL[url]
+		if !found {
+			apiResponse = net.NewNotFoundApiResponse("%s %s not found", "Route", url)
+		}
+	}
 	return
 }
 
 func (repo *FakeRouteRepository) Create(host, domainGuid string) (createdRoute models.Route, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.CreatedHost = host
 	repo.CreatedDomainGuid = domainGuid
+	repo.CreatedHosts = append(repo.CreatedHosts, host)
 
 	createdRoute.Guid = host + "-route-guid"
 
@@ -87,6 +122,9 @@ func (repo *FakeRouteRepository) Create(host, domainGuid string) (createdRoute m
 }
 
 func (repo *FakeRouteRepository) CreateInSpace(host, domainGuid, spaceGuid string) (createdRoute models.Route, apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.CreateInSpaceHost = host
 	repo.CreateInSpaceDomainGuid = domainGuid
 	repo.CreateInSpaceSpaceGuid = spaceGuid
@@ -101,18 +139,29 @@ func (repo *FakeRouteRepository) CreateInSpace(host, domainGuid, spaceGuid strin
 }
 
 func (repo *FakeRouteRepository) Bind(routeGuid, appGuid string) (apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.BoundRouteGuid = routeGuid
 	repo.BoundAppGuid = appGuid
+	repo.BoundRouteGuids = append(repo.BoundRouteGuids, routeGuid)
 	return
 }
 
 func (repo *FakeRouteRepository) Unbind(routeGuid, appGuid string) (apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.UnboundRouteGuid = routeGuid
 	repo.UnboundAppGuid = appGuid
+	repo.UnboundRouteGuids = append(repo.UnboundRouteGuids, routeGuid)
 	return
 }
 
 func (repo *FakeRouteRepository) Delete(routeGuid string) (apiResponse net.ApiResponse) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.DeleteRouteGuid = routeGuid
 	return
 }
@@ -32,6 +32,12 @@ type FakeServiceRepo struct {
 
 	FindInstanceByNameMap generic.Map
 
+	FindInstanceByNameInSpaceGuid string
+
+	// instances that are not found the first time they are looked up, as if
+	// they were created right after
+	MissingInstanceNames []string
+
 	DeleteServiceServiceInstance models.ServiceInstance
 
 	RenameServiceServiceInstance models.ServiceInstance
@@ -96,6 +102,11 @@ func (repo *FakeServiceRepo) CreateServiceInstance(name, planGuid string) (ident
 	return
 }
 
+func (repo *FakeServiceRepo) FindInstanceByNameInSpace(name, spaceGuid string) (instance models.ServiceInstance, apiResponse net.ApiResponse) {
+	repo.FindInstanceByNameInSpaceGuid = spaceGuid
+	return repo.FindInstanceByName(name)
+}
+
 func (repo *FakeServiceRepo) FindInstanceByName(name string) (instance models.ServiceInstance, apiResponse net.ApiResponse) {
 	repo.FindInstanceByNameName = name
 
@@ -113,6 +124,14 @@ func (repo *FakeServiceRepo) FindInstanceByName(name string) (instance models.Se
 		apiResponse = net.NewNotFoundApiResponse("%s %s not found", "Service instance", name)
 	}
 
+	for index, missingName := range repo.MissingInstanceNames {
+		if missingName == name {
+			repo.MissingInstanceNames = append(repo.MissingInstanceNames[:index], repo.MissingInstanceNames[index+1:]...)
+			apiResponse = net.NewNotFoundApiResponse("%s %s not found", "Service instance", name)
+			break
+		}
+	}
+
 	return
 }
 
@@ -1,24 +1,53 @@
 package commands
 
 import (
+	"cf/commands/application"
 	"cf/models"
+	"cf/terminal"
+	"sync"
 )
 
 type FakeAppStarter struct {
-	AppToStart models.Application
-	Timeout    int
+	AppToStart         models.Application
+	StartedAppNames    []string
+	Timeout            int
+	InstancesThreshold application.InstancesThreshold
+	HealthCheck        application.HealthCheck
+	StartError         error
+
+	mutex sync.Mutex
 }
 
 func (starter *FakeAppStarter) ApplicationStart(appToStart models.Application) (startedApp models.Application, err error) {
+	starter.mutex.Lock()
+	defer starter.mutex.Unlock()
+
 	starter.AppToStart = appToStart
+	starter.StartedAppNames = append(starter.StartedAppNames, appToStart.Name)
 	startedApp = appToStart
+	err = starter.StartError
 	return
 }
 
 func (starter *FakeAppStarter) SetStartTimeoutSeconds(timeout int) {
+	starter.mutex.Lock()
+	defer starter.mutex.Unlock()
+
 	starter.Timeout = timeout
 }
 
+func (starter *FakeAppStarter) SetInstancesThreshold(threshold application.InstancesThreshold) {
+	starter.InstancesThreshold = threshold
+}
+
+func (starter *FakeAppStarter) SetHealthCheck(check application.HealthCheck) {
+	starter.HealthCheck = check
+}
+
+func (starter *FakeAppStarter) WithUI(ui terminal.UI) application.ApplicationStarter {
+	return starter
+}
+
 func (starter *FakeAppStarter) ApplicationStartWithBuildpack(app models.Application, buildpackUrl string) (startedApp models.Application, err error) {
 	starter.AppToStart = app
 	startedApp = app
@@ -1,15 +1,27 @@
 package commands
 
 import (
+	"cf/commands/application"
 	"cf/models"
+	"cf/terminal"
+	"sync"
 )
 
 type FakeAppStopper struct {
 	AppToStop models.Application
+
+	mutex sync.Mutex
 }
 
 func (stopper *FakeAppStopper) ApplicationStop(app models.Application) (updatedApp models.Application, err error) {
+	stopper.mutex.Lock()
+	defer stopper.mutex.Unlock()
+
 	stopper.AppToStop = app
 	updatedApp = app
 	return
 }
+
+func (stopper *FakeAppStopper) WithUI(ui terminal.UI) application.ApplicationStopper {
+	return stopper
+}
@@ -6,7 +6,8 @@ import (
 
 type FakeManifestRepository struct {
 	ReadManifestArgs struct {
-		Path string
+		Paths []string
+		Vars  manifest.ManifestVars
 	}
 	ReadManifestReturns struct {
 		Manifest *manifest.Manifest
@@ -15,8 +16,9 @@ type FakeManifestRepository struct {
 	}
 }
 
-func (repo *FakeManifestRepository) ReadManifest(inputPath string) (m *manifest.Manifest, path string, errs manifest.ManifestErrors) {
-	repo.ReadManifestArgs.Path = inputPath
+func (repo *FakeManifestRepository) ReadManifest(inputPaths []string, vars manifest.ManifestVars) (m *manifest.Manifest, path string, errs manifest.ManifestErrors) {
+	repo.ReadManifestArgs.Paths = inputPaths
+	repo.ReadManifestArgs.Vars = vars
 	if repo.ReadManifestReturns.Manifest != nil {
 		m = repo.ReadManifestReturns.Manifest
 	} else {
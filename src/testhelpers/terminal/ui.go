@@ -19,6 +19,10 @@ type FakeUI struct {
 	FailedWithUsage            bool
 	FailedWithUsageCommandName string
 	ShowConfigurationCalled    bool
+	ProgressBars               []*FakeProgressBar
+	Phases                     []*FakePhaseIndicator
+	LiveViews                  []*FakeLiveView
+	Interactive                bool
 }
 
 func (ui *FakeUI) PrintPaginator(rows []string, err error) {
@@ -120,3 +124,62 @@ func (ui *FakeUI) DisplayTable(table [][]string) {
 func (ui *FakeUI) Table(headers []string) term.Table {
 	return term.NewTable(ui, headers)
 }
+
+func (ui *FakeUI) NewProgressBar() term.ProgressBar {
+	bar := &FakeProgressBar{}
+	ui.ProgressBars = append(ui.ProgressBars, bar)
+	return bar
+}
+
+func (ui *FakeUI) StartPhase(message string, args ...interface{}) term.PhaseIndicator {
+	phase := &FakePhaseIndicator{Message: fmt.Sprintf(message, args...)}
+	ui.Phases = append(ui.Phases, phase)
+	return phase
+}
+
+func (ui *FakeUI) NewLiveView() term.LiveView {
+	view := &FakeLiveView{ui: ui, Live: ui.Interactive}
+	ui.LiveViews = append(ui.LiveViews, view)
+	return view
+}
+
+type FakeProgressBar struct {
+	Current  int64
+	Total    int64
+	Finished bool
+}
+
+func (bar *FakeProgressBar) Update(current, total int64) {
+	bar.Current = current
+	bar.Total = total
+}
+
+func (bar *FakeProgressBar) Finish() {
+	bar.Finished = true
+}
+
+type FakePhaseIndicator struct {
+	Message string
+	Stopped bool
+}
+
+func (phase *FakePhaseIndicator) Stop() {
+	phase.Stopped = true
+}
+
+type FakeLiveView struct {
+	ui     *FakeUI
+	Live   bool
+	Frames [][]string
+}
+
+func (view *FakeLiveView) Redraw(lines []string) {
+	view.Frames = append(view.Frames, lines)
+	for _, line := range lines {
+		view.ui.Say("%s", line)
+	}
+}
+
+func (view *FakeLiveView) IsLive() bool {
+	return view.Live
+}